@@ -5,12 +5,20 @@ import (
 	"crypto/tls"
 	"github.com/gofiber/swagger"
 	"idm/docs"
+	"idm/inner/accessrequest"
+	"idm/inner/apikey"
+	"idm/inner/assignment"
+	"idm/inner/audit"
+	"idm/inner/certification"
 	"idm/inner/common"
 	"idm/inner/common/validator"
 	"idm/inner/database"
 	"idm/inner/employee"
 	"idm/inner/info"
+	"idm/inner/orgunit"
+	"idm/inner/permission"
 	"idm/inner/role"
+	"idm/inner/sod"
 	"idm/inner/web"
 	"os/signal"
 	"syscall"
@@ -45,7 +53,7 @@ func main() {
 	var db = database.ConnectDbWithCfg(cfg)
 
 	// 4. Собираем все компоненты приложения (ручная сборка зависимостей)
-	var server = build(db, cfg, logger)
+	var server, sweeper = build(db, cfg, logger)
 
 	// 5. Запускаем веб-сервер в отдельной горутине
 	go func() {
@@ -67,20 +75,23 @@ func main() {
 		}
 	}()
 
-	// 6. Создаем канал для ожидания сигнала завершения работы сервера
+	// 6. Запускаем фоновую очистку просроченных назначений ролей
+	sweeper.Start()
+
+	// 7. Создаем канал для ожидания сигнала завершения работы сервера
 	var shutdownComplete = make(chan struct{})
 
-	// 7. Запускаем gracefulShutdown в отдельной горутине
-	go gracefulShutdown(server, db, logger, shutdownComplete)
+	// 8. Запускаем gracefulShutdown в отдельной горутине
+	go gracefulShutdown(server, sweeper, db, logger, shutdownComplete)
 
-	// 8. Ожидаем сигнал от горутины gracefulShutdown, что сервер завершил работу
+	// 9. Ожидаем сигнал от горутины gracefulShutdown, что сервер завершил работу
 	<-shutdownComplete
 	// все события логируем через общий логгер
 	logger.Info("graceful shutdown complete")
 }
 
 // gracefulShutdown - функция "элегантного" завершения работы сервера по сигналу от операционной системы
-func gracefulShutdown(server *web.Server, db *sqlx.DB, logger *common.Logger, shutdownComplete chan struct{}) {
+func gracefulShutdown(server *web.Server, sweeper *assignment.Sweeper, db *sqlx.DB, logger *common.Logger, shutdownComplete chan struct{}) {
 	// Уведомить основную горутину о завершении работы
 	defer close(shutdownComplete)
 
@@ -99,6 +110,9 @@ func gracefulShutdown(server *web.Server, db *sqlx.DB, logger *common.Logger, sh
 		logger.Error("Server forced to shutdown with error", zap.Error(err))
 	}
 
+	// Останавливаем фоновую очистку до закрытия соединения с базой данных
+	sweeper.Stop()
+
 	// Закрываем соединение с базой данных
 	if err := db.Close(); err != nil {
 		logger.Error("error closing db", zap.Error(err))
@@ -109,10 +123,10 @@ func gracefulShutdown(server *web.Server, db *sqlx.DB, logger *common.Logger, sh
 
 // build - главная функция сборки приложения (ручная инъекция зависимостей)
 // Здесь мы создаём все компоненты и связываем их друг с другом как матрёшки
-// передаём сюда логгер и конфиг
-func build(db *sqlx.DB, cfg common.Config, logger *common.Logger) *web.Server {
+// передаём сюда логгер и конфиг; фоновую очистку назначений запускает и останавливает вызывающий код
+func build(db *sqlx.DB, cfg common.Config, logger *common.Logger) (*web.Server, *assignment.Sweeper) {
 	//  1. СОЗДАЁМ ВЕБ-СЕРВЕР (самая большая "матрёшка")
-	var server = web.NewServer(logger)
+	var server = web.NewServer(logger, web.NewAuthConfig(cfg))
 	// Убираем дублирующие middleware, так как они уже применяются в NewServer
 	server.App.Use("/swagger/*", swagger.HandlerDefault)
 
@@ -120,6 +134,22 @@ func build(db *sqlx.DB, cfg common.Config, logger *common.Logger) *web.Server {
 	// Валидатор для проверки входящих данных
 	var vld = validator.New()
 
+	// Политика доступа: если файл не задан, действует политика по умолчанию
+	if cfg.PolicyFile != "" {
+		policy, err := web.LoadPolicy(cfg.PolicyFile)
+		if err != nil {
+			logger.Panic("failed policy loading", zap.Error(err))
+		}
+		server.SetPolicy(policy)
+	}
+	server.RegisterPolicyRoutes()
+
+	// Журнал аудита: middleware подключается до регистрации маршрутов модулей,
+	// чтобы фиксировать все изменяющие запросы к API
+	var auditService = audit.NewService(audit.NewRepository(db), vld)
+	server.GroupApiV1.Use(audit.Middleware(auditService, logger))
+	audit.NewController(server, auditService, logger).RegisterRoutes()
+
 	//  3. СБОРКА МОДУЛЯ EMPLOYEE
 	// 3.1 Создаём репозиторий для работы с БД
 	var employeeRepo = employee.NewRepository(db)
@@ -146,13 +176,134 @@ func build(db *sqlx.DB, cfg common.Config, logger *common.Logger) *web.Server {
 	// 4.4 Регистрируем маршруты контроллера
 	roleController.RegisterRoutes()
 
-	// 5. СБОРКА МОДУЛЯ INFO (информация о приложении)
-	// 5.1 Создаём контроллер, передавая сервер, конфиг, БД и логгер
+	//  5. СБОРКА МОДУЛЯ ASSIGNMENT (назначение ролей сотрудникам)
+	// 5.1 Создаём репозиторий для работы с БД
+	var assignmentRepo = assignment.NewRepository(db)
+
+	// 5.2 Создаём сервис, передавая в него репозиторий и валидатор
+	var assignmentService = assignment.NewService(assignmentRepo, vld)
+
+	// 5.3 Создаём контроллер, передавая в него сервер, сервис и логгер
+	var assignmentController = assignment.NewController(server, assignmentService, logger)
+
+	// 5.4 Регистрируем маршруты контроллера
+	assignmentController.RegisterRoutes()
+
+	// 5.5 Создаём фоновую очистку просроченных назначений, которая пишет отзывы в журнал аудита
+	var sweeper = assignment.NewSweeper(assignmentService, auditService, cfg.AssignmentSweepInterval, logger)
+
+	//  6. СБОРКА МОДУЛЯ PERMISSION (права доступа ролей)
+	// 6.1 Создаём репозиторий для работы с БД
+	var permissionRepo = permission.NewRepository(db)
+
+	// 6.2 Создаём сервис, передавая в него репозиторий и валидатор
+	var permissionService = permission.NewService(permissionRepo, vld)
+
+	// 6.3 Создаём контроллер и регистрируем маршруты
+	var permissionController = permission.NewController(server, permissionService, logger)
+	permissionController.RegisterRoutes()
+
+	//  7. СБОРКА МОДУЛЯ ORGUNIT (подразделения и руководители)
+	// 7.1 Создаём репозиторий для работы с БД
+	var orgUnitRepo = orgunit.NewRepository(db)
+
+	// 7.2 Создаём сервис, передавая в него репозиторий и валидатор
+	var orgUnitService = orgunit.NewService(orgUnitRepo, vld)
+
+	// 7.3 Создаём контроллер и регистрируем маршруты
+	var orgUnitController = orgunit.NewController(server, orgUnitService, logger)
+	orgUnitController.RegisterRoutes()
+
+	//  8. СБОРКА МОДУЛЯ ACCESSREQUEST (заявки на роли и их согласование)
+	// 8.1 Создаём репозиторий для работы с БД
+	var accessRequestRepo = accessrequest.NewRepository(db)
+
+	// 8.2 Создаём сервис: одобренные заявки назначают роли через сервис назначений
+	var accessRequestService = accessrequest.NewService(accessRequestRepo, assignmentService, vld)
+
+	// 8.3 Создаём контроллер и регистрируем маршруты
+	var accessRequestController = accessrequest.NewController(server, accessRequestService, logger)
+	accessRequestController.RegisterRoutes()
+
+	//  9. СБОРКА МОДУЛЯ SOD (правила разделения обязанностей)
+	// 9.1 Создаём репозиторий для работы с БД
+	var sodRepo = sod.NewRepository(db)
+
+	// 9.2 Создаём сервис, передавая в него репозиторий и валидатор
+	var sodService = sod.NewService(sodRepo, vld)
+
+	// 9.3 Создаём контроллер и регистрируем маршруты
+	var sodController = sod.NewController(server, sodService, logger)
+	sodController.RegisterRoutes()
+
+	// 10. СБОРКА МОДУЛЯ CERTIFICATION (кампании пересмотра доступа)
+	// 10.1 Создаём репозиторий для работы с БД
+	var certificationRepo = certification.NewRepository(db)
+
+	// 10.2 Создаём сервис, передавая в него репозиторий, журнал аудита для отзываемых назначений, валидатор и логгер
+	var certificationService = certification.NewService(certificationRepo, auditService, vld, logger)
+
+	// 10.3 Создаём контроллер и регистрируем маршруты
+	var certificationController = certification.NewController(server, certificationService, logger)
+	certificationController.RegisterRoutes()
+
+	// 11. СБОРКА МОДУЛЯ APIKEY (API-ключи для межсервисных вызовов)
+	// 11.1 Создаём репозиторий для работы с БД
+	var apiKeyRepo = apikey.NewRepository(db)
+
+	// 11.2 Создаём сервис, передавая в него репозиторий и валидатор
+	// ключу можно выдать только роли, известные действующей политике доступа
+	var apiKeyService = apikey.NewService(apiKeyRepo, vld, func() []string { return server.Policy().Roles() })
+
+	// 11.3 Подключаем аутентификацию по API-ключу перед JWT
+	server.AddAuthenticator(apikey.NewAuthenticator(apiKeyService))
+
+	// 11.4 Создаём контроллер и регистрируем маршруты управления ключами
+	var apiKeyController = apikey.NewController(server, apiKeyService, logger)
+	apiKeyController.RegisterRoutes()
+
+	// 12. СНИМКИ ОБЪЕКТОВ ДЛЯ ЖУРНАЛА АУДИТА (состояние "до" изменения)
+	auditService.RegisterSnapshot("employees", func(ctx context.Context, id int64) (any, error) {
+		employeeResponse, err := employeeService.FindById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		// роли сотрудника входят в снимок, чтобы журнал показывал, какие роли были выданы или отозваны
+		roles, err := assignmentService.FindRolesByEmployee(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			employee.Response
+			Roles []assignment.RoleResponse `json:"roles"`
+		}{employeeResponse, roles}, nil
+	})
+	auditService.RegisterSnapshot("roles", func(ctx context.Context, id int64) (any, error) {
+		return roleService.FindById(ctx, id)
+	})
+	auditService.RegisterSnapshot("permissions", func(ctx context.Context, id int64) (any, error) {
+		return permissionService.FindById(ctx, id)
+	})
+	auditService.RegisterSnapshot("org-units", func(ctx context.Context, id int64) (any, error) {
+		return orgUnitService.FindById(ctx, id)
+	})
+	auditService.RegisterSnapshot("access-requests", func(ctx context.Context, id int64) (any, error) {
+		return accessRequestService.FindById(ctx, id)
+	})
+	auditService.RegisterSnapshot("sod-rules", func(ctx context.Context, id int64) (any, error) {
+		return sodService.FindById(ctx, id)
+	})
+	auditService.RegisterSnapshot("certifications", func(ctx context.Context, id int64) (any, error) {
+		return certificationService.FindById(ctx, id)
+	})
+
+	// 13. СБОРКА МОДУЛЯ INFO (информация о приложении)
+	// 13.1 Создаём контроллер, передавая сервер, конфиг, БД и логгер
 	var infoController = info.NewController(server, cfg, db, logger)
 
-	// 5.2 Регистрируем маршруты контроллера
+	// 13.2 Регистрируем маршруты контроллера
 	infoController.RegisterRoutes()
 
-	//  6. ВОЗВРАЩАЕМ СОБРАННЫЙ СЕРВЕР
-	return server
+	//  14. ВОЗВРАЩАЕМ СОБРАННЫЙ СЕРВЕР И ФОНОВУЮ ОЧИСТКУ
+	return server, sweeper
 }
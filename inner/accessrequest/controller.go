@@ -0,0 +1,275 @@
+package accessrequest
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с заявками на доступ
+type Controller struct {
+	server               *web.Server
+	accessRequestService Svc
+	logger               *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с заявками на доступ
+type Svc interface {
+	Create(ctx context.Context, actor Actor, request CreateRequest) (Response, error)              // подача заявки
+	Get(ctx context.Context, actor Actor, id int64) (Response, error)                              // заявка по ID
+	FindMine(ctx context.Context, actor Actor) ([]Response, error)                                 // заявки пользователя
+	FindPendingApprovals(ctx context.Context, actor Actor) ([]Response, error)                     // заявки, ожидающие решения
+	Approve(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error) // одобрение с назначением роли
+	Reject(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error)  // отклонение с причиной
+}
+
+// NewController создает новый экземпляр контроллера заявок на доступ
+func NewController(server *web.Server, accessRequestService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:               server,
+		accessRequestService: accessRequestService,
+		logger:               logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с заявками на доступ
+func (c *Controller) RegisterRoutes() {
+	api := c.server.GroupApiV1
+
+	// /mine и /approvals регистрируются раньше /:id
+	api.Post("/access-requests", c.server.Require(web.AccessRequestCreate), c.CreateAccessRequest)
+	api.Get("/access-requests/mine", c.server.Require(web.AccessRequestRead), c.GetMyAccessRequests)
+	api.Get("/access-requests/approvals", c.server.Require(web.AccessRequestRead), c.GetPendingApprovals)
+	api.Get("/access-requests/:id", c.server.Require(web.AccessRequestRead), c.GetAccessRequest)
+	api.Post("/access-requests/:id/approve", c.server.Require(web.AccessRequestDecide), c.ApproveAccessRequest)
+	api.Post("/access-requests/:id/reject", c.server.Require(web.AccessRequestDecide), c.RejectAccessRequest)
+}
+
+// CreateAccessRequest подает заявку на роль
+// @Summary Подать заявку на роль
+// @Description Запросить роль для себя или другого сотрудника (employee_id). Пользователь связывается с сотрудником по логину;
+// @Description заявку рассматривает руководитель автора или владелец роли, а если их нет - администратор. Запрашивать можно только роли с признаком requestable
+// @Tags access-request
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body accessrequest.CreateRequest true "данные заявки"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /access-requests [post]
+func (c *Controller) CreateAccessRequest(ctx *fiber.Ctx) error {
+	var req CreateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create access request: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.accessRequestService.Create(ctx.Context(), c.actor(ctx), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create access request: failed to create access request", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning created access request")
+	}
+	return nil
+}
+
+// GetMyAccessRequests получает заявки пользователя
+// @Summary Получить свои заявки
+// @Description Получить заявки, поданные пользователем или для него, начиная с новых
+// @Tags access-request
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /access-requests/mine [get]
+func (c *Controller) GetMyAccessRequests(ctx *fiber.Ctx) error {
+	resp, err := c.accessRequestService.FindMine(ctx.Context(), c.actor(ctx))
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get my access requests: failed to find access requests", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning access requests")
+	}
+	return nil
+}
+
+// GetPendingApprovals получает заявки, ожидающие решения пользователя
+// @Summary Получить заявки на согласование
+// @Description Получить нерассмотренные заявки, согласующим которых назначен пользователь, и заявки на роли, которыми он владеет; администратору - все нерассмотренные заявки
+// @Tags access-request
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /access-requests/approvals [get]
+func (c *Controller) GetPendingApprovals(ctx *fiber.Ctx) error {
+	resp, err := c.accessRequestService.FindPendingApprovals(ctx.Context(), c.actor(ctx))
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get pending approvals: failed to find access requests", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning pending approvals")
+	}
+	return nil
+}
+
+// GetAccessRequest получает заявку по ID
+// @Summary Получить заявку по ID
+// @Description Заявка доступна ее автору, получателю роли, согласующему, владельцу роли и администратору
+// @Tags access-request
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID заявки"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /access-requests/{id} [get]
+func (c *Controller) GetAccessRequest(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid access request id")
+	}
+
+	resp, err := c.accessRequestService.Get(ctx.Context(), c.actor(ctx), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get access request: failed to find access request", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning access request")
+	}
+	return nil
+}
+
+// ApproveAccessRequest одобряет заявку и назначает роль
+// @Summary Одобрить заявку
+// @Description Одобрить заявку и назначить роль получателю; если назначение не удалось, заявка остается на рассмотрении.
+// @Description Решение принимает согласующий, владелец роли или администратор, но не автор заявки и не получатель роли
+// @Tags access-request
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID заявки"
+// @Param request body accessrequest.DecisionRequest false "комментарий к решению"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /access-requests/{id}/approve [post]
+func (c *Controller) ApproveAccessRequest(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid access request id")
+	}
+
+	var req DecisionRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			c.logger.ErrorCtx(ctx.Context(), "approve access request: invalid JSON", zap.Error(err))
+			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	resp, err := c.accessRequestService.Approve(ctx.Context(), c.actor(ctx), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "approve access request: failed to approve access request", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning approved access request")
+	}
+	return nil
+}
+
+// RejectAccessRequest отклоняет заявку
+// @Summary Отклонить заявку
+// @Description Отклонить заявку с обязательным указанием причины
+// @Tags access-request
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID заявки"
+// @Param request body accessrequest.DecisionRequest true "причина отклонения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /access-requests/{id}/reject [post]
+func (c *Controller) RejectAccessRequest(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid access request id")
+	}
+
+	var req DecisionRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			c.logger.ErrorCtx(ctx.Context(), "reject access request: invalid JSON", zap.Error(err))
+			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	resp, err := c.accessRequestService.Reject(ctx.Context(), c.actor(ctx), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "reject access request: failed to reject access request", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning rejected access request")
+	}
+	return nil
+}
+
+// actor определяет пользователя запроса по claims токена
+func (c *Controller) actor(ctx *fiber.Ctx) Actor {
+	actor := Actor{Admin: c.server.Allowed(ctx, web.AccessRequestManage)}
+	if claims, err := web.GetClaims(ctx); err == nil {
+		actor.Login = claims.PreferredUsername
+	}
+	return actor
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.ForbiddenError{}):
+		return common.ErrResponse(ctx, fiber.StatusForbidden, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	case errors.As(err, &common.AlreadyExistsError{}), errors.As(err, &common.ConflictError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
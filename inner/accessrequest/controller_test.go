@@ -0,0 +1,204 @@
+package accessrequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccessRequestService - полный мок для интерфейса Svc
+type MockAccessRequestService struct {
+	mock.Mock
+}
+
+func (m *MockAccessRequestService) Create(ctx context.Context, actor Actor, request CreateRequest) (Response, error) {
+	args := m.Called(ctx, actor, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockAccessRequestService) Get(ctx context.Context, actor Actor, id int64) (Response, error) {
+	args := m.Called(ctx, actor, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockAccessRequestService) FindMine(ctx context.Context, actor Actor) ([]Response, error) {
+	args := m.Called(ctx, actor)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockAccessRequestService) FindPendingApprovals(ctx context.Context, actor Actor) ([]Response, error) {
+	args := m.Called(ctx, actor)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockAccessRequestService) Approve(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error) {
+	args := m.Called(ctx, actor, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockAccessRequestService) Reject(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error) {
+	args := m.Called(ctx, actor, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func setupTest(t *testing.T) (*fiber.App, *MockAccessRequestService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockAccessRequestService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestCreateAccessRequest(t *testing.T) {
+	t.Run("should create request for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := CreateRequest{RoleId: 5, Justification: "нужен доступ"}
+		svc.On("Create", mock.Anything, Actor{}, request).Return(Response{Id: 10, RoleId: ptr(5), Status: StatusPending}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests", request, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 when user is not linked to employee", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Create", mock.Anything, Actor{}, mock.Anything).
+			Return(Response{}, common.ForbiddenError{Message: "no employee is linked to user"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests", CreateRequest{RoleId: 5}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should return 409 for duplicate pending request", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Create", mock.Anything, Actor{}, mock.Anything).
+			Return(Response{}, common.AlreadyExistsError{Message: "a pending request already exists"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests", CreateRequest{RoleId: 5}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestGetPendingApprovals(t *testing.T) {
+	t.Run("should pass admin flag for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindPendingApprovals", mock.Anything, Actor{Admin: true}).Return([]Response{{Id: 10}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/access-requests/approvals", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should not pass admin flag for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindPendingApprovals", mock.Anything, Actor{}).Return([]Response{}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/access-requests/approvals", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 401 without token", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/access-requests/approvals", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestApproveAccessRequest(t *testing.T) {
+	t.Run("should approve without body", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Approve", mock.Anything, Actor{}, int64(10), DecisionRequest{}).Return(Response{Id: 10, Status: StatusApproved}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests/10/approve", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 for non-approver", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Approve", mock.Anything, Actor{}, int64(10), DecisionRequest{}).
+			Return(Response{}, common.ForbiddenError{Message: "you are not an approver of access request 10"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests/10/approve", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for invalid id", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests/abc/approve", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestRejectAccessRequest(t *testing.T) {
+	t.Run("should reject with reason", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := DecisionRequest{Reason: "нет обоснования"}
+		svc.On("Reject", mock.Anything, Actor{}, int64(10), request).Return(Response{Id: 10, Status: StatusRejected}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests/10/reject", request, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 409 for decided request", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Reject", mock.Anything, Actor{}, int64(10), mock.Anything).
+			Return(Response{}, common.ConflictError{Message: "access request 10 is already approved"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/access-requests/10/reject", DecisionRequest{Reason: "поздно"}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
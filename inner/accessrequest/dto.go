@@ -0,0 +1,89 @@
+package accessrequest
+
+import "time"
+
+// Статусы заявки на доступ
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Entity представляет заявку на назначение роли в базе данных.
+// RequesterId, EmployeeId и RoleId равны nil, если сотрудник или роль окончательно удалены
+type Entity struct {
+	Id             int64      `db:"id"`
+	RequesterId    *int64     `db:"requester_id"`
+	EmployeeId     *int64     `db:"employee_id"`
+	RoleId         *int64     `db:"role_id"`
+	Justification  string     `db:"justification"`
+	ValidUntil     *time.Time `db:"valid_until"`
+	ApproverId     *int64     `db:"approver_id"`
+	Status         string     `db:"status"`
+	DecidedBy      string     `db:"decided_by"`
+	DecisionReason string     `db:"decision_reason"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DecidedAt      *time.Time `db:"decided_at"`
+}
+
+// toResponse преобразует Entity в Response
+func (e *Entity) toResponse() Response {
+	return Response{
+		Id:             e.Id,
+		RequesterId:    e.RequesterId,
+		EmployeeId:     e.EmployeeId,
+		RoleId:         e.RoleId,
+		Justification:  e.Justification,
+		ValidUntil:     e.ValidUntil,
+		ApproverId:     e.ApproverId,
+		Status:         e.Status,
+		DecidedBy:      e.DecidedBy,
+		DecisionReason: e.DecisionReason,
+		CreatedAt:      e.CreatedAt,
+		DecidedAt:      e.DecidedAt,
+	}
+}
+
+// isParticipant проверяет, подал ли сотрудник заявку или запрошена ли роль для него
+func (e *Entity) isParticipant(employeeId int64) bool {
+	return isEmployee(e.RequesterId, employeeId) || isEmployee(e.EmployeeId, employeeId)
+}
+
+// EmployeeEntity представляет сотрудника-участника заявки
+type EmployeeEntity struct {
+	Id        int64  `db:"id"`
+	ManagerId *int64 `db:"manager_id"`
+	Status    string `db:"status"`
+}
+
+// RoleEntity представляет запрашиваемую роль; владелец роли вправе рассматривать заявки на нее
+type RoleEntity struct {
+	Id          int64  `db:"id"`
+	Requestable bool   `db:"requestable"`
+	OwnerId     *int64 `db:"owner_id"`
+}
+
+// Response представляет ответ API для заявки на доступ
+type Response struct {
+	Id             int64      `json:"id"`
+	RequesterId    *int64     `json:"requester_id"`
+	EmployeeId     *int64     `json:"employee_id"`
+	RoleId         *int64     `json:"role_id"`
+	Justification  string     `json:"justification"`
+	ValidUntil     *time.Time `json:"valid_until,omitempty"`
+	ApproverId     *int64     `json:"approver_id,omitempty"`
+	Status         string     `json:"status"`
+	DecidedBy      string     `json:"decided_by,omitempty"`
+	DecisionReason string     `json:"decision_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+}
+
+// toResponses преобразует список Entity в список Response
+func toResponses(entities []Entity) []Response {
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses
+}
@@ -0,0 +1,94 @@
+package accessrequest
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository представляет репозиторий для работы с заявками на доступ
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// FindEmployeeByLogin возвращает неудаленного сотрудника по логину без учета регистра
+func (r *Repository) FindEmployeeByLogin(ctx context.Context, login string) (res EmployeeEntity, err error) {
+	query := `SELECT id, manager_id, status FROM employee
+		WHERE lower(login) = lower($1) AND login <> '' AND deleted_at IS NULL`
+	err = r.db.GetContext(ctx, &res, query, login)
+	return res, err
+}
+
+// FindEmployee возвращает неудаленного сотрудника по ID
+func (r *Repository) FindEmployee(ctx context.Context, id int64) (res EmployeeEntity, err error) {
+	query := `SELECT id, manager_id, status FROM employee WHERE id = $1 AND deleted_at IS NULL`
+	err = r.db.GetContext(ctx, &res, query, id)
+	return res, err
+}
+
+// FindRole возвращает неудаленную роль по ID
+func (r *Repository) FindRole(ctx context.Context, roleId int64) (res RoleEntity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT id, requestable, owner_id FROM role WHERE id = $1 AND deleted_at IS NULL", roleId)
+	return res, err
+}
+
+// Add сохраняет новую заявку
+func (r *Repository) Add(ctx context.Context, e *Entity) error {
+	query := `INSERT INTO access_request (requester_id, employee_id, role_id, justification, valid_until, approver_id, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, e.RequesterId, e.EmployeeId, e.RoleId, e.Justification,
+		e.ValidUntil, e.ApproverId, e.Status, e.CreatedAt).Scan(&e.Id)
+}
+
+// FindById возвращает заявку по ID
+func (r *Repository) FindById(ctx context.Context, id int64) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT * FROM access_request WHERE id = $1", id)
+	return res, err
+}
+
+// FindByParticipant возвращает заявки, поданные сотрудником или для него, начиная с новых
+func (r *Repository) FindByParticipant(ctx context.Context, employeeId int64) (res []Entity, err error) {
+	query := `SELECT * FROM access_request WHERE requester_id = $1 OR employee_id = $1 ORDER BY created_at DESC, id DESC`
+	err = r.db.SelectContext(ctx, &res, query, employeeId)
+	return res, err
+}
+
+// FindPending возвращает нерассмотренные заявки согласующего и заявки на роли, которыми он владеет, начиная со старых.
+// Без approverId возвращаются все нерассмотренные заявки
+func (r *Repository) FindPending(ctx context.Context, approverId *int64) (res []Entity, err error) {
+	query := `SELECT * FROM access_request WHERE status = 'pending' AND ($1::BIGINT IS NULL OR approver_id = $1
+			OR role_id IN (SELECT id FROM role WHERE owner_id = $1 AND deleted_at IS NULL))
+		ORDER BY created_at, id`
+	err = r.db.SelectContext(ctx, &res, query, approverId)
+	return res, err
+}
+
+// Decide сохраняет решение по заявке, если она еще не рассмотрена.
+// Возвращает sql.ErrNoRows, если заявка не найдена или решение по ней уже принято
+func (r *Repository) Decide(ctx context.Context, e *Entity) error {
+	query := `UPDATE access_request SET status = $2, decided_by = $3, decision_reason = $4, decided_at = $5
+		WHERE id = $1 AND status = 'pending' RETURNING *`
+	return r.db.GetContext(ctx, e, query, e.Id, e.Status, e.DecidedBy, e.DecisionReason, e.DecidedAt)
+}
+
+// Reopen возвращает одобренную заявку в ожидание, если назначить роль по ней не удалось
+func (r *Repository) Reopen(ctx context.Context, id int64) error {
+	query := `UPDATE access_request SET status = 'pending', decided_by = '', decision_reason = '', decided_at = NULL
+		WHERE id = $1 AND status = 'approved'`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Fail отклоняет одобренную заявку, если назначить роль по ней не удалось, а вернуть ее в ожидание нельзя
+func (r *Repository) Fail(ctx context.Context, id int64, reason string, at time.Time) error {
+	query := `UPDATE access_request SET status = 'rejected', decision_reason = $2, decided_at = $3
+		WHERE id = $1 AND status = 'approved'`
+	_, err := r.db.ExecContext(ctx, query, id, reason, at)
+	return err
+}
@@ -0,0 +1,22 @@
+package accessrequest
+
+import "time"
+
+// Actor - пользователь, от имени которого выполняется операция с заявками
+type Actor struct {
+	Login string // preferred_username из токена; совпадает с логином сотрудника
+	Admin bool   // может просматривать и рассматривать любые заявки
+}
+
+// CreateRequest используется для подачи заявки на роль. Без employee_id роль запрашивается для себя
+type CreateRequest struct {
+	EmployeeId    *int64     `json:"employee_id" validate:"omitempty,gt=0"`
+	RoleId        int64      `json:"role_id" validate:"required,gt=0"`
+	Justification string     `json:"justification" validate:"max=1000"`
+	ValidUntil    *time.Time `json:"valid_until"`
+}
+
+// DecisionRequest используется при одобрении и отклонении заявки; при отклонении причина обязательна
+type DecisionRequest struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
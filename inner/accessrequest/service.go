@@ -0,0 +1,386 @@
+package accessrequest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"idm/inner/assignment"
+	"idm/inner/common"
+	"idm/inner/employee"
+	"strings"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику заявок на доступ
+type Service struct {
+	repo      Repo
+	assigner  Assigner
+	validator Validator
+}
+
+// Repo интерфейс репозитория для заявок на доступ
+type Repo interface {
+	FindEmployeeByLogin(ctx context.Context, login string) (EmployeeEntity, error)
+	FindEmployee(ctx context.Context, id int64) (EmployeeEntity, error)
+	FindRole(ctx context.Context, roleId int64) (RoleEntity, error)
+	Add(ctx context.Context, e *Entity) error
+	FindById(ctx context.Context, id int64) (Entity, error)
+	FindByParticipant(ctx context.Context, employeeId int64) ([]Entity, error)
+	FindPending(ctx context.Context, approverId *int64) ([]Entity, error)
+	Decide(ctx context.Context, e *Entity) error
+	Reopen(ctx context.Context, id int64) error
+	Fail(ctx context.Context, id int64, reason string, at time.Time) error
+}
+
+// Assigner назначает роли по одобренным заявкам. Назначение выполняется тем же сервисом,
+// что и прямое назначение, поэтому на заявки распространяются все его проверки
+type Assigner interface {
+	AssignRoles(ctx context.Context, employeeId int64, request assignment.AssignRolesRequest) ([]assignment.RoleResponse, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, assigner Assigner, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		assigner:  assigner,
+		validator: validator,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	if err := svc.validator.ValidateWithCustomMessages(request); err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Create подает заявку на роль для себя или другого сотрудника.
+// Заявку рассматривает руководитель автора заявки или владелец роли, а если их нет - администратор
+func (svc *Service) Create(ctx context.Context, actor Actor, request CreateRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	now := time.Now()
+	if request.ValidUntil != nil && !request.ValidUntil.After(now) {
+		return Response{}, common.RequestValidationError{Message: "valid_until must be in the future"}
+	}
+
+	requester, err := svc.requireEmployee(ctx, actor)
+	if err != nil {
+		return Response{}, err
+	}
+	beneficiary := requester
+	if request.EmployeeId != nil && *request.EmployeeId != requester.Id {
+		if beneficiary, err = svc.findEmployee(ctx, *request.EmployeeId); err != nil {
+			return Response{}, err
+		}
+	}
+	if beneficiary.Status == employee.StatusTerminated {
+		return Response{}, common.ConflictError{Message: fmt.Sprintf("employee %d is terminated and cannot be assigned roles", beneficiary.Id)}
+	}
+	if err := svc.checkRole(ctx, request.RoleId); err != nil {
+		return Response{}, err
+	}
+
+	entity := &Entity{
+		RequesterId:   &requester.Id,
+		EmployeeId:    &beneficiary.Id,
+		RoleId:        &request.RoleId,
+		Justification: strings.TrimSpace(request.Justification),
+		ValidUntil:    request.ValidUntil,
+		ApproverId:    requester.ManagerId,
+		Status:        StatusPending,
+		CreatedAt:     now,
+	}
+	err = svc.repo.Add(ctx, entity)
+	if common.IsUniqueViolation(err) {
+		return Response{}, common.AlreadyExistsError{
+			Message: fmt.Sprintf("a pending request for role %d already exists for employee %d", request.RoleId, beneficiary.Id),
+		}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: "error adding access request", Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindById возвращает заявку по ID без проверки доступа к ней
+func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
+	entity, err := svc.findById(ctx, id)
+	if err != nil {
+		return Response{}, err
+	}
+	return entity.toResponse(), nil
+}
+
+// Get возвращает заявку, если пользователь - ее автор, получатель роли, согласующий или администратор
+func (svc *Service) Get(ctx context.Context, actor Actor, id int64) (Response, error) {
+	entity, err := svc.findById(ctx, id)
+	if err != nil {
+		return Response{}, err
+	}
+	if actor.Admin {
+		return entity.toResponse(), nil
+	}
+
+	me, err := svc.requireEmployee(ctx, actor)
+	if err != nil {
+		return Response{}, err
+	}
+	if entity.isParticipant(me.Id) {
+		return entity.toResponse(), nil
+	}
+	approver, err := svc.isApprover(ctx, entity, me.Id)
+	if err != nil {
+		return Response{}, err
+	}
+	if !approver {
+		return Response{}, common.ForbiddenError{Message: fmt.Sprintf("access request %d is not available to you", id)}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindMine возвращает заявки, поданные пользователем или для него
+func (svc *Service) FindMine(ctx context.Context, actor Actor) ([]Response, error) {
+	me, err := svc.requireEmployee(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindByParticipant(ctx, me.Id)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding access requests of employee %d", me.Id), Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// FindPendingApprovals возвращает заявки, ожидающие решения пользователя как руководителя автора или владельца роли.
+// Администратору возвращаются все нерассмотренные заявки
+func (svc *Service) FindPendingApprovals(ctx context.Context, actor Actor) ([]Response, error) {
+	var approverId *int64
+	if !actor.Admin {
+		me, err := svc.requireEmployee(ctx, actor)
+		if err != nil {
+			return nil, err
+		}
+		approverId = &me.Id
+	}
+
+	entities, err := svc.repo.FindPending(ctx, approverId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding pending access requests", Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// Approve одобряет заявку и назначает роль. Если назначить роль не удалось,
+// заявка возвращается в ожидание, чтобы ее можно было рассмотреть повторно, и возвращается ошибка назначения
+func (svc *Service) Approve(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+
+	// решение фиксируется до назначения роли: параллельное отклонение той же заявки не должно оставить роль назначенной
+	entity, err := svc.decide(ctx, actor, id, StatusApproved, strings.TrimSpace(request.Reason))
+	if err != nil {
+		return Response{}, err
+	}
+
+	_, err = svc.assigner.AssignRoles(ctx, *entity.EmployeeId, assignment.AssignRolesRequest{
+		RoleIds:    []int64{*entity.RoleId},
+		ValidUntil: entity.ValidUntil,
+	})
+	if err != nil {
+		if reopenErr := svc.reopen(ctx, entity.Id, err); reopenErr != nil {
+			return Response{}, reopenErr
+		}
+		return Response{}, err
+	}
+	return entity.toResponse(), nil
+}
+
+// reopen возвращает заявку в ожидание после неудачного назначения роли. Если за это время на ту же роль
+// подана новая заявка, вторая нерассмотренная заявка недопустима: заявка отклоняется с ошибкой назначения в качестве причины
+func (svc *Service) reopen(ctx context.Context, id int64, assignErr error) error {
+	err := svc.repo.Reopen(ctx, id)
+	if common.IsUniqueViolation(err) {
+		err = svc.repo.Fail(ctx, id, "role assignment failed: "+assignErr.Error(), time.Now())
+	}
+	if err != nil {
+		return common.RepositoryError{
+			Message: fmt.Sprintf("error reopening access request %d after failed assignment: %v", id, assignErr),
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// Reject отклоняет заявку с обязательным указанием причины
+func (svc *Service) Reject(ctx context.Context, actor Actor, id int64, request DecisionRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return Response{}, common.RequestValidationError{Message: "reason is required to reject an access request"}
+	}
+
+	entity, err := svc.decide(ctx, actor, id, StatusRejected, reason)
+	if err != nil {
+		return Response{}, err
+	}
+	return entity.toResponse(), nil
+}
+
+// decide проверяет право пользователя принять решение и сохраняет его
+func (svc *Service) decide(ctx context.Context, actor Actor, id int64, status, reason string) (Entity, error) {
+	entity, err := svc.findById(ctx, id)
+	if err != nil {
+		return Entity{}, err
+	}
+	if entity.Status != StatusPending {
+		return Entity{}, common.ConflictError{Message: fmt.Sprintf("access request %d is already %s", id, entity.Status)}
+	}
+	if status == StatusApproved && (entity.EmployeeId == nil || entity.RoleId == nil) {
+		return Entity{}, common.ConflictError{Message: fmt.Sprintf("employee or role of access request %d has been purged", id)}
+	}
+	if err := svc.checkApprover(ctx, actor, entity); err != nil {
+		return Entity{}, err
+	}
+
+	now := time.Now()
+	entity.Status = status
+	entity.DecidedBy = actor.Login
+	entity.DecisionReason = reason
+	entity.DecidedAt = &now
+	err = svc.repo.Decide(ctx, &entity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entity{}, common.ConflictError{Message: fmt.Sprintf("access request %d was decided by another request", id)}
+	}
+	if err != nil {
+		return Entity{}, common.RepositoryError{Message: fmt.Sprintf("error deciding access request %d", id), Err: err}
+	}
+	return entity, nil
+}
+
+// checkApprover проверяет, что пользователь - согласующий заявки, владелец роли или администратор.
+// Решение по заявке, поданной пользователем или для него, не может принять никто из них, включая администратора
+func (svc *Service) checkApprover(ctx context.Context, actor Actor, entity Entity) error {
+	me, found, err := svc.findActor(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if found && entity.isParticipant(me.Id) {
+		return common.ForbiddenError{Message: fmt.Sprintf("access request %d cannot be decided by its requester or beneficiary", entity.Id)}
+	}
+	if actor.Admin {
+		return nil
+	}
+	if found {
+		approver, err := svc.isApprover(ctx, entity, me.Id)
+		if err != nil || approver {
+			return err
+		}
+	}
+	return common.ForbiddenError{Message: fmt.Sprintf("you are not an approver of access request %d", entity.Id)}
+}
+
+// isApprover проверяет, может ли сотрудник рассматривать заявку: он назначен ее согласующим или владеет ролью.
+// Владелец берется из роли на момент проверки, поэтому смена владельца сразу передает ему нерассмотренные заявки
+func (svc *Service) isApprover(ctx context.Context, entity Entity, employeeId int64) (bool, error) {
+	if isEmployee(entity.ApproverId, employeeId) {
+		return true, nil
+	}
+	if entity.RoleId == nil {
+		return false, nil
+	}
+	role, err := svc.repo.FindRole(ctx, *entity.RoleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, common.RepositoryError{Message: fmt.Sprintf("error finding role with id %d", *entity.RoleId), Err: err}
+	}
+	return isEmployee(role.OwnerId, employeeId), nil
+}
+
+// isEmployee проверяет, ссылается ли id на сотрудника employeeId; nil - ссылка на удаленного сотрудника или ее отсутствие
+func isEmployee(id *int64, employeeId int64) bool {
+	return id != nil && *id == employeeId
+}
+
+// findActor ищет сотрудника, логин которого совпадает с логином пользователя
+func (svc *Service) findActor(ctx context.Context, actor Actor) (EmployeeEntity, bool, error) {
+	if actor.Login == "" {
+		return EmployeeEntity{}, false, nil
+	}
+	entity, err := svc.repo.FindEmployeeByLogin(ctx, actor.Login)
+	if errors.Is(err, sql.ErrNoRows) {
+		return EmployeeEntity{}, false, nil
+	}
+	if err != nil {
+		return EmployeeEntity{}, false, common.RepositoryError{Message: fmt.Sprintf("error finding employee with login %q", actor.Login), Err: err}
+	}
+	return entity, true, nil
+}
+
+// requireEmployee возвращает сотрудника пользователя; без него работать с заявками от своего имени нельзя
+func (svc *Service) requireEmployee(ctx context.Context, actor Actor) (EmployeeEntity, error) {
+	me, found, err := svc.findActor(ctx, actor)
+	if err != nil {
+		return EmployeeEntity{}, err
+	}
+	if !found {
+		return EmployeeEntity{}, common.ForbiddenError{Message: fmt.Sprintf("no employee is linked to user %q", actor.Login)}
+	}
+	return me, nil
+}
+
+// findEmployee возвращает сотрудника по ID
+func (svc *Service) findEmployee(ctx context.Context, id int64) (EmployeeEntity, error) {
+	entity, err := svc.repo.FindEmployee(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return EmployeeEntity{}, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
+	if err != nil {
+		return EmployeeEntity{}, common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", id), Err: err}
+	}
+	return entity, nil
+}
+
+// checkRole проверяет наличие роли и то, что ее можно запрашивать через заявки
+func (svc *Service) checkRole(ctx context.Context, roleId int64) error {
+	role, err := svc.repo.FindRole(ctx, roleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", roleId)}
+	}
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding role with id %d", roleId), Err: err}
+	}
+	if !role.Requestable {
+		return common.ForbiddenError{Message: fmt.Sprintf("role with id %d is not requestable; it can only be assigned by an administrator", roleId)}
+	}
+	return nil
+}
+
+// findById возвращает заявку по ID
+func (svc *Service) findById(ctx context.Context, id int64) (Entity, error) {
+	if id <= 0 {
+		return Entity{}, common.RequestValidationError{Message: fmt.Sprintf("invalid access request id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entity{}, common.NotFoundError{Message: fmt.Sprintf("access request with id %d not found", id)}
+	}
+	if err != nil {
+		return Entity{}, common.RepositoryError{Message: fmt.Sprintf("error finding access request with id %d", id), Err: err}
+	}
+	return entity, nil
+}
@@ -0,0 +1,465 @@
+package accessrequest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/assignment"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"idm/inner/employee"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория заявок на доступ
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) FindEmployeeByLogin(ctx context.Context, login string) (EmployeeEntity, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(EmployeeEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindEmployee(ctx context.Context, id int64) (EmployeeEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(EmployeeEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindRole(ctx context.Context, roleId int64) (RoleEntity, error) {
+	args := m.Called(ctx, roleId)
+	return args.Get(0).(RoleEntity), args.Error(1)
+}
+
+func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindById(ctx context.Context, id int64) (Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindByParticipant(ctx context.Context, employeeId int64) ([]Entity, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindPending(ctx context.Context, approverId *int64) ([]Entity, error) {
+	args := m.Called(ctx, approverId)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Decide(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) Reopen(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepo) Fail(ctx context.Context, id int64, reason string, at time.Time) error {
+	args := m.Called(ctx, id, reason, at)
+	return args.Error(0)
+}
+
+// MockAssigner - mock-объект сервиса назначения ролей
+type MockAssigner struct {
+	mock.Mock
+}
+
+func (m *MockAssigner) AssignRoles(ctx context.Context, employeeId int64, request assignment.AssignRolesRequest) ([]assignment.RoleResponse, error) {
+	args := m.Called(ctx, employeeId, request)
+	return args.Get(0).([]assignment.RoleResponse), args.Error(1)
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+// сотрудники для тестов: jdoe (id 1) подчиняется boss (id 2), owner (id 8) владеет ролью 5
+var (
+	jdoe  = EmployeeEntity{Id: 1, ManagerId: ptr(2), Status: employee.StatusActive}
+	boss  = EmployeeEntity{Id: 2, Status: employee.StatusActive}
+	owner = EmployeeEntity{Id: 8, Status: employee.StatusActive}
+)
+
+func pendingRequest() Entity {
+	return Entity{Id: 10, RequesterId: ptr(1), EmployeeId: ptr(1), RoleId: ptr(5), ApproverId: ptr(2), Status: StatusPending}
+}
+
+func TestAccessRequestService_Create(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should create request for self with manager as approver", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true}, nil)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*accessrequest.Entity")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*Entity).Id = 10
+		})
+
+		got, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{RoleId: 5, Justification: " нужен доступ "})
+
+		a.Nil(err)
+		a.Equal(int64(10), got.Id)
+		a.Equal(int64(1), *got.RequesterId)
+		a.Equal(int64(1), *got.EmployeeId)
+		a.Equal(int64(2), *got.ApproverId)
+		a.Equal(StatusPending, got.Status)
+		a.Equal("нужен доступ", got.Justification)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should create request for another employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindEmployee", mock.Anything, int64(3)).Return(EmployeeEntity{Id: 3, Status: employee.StatusActive}, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true}, nil)
+		repo.On("Add", mock.Anything, mock.Anything).Return(nil)
+
+		got, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{EmployeeId: ptr(3), RoleId: 5})
+
+		a.Nil(err)
+		a.Equal(int64(1), *got.RequesterId)
+		a.Equal(int64(3), *got.EmployeeId)
+	})
+
+	t.Run("should return forbidden when user is not linked to employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "ghost").Return(EmployeeEntity{}, sql.ErrNoRows)
+
+		_, err := svc.Create(context.Background(), Actor{Login: "ghost"}, CreateRequest{RoleId: 5})
+
+		a.True(errors.As(err, &common.ForbiddenError{}))
+		repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return conflict for terminated beneficiary", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindEmployee", mock.Anything, int64(3)).Return(EmployeeEntity{Id: 3, Status: employee.StatusTerminated}, nil)
+
+		_, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{EmployeeId: ptr(3), RoleId: 5})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{}, sql.ErrNoRows)
+
+		_, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{RoleId: 5})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should return forbidden for role that is not requestable", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5}, nil)
+
+		_, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{RoleId: 5})
+
+		a.True(errors.As(err, &common.ForbiddenError{}))
+		repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return already exists for duplicate pending request", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true}, nil)
+		repo.On("Add", mock.Anything, mock.Anything).Return(&pq.Error{Code: "23505"})
+
+		_, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{RoleId: 5})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+	})
+
+	t.Run("should reject expired valid_until", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		past := time.Now().Add(-time.Hour)
+
+		_, err := svc.Create(context.Background(), Actor{Login: "jdoe"}, CreateRequest{RoleId: 5, ValidUntil: &past})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "FindEmployeeByLogin", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccessRequestService_FindPendingApprovals(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return requests awaiting the manager", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("FindPending", mock.Anything, ptr(2)).Return([]Entity{pendingRequest()}, nil)
+
+		got, err := svc.FindPendingApprovals(context.Background(), Actor{Login: "boss"})
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(int64(10), got[0].Id)
+	})
+
+	t.Run("should return all pending requests to admin", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindPending", mock.Anything, (*int64)(nil)).Return([]Entity{}, nil)
+
+		got, err := svc.FindPendingApprovals(context.Background(), Actor{Login: "admin", Admin: true})
+
+		a.Nil(err)
+		a.Empty(got)
+		repo.AssertNotCalled(t, "FindEmployeeByLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return requests for roles owned by the employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindEmployeeByLogin", mock.Anything, "owner").Return(owner, nil)
+		repo.On("FindPending", mock.Anything, ptr(8)).Return([]Entity{pendingRequest()}, nil)
+
+		got, err := svc.FindPendingApprovals(context.Background(), Actor{Login: "owner"})
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(int64(5), *got[0].RoleId)
+	})
+}
+
+func TestAccessRequestService_Get(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return request to approver", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+
+		got, err := svc.Get(context.Background(), Actor{Login: "boss"}, 10)
+
+		a.Nil(err)
+		a.Equal(int64(10), got.Id)
+	})
+
+	t.Run("should return forbidden to unrelated employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "other").Return(EmployeeEntity{Id: 7}, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true, OwnerId: ptr(8)}, nil)
+
+		_, err := svc.Get(context.Background(), Actor{Login: "other"}, 10)
+
+		a.True(errors.As(err, &common.ForbiddenError{}))
+	})
+}
+
+func TestAccessRequestService_Approve(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should approve and assign role", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("Decide", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Status == StatusApproved && e.DecidedBy == "boss" && e.DecidedAt != nil
+		})).Return(nil)
+		assigner.On("AssignRoles", mock.Anything, int64(1), assignment.AssignRolesRequest{RoleIds: []int64{5}}).
+			Return([]assignment.RoleResponse{{Id: 5}}, nil)
+
+		got, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.Nil(err)
+		a.Equal(StatusApproved, got.Status)
+		repo.AssertExpectations(t)
+		assigner.AssertExpectations(t)
+	})
+
+	t.Run("should approve as role owner", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "owner").Return(owner, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true, OwnerId: ptr(8)}, nil)
+		repo.On("Decide", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Status == StatusApproved && e.DecidedBy == "owner"
+		})).Return(nil)
+		assigner.On("AssignRoles", mock.Anything, int64(1), assignment.AssignRolesRequest{RoleIds: []int64{5}}).
+			Return([]assignment.RoleResponse{{Id: 5}}, nil)
+
+		got, err := svc.Approve(context.Background(), Actor{Login: "owner"}, 10, DecisionRequest{})
+
+		a.Nil(err)
+		a.Equal(StatusApproved, got.Status)
+		repo.AssertExpectations(t)
+		assigner.AssertExpectations(t)
+	})
+
+	t.Run("should allow admin to approve request without approver", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		request := pendingRequest()
+		request.ApproverId = nil
+		repo.On("FindById", mock.Anything, int64(10)).Return(request, nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "admin").Return(EmployeeEntity{}, sql.ErrNoRows)
+		repo.On("Decide", mock.Anything, mock.Anything).Return(nil)
+		assigner.On("AssignRoles", mock.Anything, int64(1), mock.Anything).Return([]assignment.RoleResponse{}, nil)
+
+		got, err := svc.Approve(context.Background(), Actor{Login: "admin", Admin: true}, 10, DecisionRequest{})
+
+		a.Nil(err)
+		a.Equal(StatusApproved, got.Status)
+	})
+
+	t.Run("should forbid approval by requester even for admin", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "jdoe").Return(jdoe, nil)
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "jdoe", Admin: true}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ForbiddenError{}))
+		repo.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should forbid approval by employee who is not an approver", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "other").Return(EmployeeEntity{Id: 7}, nil)
+		repo.On("FindRole", mock.Anything, int64(5)).Return(RoleEntity{Id: 5, Requestable: true, OwnerId: ptr(8)}, nil)
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "other"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ForbiddenError{}))
+	})
+
+	t.Run("should return conflict for decided request", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		request := pendingRequest()
+		request.Status = StatusRejected
+		repo.On("FindById", mock.Anything, int64(10)).Return(request, nil)
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should return conflict when beneficiary was purged", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		request := pendingRequest()
+		request.EmployeeId = nil
+		repo.On("FindById", mock.Anything, int64(10)).Return(request, nil)
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		repo.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return conflict when decided concurrently", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("Decide", mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should reopen request when assignment fails", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("Decide", mock.Anything, mock.Anything).Return(nil)
+		repo.On("Reopen", mock.Anything, int64(10)).Return(nil)
+		assigner.On("AssignRoles", mock.Anything, int64(1), mock.Anything).
+			Return([]assignment.RoleResponse(nil), common.ConflictError{Message: "employee 1 is terminated"})
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		repo.AssertCalled(t, "Reopen", mock.Anything, int64(10))
+	})
+
+	t.Run("should reject request when it cannot be reopened", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("Decide", mock.Anything, mock.Anything).Return(nil)
+		repo.On("Reopen", mock.Anything, int64(10)).Return(&pq.Error{Code: "23505", Constraint: "access_request_pending_key"})
+		repo.On("Fail", mock.Anything, int64(10), "role assignment failed: employee 1 is terminated", mock.AnythingOfType("time.Time")).Return(nil)
+		assigner.On("AssignRoles", mock.Anything, int64(1), mock.Anything).
+			Return([]assignment.RoleResponse(nil), common.ConflictError{Message: "employee 1 is terminated"})
+
+		_, err := svc.Approve(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestAccessRequestService_Reject(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should reject with reason", func(t *testing.T) {
+		repo := new(MockRepo)
+		assigner := new(MockAssigner)
+		svc := NewService(repo, assigner, validator.New())
+		repo.On("FindById", mock.Anything, int64(10)).Return(pendingRequest(), nil)
+		repo.On("FindEmployeeByLogin", mock.Anything, "boss").Return(boss, nil)
+		repo.On("Decide", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Status == StatusRejected && e.DecisionReason == "нет обоснования"
+		})).Return(nil)
+
+		got, err := svc.Reject(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{Reason: " нет обоснования "})
+
+		a.Nil(err)
+		a.Equal(StatusRejected, got.Status)
+		a.Equal("нет обоснования", got.DecisionReason)
+		assigner.AssertNotCalled(t, "AssignRoles", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require reason", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockAssigner), validator.New())
+
+		_, err := svc.Reject(context.Background(), Actor{Login: "boss"}, 10, DecisionRequest{Reason: "  "})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,48 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"idm/inner/web"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// HeaderApiKey заголовок, в котором сервисы передают API-ключ
+const HeaderApiKey = "X-API-Key"
+
+// Authenticator провайдер аутентификации по API-ключу (реализует web.Authenticator)
+type Authenticator struct {
+	apiKeyService KeyAuthenticator
+}
+
+// KeyAuthenticator интерфейс сервиса, проверяющего значение API-ключа
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (Response, error)
+}
+
+// NewAuthenticator создает провайдер аутентификации по API-ключу
+func NewAuthenticator(apiKeyService KeyAuthenticator) *Authenticator {
+	return &Authenticator{apiKeyService: apiKeyService}
+}
+
+// Supports сообщает, передан ли в запросе API-ключ
+func (a *Authenticator) Supports(ctx *fiber.Ctx) bool {
+	return ctx.Get(HeaderApiKey) != ""
+}
+
+// Authenticate проверяет API-ключ и возвращает claims с ролями ключа
+func (a *Authenticator) Authenticate(ctx *fiber.Ctx) (*web.IdmClaims, error) {
+	key, err := a.apiKeyService.Authenticate(ctx.Context(), ctx.Get(HeaderApiKey))
+	if err != nil {
+		return nil, err
+	}
+	return &web.IdmClaims{
+		RealmAccess:       web.RealmAccessClaims{Roles: key.Roles},
+		PreferredUsername: key.Name,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: fmt.Sprintf("api-key:%d", key.Id),
+		},
+	}, nil
+}
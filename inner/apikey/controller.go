@@ -0,0 +1,141 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для управления API-ключами
+type Controller struct {
+	server        *web.Server
+	apiKeyService Svc
+	logger        *common.Logger
+}
+
+// Svc интерфейс сервиса для управления API-ключами
+type Svc interface {
+	Issue(ctx context.Context, request IssueApiKeyRequest) (IssueResponse, error) // выпуск ключа
+	FindAll(ctx context.Context) ([]Response, error)                              // список ключей
+	Revoke(ctx context.Context, id int64) error                                   // отзыв ключа
+}
+
+// NewController создает новый экземпляр контроллера API-ключей
+func NewController(server *web.Server, apiKeyService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:        server,
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для управления API-ключами (право api-key:manage)
+func (c *Controller) RegisterRoutes() {
+	c.server.GroupApiV1.Post("/api-keys", c.server.Require(web.ApiKeyManage), c.IssueApiKey)
+	c.server.GroupApiV1.Get("/api-keys", c.server.Require(web.ApiKeyManage), c.GetAllApiKeys)
+	c.server.GroupApiV1.Delete("/api-keys/:id", c.server.Require(web.ApiKeyManage), c.RevokeApiKey)
+}
+
+// IssueApiKey выпускает новый API-ключ
+// @Summary Выпустить API-ключ
+// @Description Выпустить API-ключ для межсервисных вызовов. Значение ключа возвращается только один раз
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body apikey.IssueApiKeyRequest true "issue api key request"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /api-keys [post]
+func (c *Controller) IssueApiKey(ctx *fiber.Ctx) error {
+	var req IssueApiKeyRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "issue api key: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.apiKeyService.Issue(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "issue api key: failed to issue key", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// Значение ключа не логируем - только его видимый префикс
+	c.logger.InfoCtx(ctx.Context(), "issue api key: key issued", zap.Int64("id", resp.Id), zap.String("prefix", resp.Prefix))
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning issued api key")
+	}
+	return nil
+}
+
+// GetAllApiKeys получает список API-ключей
+// @Summary Получить API-ключи
+// @Description Получить список выпущенных API-ключей без их значений
+// @Tags api-key
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /api-keys [get]
+func (c *Controller) GetAllApiKeys(ctx *fiber.Ctx) error {
+	resp, err := c.apiKeyService.FindAll(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get api keys: failed to find keys", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning api keys")
+	}
+	return nil
+}
+
+// RevokeApiKey отзывает API-ключ
+// @Summary Отозвать API-ключ
+// @Description Отозвать API-ключ по идентификатору
+// @Tags api-key
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID API-ключа"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /api-keys/{id} [delete]
+func (c *Controller) RevokeApiKey(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid api key id")
+	}
+
+	if err := c.apiKeyService.Revoke(ctx.Context(), id); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "revoke api key: failed to revoke key", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,184 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockApiKeyService - мок для интерфейсов Svc и KeyAuthenticator
+type MockApiKeyService struct {
+	mock.Mock
+}
+
+func (m *MockApiKeyService) Issue(ctx context.Context, request IssueApiKeyRequest) (IssueResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(IssueResponse), args.Error(1)
+}
+
+func (m *MockApiKeyService) FindAll(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockApiKeyService) Revoke(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockApiKeyService) Authenticate(ctx context.Context, key string) (Response, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+// setupTest собирает сервер с цепочкой аутентификации: API-ключи, затем JWT
+func setupTest(t *testing.T) (*fiber.App, *MockApiKeyService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockApiKeyService)
+	server.AddAuthenticator(NewAuthenticator(mockService))
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createRequest создает HTTP-запрос с JSON-телом
+func createRequest(t *testing.T, method, url string, body interface{}) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestIssueApiKey(t *testing.T) {
+	t.Run("should issue key for admin token", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := IssueApiKeyRequest{Name: "hr-sync", Roles: []string{web.IdmAdmin}}
+		expected := IssueResponse{Response: Response{Id: 1, Name: "hr-sync", Prefix: "idm_abcdefgh", Roles: []string{web.IdmAdmin}}, Key: "idm_abcdefgh123"}
+		svc.On("Issue", mock.Anything, request).Return(expected, nil)
+
+		req := createRequest(t, "POST", "/api/v1/api-keys", request)
+		req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{web.IdmAdmin}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[IssueResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, expected, result.Data)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should forbid issue for user token", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		req := createRequest(t, "POST", "/api/v1/api-keys", IssueApiKeyRequest{Name: "hr-sync", Roles: []string{web.IdmAdmin}})
+		req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{web.IdmUser}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 400 on validation error", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := IssueApiKeyRequest{Name: "hr-sync"}
+		svc.On("Issue", mock.Anything, request).Return(IssueResponse{}, common.RequestValidationError{Message: "roles cannot be empty"})
+
+		req := createRequest(t, "POST", "/api/v1/api-keys", request)
+		req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{web.IdmAdmin}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestApiKeyAuthentication(t *testing.T) {
+	t.Run("should authenticate request with admin api key", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Authenticate", mock.Anything, "idm_valid").Return(Response{Id: 1, Roles: []string{web.IdmAdmin}}, nil)
+		svc.On("FindAll", mock.Anything).Return([]Response{{Id: 1, Name: "hr-sync"}}, nil)
+
+		req := createRequest(t, "GET", "/api/v1/api-keys", nil)
+		req.Header.Set(HeaderApiKey, "idm_valid")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should reject revoked api key", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Authenticate", mock.Anything, "idm_revoked").Return(Response{}, ErrInvalidKey)
+
+		req := createRequest(t, "GET", "/api/v1/api-keys", nil)
+		req.Header.Set(HeaderApiKey, "idm_revoked")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+		svc.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("should apply roles of api key", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Authenticate", mock.Anything, "idm_user").Return(Response{Id: 2, Roles: []string{web.IdmUser}}, nil)
+
+		req := createRequest(t, "DELETE", "/api/v1/api-keys/1", nil)
+		req.Header.Set(HeaderApiKey, "idm_user")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		svc.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	t.Run("should revoke key for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Revoke", mock.Anything, int64(3)).Return(nil)
+
+		req := createRequest(t, "DELETE", "/api/v1/api-keys/3", nil)
+		req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{web.IdmAdmin}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 204, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for missing key", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Revoke", mock.Anything, int64(3)).Return(common.NotFoundError{Message: "active api key with id 3 not found"})
+
+		req := createRequest(t, "DELETE", "/api/v1/api-keys/3", nil)
+		req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{web.IdmAdmin}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
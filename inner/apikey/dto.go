@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Entity представляет API-ключ в базе данных (хранится только хэш ключа)
+type Entity struct {
+	Id        int64          `db:"id"`
+	Name      string         `db:"name"`
+	Prefix    string         `db:"prefix"`
+	KeyHash   string         `db:"key_hash"`
+	Roles     pq.StringArray `db:"roles"`
+	CreatedAt time.Time      `db:"created_at"`
+	RevokedAt *time.Time     `db:"revoked_at"`
+}
+
+// toResponse преобразует Entity в Response
+func (e *Entity) toResponse() Response {
+	return Response{
+		Id:        e.Id,
+		Name:      e.Name,
+		Prefix:    e.Prefix,
+		Roles:     e.Roles,
+		CreatedAt: e.CreatedAt,
+		RevokedAt: e.RevokedAt,
+	}
+}
+
+// Response представляет ответ API для API-ключа
+type Response struct {
+	Id        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Roles     []string   `json:"roles"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssueResponse представляет ответ на выпуск API-ключа.
+// Значение ключа возвращается только один раз и больше нигде не хранится
+type IssueResponse struct {
+	Response
+	Key string `json:"key"`
+}
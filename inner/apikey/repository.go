@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository представляет репозиторий для работы с API-ключами
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// Add сохраняет новый API-ключ
+func (r *Repository) Add(ctx context.Context, e *Entity) error {
+	query := `INSERT INTO api_key (name, prefix, key_hash, roles, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.Prefix, e.KeyHash, e.Roles, e.CreatedAt).Scan(&e.Id)
+}
+
+// FindAll возвращает все API-ключи, включая отозванные
+func (r *Repository) FindAll(ctx context.Context) ([]Entity, error) {
+	var res []Entity
+	err := r.db.SelectContext(ctx, &res, "SELECT * FROM api_key ORDER BY id")
+	return res, err
+}
+
+// FindActiveByHash возвращает неотозванный API-ключ по хэшу, sql.ErrNoRows - если такого нет
+func (r *Repository) FindActiveByHash(ctx context.Context, keyHash string) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT * FROM api_key WHERE key_hash = $1 AND revoked_at IS NULL", keyHash)
+	return res, err
+}
+
+// Revoke отзывает API-ключ, возвращает количество отозванных ключей (0 - если ключ не найден или уже отозван)
+func (r *Repository) Revoke(ctx context.Context, id int64, at time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE api_key SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", at, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,8 @@
+package apikey
+
+// IssueApiKeyRequest используется для выпуска нового API-ключа.
+// Роли должны быть известны действующей политике доступа; проверку выполняет сервис
+type IssueApiKeyRequest struct {
+	Name  string   `json:"name" validate:"required,min=2,max=100"`
+	Roles []string `json:"roles" validate:"required,min=1,dive,required"`
+}
@@ -0,0 +1,151 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"slices"
+	"time"
+)
+
+const (
+	keyPrefix    = "idm_" // префикс, по которому ключи idm легко отличить от других секретов
+	keyBytes     = 32     // количество случайных байт в ключе
+	prefixLength = 12     // длина видимой части ключа, по которой администратор узнает ключ в списке
+)
+
+// ErrInvalidKey возвращается, если API-ключ не найден или отозван
+var ErrInvalidKey = errors.New("invalid or revoked api key")
+
+// Service структура, которая инкапсулирует бизнес-логику API-ключей
+type Service struct {
+	repo      Repo
+	validator Validator
+	roles     func() []string
+}
+
+// Repo интерфейс репозитория для API-ключей
+type Repo interface {
+	Add(ctx context.Context, e *Entity) error
+	FindAll(ctx context.Context) ([]Entity, error)
+	FindActiveByHash(ctx context.Context, keyHash string) (Entity, error)
+	Revoke(ctx context.Context, id int64, at time.Time) (int64, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// NewService функция-конструктор для Service.
+// roles возвращает роли, которые можно выдать ключу; функция вызывается при каждом выпуске,
+// поэтому учитывается политика доступа, действующая на момент запроса
+func NewService(repo Repo, validator Validator, roles func() []string) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+		roles:     roles,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	err := svc.validator.ValidateWithCustomMessages(request)
+	if err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Issue выпускает новый API-ключ. Значение ключа возвращается только в этом ответе
+func (svc *Service) Issue(ctx context.Context, request IssueApiKeyRequest) (IssueResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return IssueResponse{}, err
+	}
+	known := svc.roles()
+	for _, role := range request.Roles {
+		if !slices.Contains(known, role) {
+			return IssueResponse{}, common.RequestValidationError{Message: fmt.Sprintf("unknown role: %s", role)}
+		}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return IssueResponse{}, fmt.Errorf("error generating api key: %w", err)
+	}
+
+	entity := &Entity{
+		Name:      request.Name,
+		Prefix:    key[:prefixLength],
+		KeyHash:   hashKey(key),
+		Roles:     request.Roles,
+		CreatedAt: time.Now(),
+	}
+	if err := svc.repo.Add(ctx, entity); err != nil {
+		return IssueResponse{}, common.RepositoryError{Message: "error adding api key", Err: err}
+	}
+
+	return IssueResponse{Response: entity.toResponse(), Key: key}, nil
+}
+
+// FindAll возвращает все API-ключи без их значений
+func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding api keys", Err: err}
+	}
+
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// Revoke отзывает API-ключ по ID
+func (svc *Service) Revoke(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid api key id: %d", id)}
+	}
+
+	revoked, err := svc.repo.Revoke(ctx, id, time.Now())
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error revoking api key with id %d", id), Err: err}
+	}
+	if revoked == 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("active api key with id %d not found", id)}
+	}
+	return nil
+}
+
+// Authenticate проверяет значение API-ключа и возвращает его описание
+func (svc *Service) Authenticate(ctx context.Context, key string) (Response, error) {
+	entity, err := svc.repo.FindActiveByHash(ctx, hashKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, ErrInvalidKey
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: "error finding api key", Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// generateKey генерирует случайное значение API-ключа
+func generateKey() (string, error) {
+	buf := make([]byte, keyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashKey возвращает хэш API-ключа, который хранится в базе данных вместо самого ключа
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,174 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория API-ключей
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindAll(ctx context.Context) ([]Entity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindActiveByHash(ctx context.Context, keyHash string) (Entity, error) {
+	args := m.Called(ctx, keyHash)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) Revoke(ctx context.Context, id int64, at time.Time) (int64, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// knownRoles - роли политики доступа, включая пользовательскую роль из файла политики
+func knownRoles() []string {
+	return []string{"HR_SYNC", "IDM_ADMIN", "IDM_USER"}
+}
+
+func TestApiKeyService_Issue(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should issue key and store only its hash", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		var stored *Entity
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*apikey.Entity")).Return(nil).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*Entity)
+			stored.Id = 1
+		})
+
+		got, err := svc.Issue(context.Background(), IssueApiKeyRequest{Name: "hr-sync", Roles: []string{"IDM_ADMIN"}})
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Id)
+		a.True(strings.HasPrefix(got.Key, keyPrefix))
+		a.Equal(got.Key[:prefixLength], got.Prefix)
+		a.Equal([]string{"IDM_ADMIN"}, got.Roles)
+		a.Equal(hashKey(got.Key), stored.KeyHash)
+		a.NotContains(stored.KeyHash, got.Key)
+	})
+
+	t.Run("should generate different keys", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*apikey.Entity")).Return(nil)
+		request := IssueApiKeyRequest{Name: "hr-sync", Roles: []string{"IDM_USER"}}
+
+		first, err := svc.Issue(context.Background(), request)
+		a.Nil(err)
+		second, err := svc.Issue(context.Background(), request)
+		a.Nil(err)
+
+		a.NotEqual(first.Key, second.Key)
+	})
+
+	t.Run("should reject unknown role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+
+		_, err := svc.Issue(context.Background(), IssueApiKeyRequest{Name: "hr-sync", Roles: []string{"ROOT"}})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should issue key with custom role from policy", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*apikey.Entity")).Return(nil)
+
+		got, err := svc.Issue(context.Background(), IssueApiKeyRequest{Name: "hr-sync", Roles: []string{"HR_SYNC"}})
+
+		a.Nil(err)
+		a.Equal([]string{"HR_SYNC"}, got.Roles)
+	})
+
+	t.Run("should reject empty roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+
+		_, err := svc.Issue(context.Background(), IssueApiKeyRequest{Name: "hr-sync"})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should find active key by hash", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("FindActiveByHash", mock.Anything, hashKey("idm_secret")).
+			Return(Entity{Id: 3, Name: "hr-sync", Roles: []string{"IDM_ADMIN"}}, nil)
+
+		got, err := svc.Authenticate(context.Background(), "idm_secret")
+
+		a.Nil(err)
+		a.Equal(int64(3), got.Id)
+		a.Equal([]string{"IDM_ADMIN"}, got.Roles)
+	})
+
+	t.Run("should reject unknown or revoked key", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("FindActiveByHash", mock.Anything, mock.Anything).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Authenticate(context.Background(), "idm_unknown")
+
+		a.ErrorIs(err, ErrInvalidKey)
+	})
+}
+
+func TestApiKeyService_Revoke(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should revoke key", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("Revoke", mock.Anything, int64(3), mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+
+		a.Nil(svc.Revoke(context.Background(), 3))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing or revoked key", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+		repo.On("Revoke", mock.Anything, int64(3), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+
+		err := svc.Revoke(context.Background(), 3)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should reject invalid id", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New(), knownRoles)
+
+		err := svc.Revoke(context.Background(), 0)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
@@ -0,0 +1,235 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с назначениями ролей
+type Controller struct {
+	server            *web.Server
+	assignmentService Svc
+	logger            *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с назначениями ролей
+type Svc interface {
+	FindRolesByEmployee(ctx context.Context, employeeId int64) ([]RoleResponse, error)                     // роли сотрудника
+	FindEffectiveRolesByEmployee(ctx context.Context, employeeId int64) ([]EffectiveRoleResponse, error)   // роли сотрудника с учетом иерархии
+	FindEmployeesByRole(ctx context.Context, roleId int64) ([]EmployeeResponse, error)                     // сотрудники с ролью
+	AssignRoles(ctx context.Context, employeeId int64, request AssignRolesRequest) ([]RoleResponse, error) // назначение ролей
+	RevokeRoles(ctx context.Context, employeeId int64, request RevokeRolesRequest) error                   // отзыв ролей
+}
+
+// NewController создает новый экземпляр контроллера назначений
+func NewController(server *web.Server, assignmentService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:            server,
+		assignmentService: assignmentService,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с назначениями ролей
+func (c *Controller) RegisterRoutes() {
+	// Назначение и отзыв ролей (по умолчанию политика разрешает их только администраторам)
+	c.server.GroupApiV1.Post("/employees/:id/roles", c.server.Require(web.AssignmentWrite), c.AssignRoles)
+	c.server.GroupApiV1.Delete("/employees/:id/roles", c.server.Require(web.AssignmentWrite), c.RevokeRoles)
+
+	// Маршруты чтения (по умолчанию доступны администраторам и пользователям)
+	c.server.GroupApiV1.Get("/employees/:id/roles", c.server.Require(web.AssignmentRead), c.GetEmployeeRoles)
+	c.server.GroupApiV1.Get("/employees/:id/roles/effective", c.server.Require(web.AssignmentRead), c.GetEmployeeEffectiveRoles)
+	c.server.GroupApiV1.Get("/roles/:id/employees", c.server.Require(web.AssignmentRead), c.GetRoleEmployees)
+}
+
+// GetEmployeeRoles получает роли сотрудника
+// @Summary Получить роли сотрудника
+// @Description Получить список действующих ролей сотрудника; назначения вне срока действия не учитываются
+// @Tags assignment
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/roles [get]
+func (c *Controller) GetEmployeeRoles(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	resp, err := c.assignmentService.FindRolesByEmployee(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get employee roles: failed to find roles", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee roles")
+	}
+	return nil
+}
+
+// GetEmployeeEffectiveRoles получает действующие роли сотрудника
+// @Summary Получить действующие роли сотрудника
+// @Description Получить назначенные сотруднику роли вместе с ролями, унаследованными по иерархии
+// @Tags assignment
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/roles/effective [get]
+func (c *Controller) GetEmployeeEffectiveRoles(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	resp, err := c.assignmentService.FindEffectiveRolesByEmployee(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get employee effective roles: failed to find roles", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee roles")
+	}
+	return nil
+}
+
+// GetRoleEmployees получает сотрудников, которым назначена роль
+// @Summary Получить сотрудников с ролью
+// @Description Получить список сотрудников, которым роль назначена и действует в текущий момент
+// @Tags assignment
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID роли"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/employees [get]
+func (c *Controller) GetRoleEmployees(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	resp, err := c.assignmentService.FindEmployeesByRole(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get role employees: failed to find employees", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning role employees")
+	}
+	return nil
+}
+
+// AssignRoles назначает сотруднику роли
+// @Summary Назначить роли сотруднику
+// @Description Назначить сотруднику роли по списку идентификаторов; valid_from и valid_until ограничивают срок действия назначения, повторное назначение заменяет срок.
+// @Description Назначение отклоняется (409), если сотрудник уволен или получил бы роли, совмещать которые запрещено правилами разделения обязанностей
+// @Tags assignment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param request body assignment.AssignRolesRequest true "список ID ролей"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /employees/{id}/roles [post]
+func (c *Controller) AssignRoles(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	var req AssignRolesRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "assign roles: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	c.logger.DebugCtx(ctx.Context(), "assign roles: received request", zap.Int64("employee_id", id), zap.Any("request", req))
+
+	resp, err := c.assignmentService.AssignRoles(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "assign roles: failed to assign roles", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee roles")
+	}
+	return nil
+}
+
+// RevokeRoles отзывает роли у сотрудника
+// @Summary Отозвать роли у сотрудника
+// @Description Отозвать у сотрудника роли по списку идентификаторов
+// @Tags assignment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param request body assignment.RevokeRolesRequest true "список ID ролей"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/roles [delete]
+func (c *Controller) RevokeRoles(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	var req RevokeRolesRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "revoke roles: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := c.assignmentService.RevokeRoles(ctx.Context(), id, req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "revoke roles: failed to revoke roles", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	case errors.As(err, &common.ConflictError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
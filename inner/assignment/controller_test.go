@@ -0,0 +1,217 @@
+package assignment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockAssignmentService - полный мок для интерфейса Svc
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) FindRolesByEmployee(ctx context.Context, employeeId int64) ([]RoleResponse, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]RoleResponse), args.Error(1)
+}
+
+func (m *MockAssignmentService) FindEffectiveRolesByEmployee(ctx context.Context, employeeId int64) ([]EffectiveRoleResponse, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]EffectiveRoleResponse), args.Error(1)
+}
+
+func (m *MockAssignmentService) FindEmployeesByRole(ctx context.Context, roleId int64) ([]EmployeeResponse, error) {
+	args := m.Called(ctx, roleId)
+	return args.Get(0).([]EmployeeResponse), args.Error(1)
+}
+
+func (m *MockAssignmentService) AssignRoles(ctx context.Context, employeeId int64, request AssignRolesRequest) ([]RoleResponse, error) {
+	args := m.Called(ctx, employeeId, request)
+	return args.Get(0).([]RoleResponse), args.Error(1)
+}
+
+func (m *MockAssignmentService) RevokeRoles(ctx context.Context, employeeId int64, request RevokeRolesRequest) error {
+	args := m.Called(ctx, employeeId, request)
+	return args.Error(0)
+}
+
+// setupTest инициализирует тестовое окружение
+func setupTest(t *testing.T) (*fiber.App, *MockAssignmentService) {
+	t.Helper()
+
+	app := fiber.New()
+	groupApiV1 := app.Group("/api/v1")
+	server := &web.Server{App: app, GroupApiV1: groupApiV1}
+
+	logger := &common.Logger{Logger: zap.NewNop()}
+	groupApiV1.Use(web.AuthMiddleware(logger))
+
+	mockService := new(MockAssignmentService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return app, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestGetEmployeeRoles(t *testing.T) {
+	t.Run("should return roles for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		expected := []RoleResponse{{Id: 1, Name: "engineer"}}
+		svc.On("FindRolesByEmployee", mock.Anything, int64(7)).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/7/roles", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]RoleResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.True(t, result.Success)
+		assert.Equal(t, expected, result.Data)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 for missing employee", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindRolesByEmployee", mock.Anything, int64(7)).
+			Return([]RoleResponse(nil), common.NotFoundError{Message: "employee with id 7 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/7/roles", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for invalid id", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/abc/roles", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestGetEmployeeEffectiveRoles(t *testing.T) {
+	app, svc := setupTest(t)
+	expected := []EffectiveRoleResponse{{Id: 1, Name: "engineer", Inherited: true}, {Id: 2, Name: "senior-engineer"}}
+	svc.On("FindEffectiveRolesByEmployee", mock.Anything, int64(7)).Return(expected, nil)
+
+	resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/7/roles/effective", nil, []string{web.IdmUser}))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result common.Response[[]EffectiveRoleResponse]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, expected, result.Data)
+}
+
+func TestGetRoleEmployees(t *testing.T) {
+	app, svc := setupTest(t)
+	expected := []EmployeeResponse{{Id: 7, Name: "John Doe"}}
+	svc.On("FindEmployeesByRole", mock.Anything, int64(1)).Return(expected, nil)
+
+	resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/1/employees", nil, []string{web.IdmUser}))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	svc.AssertExpectations(t)
+}
+
+func TestAssignRoles(t *testing.T) {
+	t.Run("should assign roles for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AssignRolesRequest{RoleIds: []int64{1, 2}}
+		svc.On("AssignRoles", mock.Anything, int64(7), request).Return([]RoleResponse{{Id: 1}, {Id: 2}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/employees/7/roles", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should forbid assignment for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AssignRolesRequest{RoleIds: []int64{1}}
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/employees/7/roles", request, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 400 on validation error", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AssignRolesRequest{}
+		svc.On("AssignRoles", mock.Anything, int64(7), request).
+			Return([]RoleResponse(nil), common.RequestValidationError{Message: "ids list cannot be empty"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/employees/7/roles", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return 409 for terminated employee", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AssignRolesRequest{RoleIds: []int64{1}}
+		svc.On("AssignRoles", mock.Anything, int64(7), request).
+			Return([]RoleResponse(nil), common.ConflictError{Message: "employee 7 is terminated and cannot be assigned roles"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/employees/7/roles", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestRevokeRoles(t *testing.T) {
+	t.Run("should revoke roles for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := RevokeRolesRequest{RoleIds: []int64{1}}
+		svc.On("RevokeRoles", mock.Anything, int64(7), request).Return(nil)
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/employees/7/roles", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 204, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should require token", func(t *testing.T) {
+		app, svc := setupTest(t)
+		req := httptest.NewRequest("DELETE", "/api/v1/employees/7/roles", nil)
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
@@ -0,0 +1,122 @@
+package assignment
+
+import "time"
+
+// Entity представляет связь сотрудника и роли в базе данных
+type Entity struct {
+	EmployeeId int64      `db:"employee_id"`
+	RoleId     int64      `db:"role_id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ValidFrom  *time.Time `db:"valid_from"`
+	ValidUntil *time.Time `db:"valid_until"`
+}
+
+// toResponse преобразует Entity в GrantResponse
+func (e *Entity) toResponse() GrantResponse {
+	return GrantResponse{
+		EmployeeId: e.EmployeeId,
+		RoleId:     e.RoleId,
+		AssignedAt: e.CreatedAt,
+		ValidFrom:  e.ValidFrom,
+		ValidUntil: e.ValidUntil,
+	}
+}
+
+// RoleEntity представляет роль, назначенную сотруднику
+type RoleEntity struct {
+	Id         int64      `db:"id"`
+	Name       string     `db:"name"`
+	AssignedAt time.Time  `db:"assigned_at"`
+	ValidFrom  *time.Time `db:"valid_from"`
+	ValidUntil *time.Time `db:"valid_until"`
+}
+
+// toResponse преобразует RoleEntity в RoleResponse
+func (e *RoleEntity) toResponse() RoleResponse {
+	return RoleResponse{
+		Id:         e.Id,
+		Name:       e.Name,
+		AssignedAt: e.AssignedAt,
+		ValidFrom:  e.ValidFrom,
+		ValidUntil: e.ValidUntil,
+	}
+}
+
+// SodConflictEntity представляет правило разделения обязанностей, которое нарушило бы назначение
+type SodConflictEntity struct {
+	RuleId    int64  `db:"rule_id"`
+	RuleName  string `db:"rule_name"`
+	RoleAName string `db:"role_a_name"`
+	RoleBName string `db:"role_b_name"`
+}
+
+// EmployeeEntity представляет сотрудника, которому назначена роль
+type EmployeeEntity struct {
+	Id         int64      `db:"id"`
+	Name       string     `db:"name"`
+	AssignedAt time.Time  `db:"assigned_at"`
+	ValidFrom  *time.Time `db:"valid_from"`
+	ValidUntil *time.Time `db:"valid_until"`
+}
+
+// toResponse преобразует EmployeeEntity в EmployeeResponse
+func (e *EmployeeEntity) toResponse() EmployeeResponse {
+	return EmployeeResponse{
+		Id:         e.Id,
+		Name:       e.Name,
+		AssignedAt: e.AssignedAt,
+		ValidFrom:  e.ValidFrom,
+		ValidUntil: e.ValidUntil,
+	}
+}
+
+// EffectiveRoleEntity представляет роль сотрудника с учетом иерархии ролей
+type EffectiveRoleEntity struct {
+	Id     int64  `db:"id"`
+	Name   string `db:"name"`
+	Direct bool   `db:"direct"`
+}
+
+// toResponse преобразует EffectiveRoleEntity в EffectiveRoleResponse
+func (e *EffectiveRoleEntity) toResponse() EffectiveRoleResponse {
+	return EffectiveRoleResponse{
+		Id:        e.Id,
+		Name:      e.Name,
+		Inherited: !e.Direct,
+	}
+}
+
+// GrantResponse представляет назначение роли сотруднику со сроком действия
+type GrantResponse struct {
+	EmployeeId int64      `json:"employee_id"`
+	RoleId     int64      `json:"role_id"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// RoleResponse представляет ответ API для роли сотрудника
+type RoleResponse struct {
+	Id         int64      `json:"id"`
+	Name       string     `json:"name"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// EmployeeResponse представляет ответ API для сотрудника с ролью
+type EmployeeResponse struct {
+	Id         int64      `json:"id"`
+	Name       string     `json:"name"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// EffectiveRoleResponse представляет действующую роль сотрудника.
+// Inherited означает, что роль не назначена напрямую, а получена через иерархию ролей
+type EffectiveRoleResponse struct {
+	Id        int64  `json:"id"`
+	Name      string `json:"name"`
+	Inherited bool   `json:"inherited"`
+}
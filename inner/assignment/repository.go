@@ -0,0 +1,195 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Repository представляет репозиторий для работы с назначениями ролей
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// EmployeeExists проверяет наличие неудаленного сотрудника с заданным ID
+func (r *Repository) EmployeeExists(ctx context.Context, employeeId int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM employee WHERE id = $1 AND deleted_at IS NULL)", employeeId)
+	return exists, err
+}
+
+// RoleExists проверяет наличие неудаленной роли с заданным ID
+func (r *Repository) RoleExists(ctx context.Context, roleId int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM role WHERE id = $1 AND deleted_at IS NULL)", roleId)
+	return exists, err
+}
+
+// FindExistingRoleIds возвращает те ID из списка, для которых существуют неудаленные роли
+func (r *Repository) FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error) {
+	var res []int64
+	err := r.db.SelectContext(ctx, &res, "SELECT id FROM role WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(roleIds))
+	return res, err
+}
+
+// activeGrant - условие действия назначения er: срок действия уже начался и еще не истек
+const activeGrant = `(er.valid_from IS NULL OR er.valid_from <= now()) AND (er.valid_until IS NULL OR er.valid_until > now())`
+
+// FindRolesByEmployeeId возвращает действующие роли, назначенные сотруднику
+// (удаленные роли и назначения вне срока действия не учитываются)
+func (r *Repository) FindRolesByEmployeeId(ctx context.Context, employeeId int64) ([]RoleEntity, error) {
+	query := `SELECT r.id, r.name, er.created_at AS assigned_at, er.valid_from, er.valid_until
+		FROM employee_role er
+		JOIN role r ON r.id = er.role_id
+		WHERE er.employee_id = $1 AND r.deleted_at IS NULL AND ` + activeGrant + `
+		ORDER BY r.id`
+	var res []RoleEntity
+	err := r.db.SelectContext(ctx, &res, query, employeeId)
+	return res, err
+}
+
+// FindEffectiveRolesByEmployeeId возвращает роли сотрудника вместе со всеми ролями, от которых они наследуются.
+// Роль считается прямой, если она назначена сотруднику, даже если она также унаследована.
+// Удаленная роль и назначение вне срока действия не действуют и не передают наследование родителей
+func (r *Repository) FindEffectiveRolesByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveRoleEntity, error) {
+	query := `WITH RECURSIVE effective_role AS (
+			SELECT er.role_id, true AS direct
+			FROM employee_role er JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+			WHERE er.employee_id = $1 AND ` + activeGrant + `
+			UNION
+			SELECT rp.parent_id, false
+			FROM role_parent rp
+			JOIN effective_role er ON rp.role_id = er.role_id
+			JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+		)
+		SELECT r.id, r.name, bool_or(er.direct) AS direct
+		FROM effective_role er
+		JOIN role r ON r.id = er.role_id
+		GROUP BY r.id, r.name
+		ORDER BY r.id`
+	var res []EffectiveRoleEntity
+	err := r.db.SelectContext(ctx, &res, query, employeeId)
+	return res, err
+}
+
+// FindEmployeesByRoleId возвращает сотрудников, которым роль назначена и действует
+// (удаленные сотрудники и назначения вне срока действия не учитываются)
+func (r *Repository) FindEmployeesByRoleId(ctx context.Context, roleId int64) ([]EmployeeEntity, error) {
+	query := `SELECT e.id, e.name, er.created_at AS assigned_at, er.valid_from, er.valid_until
+		FROM employee_role er
+		JOIN employee e ON e.id = er.employee_id
+		WHERE er.role_id = $1 AND e.deleted_at IS NULL AND ` + activeGrant + `
+		ORDER BY e.id`
+	var res []EmployeeEntity
+	err := r.db.SelectContext(ctx, &res, query, roleId)
+	return res, err
+}
+
+// AssignCheck проверяет назначение по статусу сотрудника и правилам разделения обязанностей, которые оно нарушило бы;
+// ошибка проверки отменяет назначение
+type AssignCheck func(status string, conflicts []SodConflictEntity) error
+
+// Assign назначает сотруднику роли со сроком действия [validFrom, validUntil); nil означает отсутствие ограничения.
+// У уже существующих назначений заменяется только срок действия, дата назначения сохраняется.
+// Назначение выполняется в одной транзакции с проверкой check под блокировкой строки сотрудника, поэтому
+// параллельные назначения и увольнение того же сотрудника, а также изменения иерархии ролей и правил
+// не могут обойти проверку.
+// Возвращает sql.ErrNoRows, если неудаленный сотрудник не найден
+func (r *Repository) Assign(ctx context.Context, employeeId int64, roleIds []int64, at time.Time, validFrom, validUntil *time.Time, check AssignCheck) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var status string
+	err = tx.GetContext(ctx, &status, "SELECT status FROM employee WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", employeeId)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	// изменения иерархии ролей и правил разделения обязанностей проверяют назначения под более строгой
+	// блокировкой этих таблиц, поэтому выполняются либо до этой проверки, либо после назначения
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE role_parent, sod_rule IN SHARE MODE"); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	var conflicts []SodConflictEntity
+	if err := tx.SelectContext(ctx, &conflicts, sodConflictsQuery, employeeId, pq.Array(roleIds)); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if err := check(status, conflicts); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	query := `INSERT INTO employee_role (employee_id, role_id, created_at, valid_from, valid_until)
+		SELECT $1, role_id, $3, $4, $5 FROM unnest($2::bigint[]) AS role_id
+		ON CONFLICT (employee_id, role_id) DO UPDATE
+		SET valid_from = EXCLUDED.valid_from, valid_until = EXCLUDED.valid_until`
+	if _, err := tx.ExecContext(ctx, query, employeeId, pq.Array(roleIds), at, validFrom, validUntil); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// sodConflictsQuery выбирает запрещающие (prevent) правила разделения обязанностей, которые нарушило бы
+// назначение сотруднику $1 ролей $2. Роли учитываются с иерархией; уже имеющиеся назначения - вместе с еще
+// не начавшимися. Нарушения, которые были у сотрудника и до назначения, не выбираются
+const sodConflictsQuery = `WITH RECURSIVE held AS (
+		SELECT er.role_id, false AS requested
+		FROM employee_role er JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+		WHERE er.employee_id = $1 AND (er.valid_until IS NULL OR er.valid_until > now())
+		UNION
+		SELECT r.id, true FROM role r WHERE r.id = ANY($2) AND r.deleted_at IS NULL
+		UNION
+		SELECT rp.parent_id, h.requested
+		FROM role_parent rp
+		JOIN held h ON rp.role_id = h.role_id
+		JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+	),
+	held_before AS (SELECT role_id FROM held WHERE NOT requested)
+	SELECT s.id AS rule_id, s.name AS rule_name, ra.name AS role_a_name, rb.name AS role_b_name
+	FROM sod_rule s
+	JOIN role ra ON ra.id = s.role_a_id
+	JOIN role rb ON rb.id = s.role_b_id
+	WHERE s.mode = 'prevent'
+		AND s.role_a_id IN (SELECT role_id FROM held) AND s.role_b_id IN (SELECT role_id FROM held)
+		AND NOT (s.role_a_id IN (SELECT role_id FROM held_before) AND s.role_b_id IN (SELECT role_id FROM held_before))
+	ORDER BY s.name`
+
+// Revoke отзывает у сотрудника роли
+func (r *Repository) Revoke(ctx context.Context, employeeId int64, roleIds []int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM employee_role WHERE employee_id = $1 AND role_id = ANY($2)", employeeId, pq.Array(roleIds))
+	return err
+}
+
+// DeleteExpired удаляет назначения, срок действия которых истек к моменту at, и возвращает удаленные назначения.
+// Строки затронутых сотрудников блокируются в той же транзакции, что и при назначении ролей
+func (r *Repository) DeleteExpired(ctx context.Context, at time.Time) ([]Entity, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := `SELECT id FROM employee
+		WHERE id IN (SELECT employee_id FROM employee_role WHERE valid_until <= $1)
+		ORDER BY id FOR UPDATE`
+	var locked []int64
+	if err := tx.SelectContext(ctx, &locked, lock, at); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+
+	query := `DELETE FROM employee_role WHERE valid_until <= $1
+		RETURNING employee_id, role_id, created_at, valid_from, valid_until`
+	var res []Entity
+	if err := tx.SelectContext(ctx, &res, query, at); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	return res, tx.Commit()
+}
@@ -0,0 +1,16 @@
+package assignment
+
+import "time"
+
+// AssignRolesRequest используется для назначения ролей сотруднику.
+// ValidFrom и ValidUntil задают срок действия назначения; без них роль действует бессрочно
+type AssignRolesRequest struct {
+	RoleIds    []int64    `json:"role_ids" validate:"required,min=1,dive,gt=0"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// RevokeRolesRequest используется для отзыва ролей у сотрудника
+type RevokeRolesRequest struct {
+	RoleIds []int64 `json:"role_ids" validate:"required,min=1,dive,gt=0"`
+}
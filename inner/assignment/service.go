@@ -0,0 +1,245 @@
+package assignment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"idm/inner/employee"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику назначения ролей
+type Service struct {
+	repo      Repo
+	validator Validator
+}
+
+// Repo интерфейс репозитория для назначений ролей
+type Repo interface {
+	EmployeeExists(ctx context.Context, employeeId int64) (bool, error)
+	RoleExists(ctx context.Context, roleId int64) (bool, error)
+	FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error)
+	FindRolesByEmployeeId(ctx context.Context, employeeId int64) ([]RoleEntity, error)
+	FindEffectiveRolesByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveRoleEntity, error)
+	FindEmployeesByRoleId(ctx context.Context, roleId int64) ([]EmployeeEntity, error)
+	Assign(ctx context.Context, employeeId int64, roleIds []int64, at time.Time, validFrom, validUntil *time.Time, check AssignCheck) error
+	Revoke(ctx context.Context, employeeId int64, roleIds []int64) error
+	DeleteExpired(ctx context.Context, at time.Time) ([]Entity, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	err := svc.validator.ValidateWithCustomMessages(request)
+	if err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// FindRolesByEmployee возвращает роли, назначенные сотруднику
+func (svc *Service) FindRolesByEmployee(ctx context.Context, employeeId int64) ([]RoleResponse, error) {
+	if err := svc.checkEmployee(ctx, employeeId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindRolesByEmployeeId(ctx, employeeId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding roles of employee %d", employeeId), Err: err}
+	}
+
+	responses := make([]RoleResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// FindEffectiveRolesByEmployee возвращает действующие роли сотрудника с учетом иерархии ролей
+func (svc *Service) FindEffectiveRolesByEmployee(ctx context.Context, employeeId int64) ([]EffectiveRoleResponse, error) {
+	if err := svc.checkEmployee(ctx, employeeId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindEffectiveRolesByEmployeeId(ctx, employeeId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding effective roles of employee %d", employeeId), Err: err}
+	}
+
+	responses := make([]EffectiveRoleResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// FindEmployeesByRole возвращает сотрудников, которым назначена роль
+func (svc *Service) FindEmployeesByRole(ctx context.Context, roleId int64) ([]EmployeeResponse, error) {
+	if roleId <= 0 {
+		return nil, common.RequestValidationError{Message: fmt.Sprintf("invalid role id: %d", roleId)}
+	}
+
+	exists, err := svc.repo.RoleExists(ctx, roleId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding role with id %d", roleId), Err: err}
+	}
+	if !exists {
+		return nil, common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", roleId)}
+	}
+
+	entities, err := svc.repo.FindEmployeesByRoleId(ctx, roleId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding employees with role %d", roleId), Err: err}
+	}
+
+	responses := make([]EmployeeResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// AssignRoles назначает сотруднику роли и возвращает его актуальный список действующих ролей.
+// Повторное назначение роли заменяет срок ее действия. Уволенному сотруднику роли не назначаются,
+// как и роли, совмещение которых с уже имеющимися запрещено правилами разделения обязанностей
+func (svc *Service) AssignRoles(ctx context.Context, employeeId int64, request AssignRolesRequest) ([]RoleResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := validatePeriod(request.ValidFrom, request.ValidUntil, now); err != nil {
+		return nil, err
+	}
+	if employeeId <= 0 {
+		return nil, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", employeeId)}
+	}
+
+	existing, err := svc.repo.FindExistingRoleIds(ctx, request.RoleIds)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding roles by ids", Err: err}
+	}
+	if missing := missingIds(request.RoleIds, existing); len(missing) > 0 {
+		return nil, common.NotFoundError{Message: fmt.Sprintf("roles not found: %v", missing)}
+	}
+
+	// проверка выполняется репозиторием под блокировкой сотрудника, ее ошибка возвращается как есть
+	var rejected error
+	check := func(status string, conflicts []SodConflictEntity) error {
+		rejected = checkAssignable(employeeId, status, conflicts)
+		return rejected
+	}
+	err = svc.repo.Assign(ctx, employeeId, request.RoleIds, now, request.ValidFrom, request.ValidUntil, check)
+	if rejected != nil {
+		return nil, rejected
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", employeeId)}
+	}
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error assigning roles to employee %d", employeeId), Err: err}
+	}
+
+	return svc.FindRolesByEmployee(ctx, employeeId)
+}
+
+// RevokeRoles отзывает у сотрудника роли
+func (svc *Service) RevokeRoles(ctx context.Context, employeeId int64, request RevokeRolesRequest) error {
+	if err := svc.ValidateRequest(request); err != nil {
+		return err
+	}
+	if err := svc.checkEmployee(ctx, employeeId); err != nil {
+		return err
+	}
+
+	if err := svc.repo.Revoke(ctx, employeeId, request.RoleIds); err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error revoking roles from employee %d", employeeId), Err: err}
+	}
+	return nil
+}
+
+// RevokeExpired отзывает назначения ролей, срок действия которых истек к моменту at, и возвращает их
+func (svc *Service) RevokeExpired(ctx context.Context, at time.Time) ([]Entity, error) {
+	revoked, err := svc.repo.DeleteExpired(ctx, at)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error revoking expired role assignments", Err: err}
+	}
+	return revoked, nil
+}
+
+// validatePeriod проверяет, что срок действия назначения не пустой и еще не истек
+func validatePeriod(validFrom, validUntil *time.Time, now time.Time) error {
+	if validUntil == nil {
+		return nil
+	}
+	if validFrom != nil && !validUntil.After(*validFrom) {
+		return common.RequestValidationError{Message: "valid_until must be after valid_from"}
+	}
+	if !validUntil.After(now) {
+		return common.RequestValidationError{Message: "valid_until must be in the future"}
+	}
+	return nil
+}
+
+// checkEmployee проверяет корректность ID и наличие сотрудника
+func (svc *Service) checkEmployee(ctx context.Context, employeeId int64) error {
+	if employeeId <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", employeeId)}
+	}
+
+	exists, err := svc.repo.EmployeeExists(ctx, employeeId)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", employeeId), Err: err}
+	}
+	if !exists {
+		return common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", employeeId)}
+	}
+	return nil
+}
+
+// checkAssignable проверяет, что сотрудник не уволен и назначение не нарушает правила разделения обязанностей
+func checkAssignable(employeeId int64, status string, conflicts []SodConflictEntity) error {
+	if status == employee.StatusTerminated {
+		return common.ConflictError{Message: fmt.Sprintf("employee %d is terminated and cannot be assigned roles", employeeId)}
+	}
+	if len(conflicts) > 0 {
+		return sodConflictError(employeeId, conflicts)
+	}
+	return nil
+}
+
+// sodConflictError перечисляет нарушенные правила разделения обязанностей
+func sodConflictError(employeeId int64, conflicts []SodConflictEntity) error {
+	rules := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		rules[i] = fmt.Sprintf("'%s' (%s + %s)", conflict.RuleName, conflict.RoleAName, conflict.RoleBName)
+	}
+	return common.ConflictError{
+		Message: fmt.Sprintf("assignment to employee %d violates segregation-of-duties rules: %s", employeeId, strings.Join(rules, ", ")),
+	}
+}
+
+// missingIds возвращает ID из requested, которых нет в found
+func missingIds(requested, found []int64) []int64 {
+	var missing []int64
+	for _, id := range requested {
+		if !slices.Contains(found, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,405 @@
+package assignment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория назначений
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) EmployeeExists(ctx context.Context, employeeId int64) (bool, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) RoleExists(ctx context.Context, roleId int64) (bool, error) {
+	args := m.Called(ctx, roleId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error) {
+	args := m.Called(ctx, roleIds)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepo) FindRolesByEmployeeId(ctx context.Context, employeeId int64) ([]RoleEntity, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]RoleEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindEffectiveRolesByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveRoleEntity, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]EffectiveRoleEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindEmployeesByRoleId(ctx context.Context, roleId int64) ([]EmployeeEntity, error) {
+	args := m.Called(ctx, roleId)
+	return args.Get(0).([]EmployeeEntity), args.Error(1)
+}
+
+// Assign возвращает ошибку из ожидания, а без нее - результат проверки check
+// на статусе сотрудника и нарушенных правилах из ожидания
+func (m *MockRepo) Assign(ctx context.Context, employeeId int64, roleIds []int64, at time.Time, validFrom, validUntil *time.Time, check AssignCheck) error {
+	args := m.Called(ctx, employeeId, roleIds, at, validFrom, validUntil)
+	if err := args.Error(2); err != nil {
+		return err
+	}
+	return check(args.String(0), args.Get(1).([]SodConflictEntity))
+}
+
+func (m *MockRepo) Revoke(ctx context.Context, employeeId int64, roleIds []int64) error {
+	args := m.Called(ctx, employeeId, roleIds)
+	return args.Error(0)
+}
+
+func (m *MockRepo) DeleteExpired(ctx context.Context, at time.Time) ([]Entity, error) {
+	args := m.Called(ctx, at)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+// StubValidator - валидатор, который пропускает все запросы
+type StubValidator struct{}
+
+func (s *StubValidator) Validate(_ any) error {
+	return nil
+}
+
+func (s *StubValidator) ValidateWithCustomMessages(_ any) error {
+	return nil
+}
+
+func TestAssignmentService_FindRolesByEmployee(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return roles of employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		now := time.Now()
+
+		repo.On("EmployeeExists", mock.Anything, int64(1)).Return(true, nil)
+		repo.On("FindRolesByEmployeeId", mock.Anything, int64(1)).Return([]RoleEntity{
+			{Id: 10, Name: "engineer", AssignedAt: now},
+		}, nil)
+
+		got, err := svc.FindRolesByEmployee(context.Background(), 1)
+
+		a.NoError(err)
+		a.Equal([]RoleResponse{{Id: 10, Name: "engineer", AssignedAt: now}}, got)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("EmployeeExists", mock.Anything, int64(42)).Return(false, nil)
+
+		got, err := svc.FindRolesByEmployee(context.Background(), 42)
+
+		a.Nil(got)
+		a.True(errors.As(err, &common.NotFoundError{}))
+		repo.AssertNotCalled(t, "FindRolesByEmployeeId", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject invalid employee id", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		_, err := svc.FindRolesByEmployee(context.Background(), 0)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
+
+func TestAssignmentService_FindEffectiveRolesByEmployee(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should mark inherited roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("EmployeeExists", mock.Anything, int64(1)).Return(true, nil)
+		repo.On("FindEffectiveRolesByEmployeeId", mock.Anything, int64(1)).Return([]EffectiveRoleEntity{
+			{Id: 10, Name: "engineer", Direct: false},
+			{Id: 11, Name: "senior-engineer", Direct: true},
+		}, nil)
+
+		got, err := svc.FindEffectiveRolesByEmployee(context.Background(), 1)
+
+		a.NoError(err)
+		a.Equal([]EffectiveRoleResponse{
+			{Id: 10, Name: "engineer", Inherited: true},
+			{Id: 11, Name: "senior-engineer", Inherited: false},
+		}, got)
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("EmployeeExists", mock.Anything, int64(42)).Return(false, nil)
+
+		_, err := svc.FindEffectiveRolesByEmployee(context.Background(), 42)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		repo.AssertNotCalled(t, "FindEffectiveRolesByEmployeeId", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAssignmentService_FindEmployeesByRole(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return employees with role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		now := time.Now()
+
+		repo.On("RoleExists", mock.Anything, int64(3)).Return(true, nil)
+		repo.On("FindEmployeesByRoleId", mock.Anything, int64(3)).Return([]EmployeeEntity{
+			{Id: 1, Name: "John Doe", AssignedAt: now},
+		}, nil)
+
+		got, err := svc.FindEmployeesByRole(context.Background(), 3)
+
+		a.NoError(err)
+		a.Equal([]EmployeeResponse{{Id: 1, Name: "John Doe", AssignedAt: now}}, got)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("RoleExists", mock.Anything, int64(3)).Return(false, nil)
+
+		_, err := svc.FindEmployeesByRole(context.Background(), 3)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("RoleExists", mock.Anything, int64(3)).Return(false, errors.New("db down"))
+
+		_, err := svc.FindEmployeesByRole(context.Background(), 3)
+
+		a.True(errors.As(err, &common.RepositoryError{}))
+		a.Contains(err.Error(), "db down")
+	})
+}
+
+func TestAssignmentService_AssignRoles(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should assign existing roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{1, 2}
+
+		repo.On("EmployeeExists", mock.Anything, int64(5)).Return(true, nil)
+		repo.On("FindExistingRoleIds", mock.Anything, roleIds).Return([]int64{1, 2}, nil)
+		repo.On("Assign", mock.Anything, int64(5), roleIds, mock.AnythingOfType("time.Time"), (*time.Time)(nil), (*time.Time)(nil)).
+			Return("active", []SodConflictEntity{}, nil)
+		repo.On("FindRolesByEmployeeId", mock.Anything, int64(5)).Return([]RoleEntity{
+			{Id: 1, Name: "engineer"},
+			{Id: 2, Name: "reviewer"},
+		}, nil)
+
+		got, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: roleIds})
+
+		a.NoError(err)
+		a.Len(got, 2)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should report missing roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{1, 2, 3}
+
+		repo.On("FindExistingRoleIds", mock.Anything, roleIds).Return([]int64{1}, nil)
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: roleIds})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		a.Contains(err.Error(), "[2 3]")
+		repo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should assign roles for limited period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{1}
+		validFrom := time.Now().Add(-time.Hour)
+		validUntil := time.Now().Add(24 * time.Hour)
+
+		repo.On("EmployeeExists", mock.Anything, int64(5)).Return(true, nil)
+		repo.On("FindExistingRoleIds", mock.Anything, roleIds).Return([]int64{1}, nil)
+		repo.On("Assign", mock.Anything, int64(5), roleIds, mock.AnythingOfType("time.Time"), &validFrom, &validUntil).
+			Return("active", []SodConflictEntity{}, nil)
+		repo.On("FindRolesByEmployeeId", mock.Anything, int64(5)).Return([]RoleEntity{
+			{Id: 1, Name: "contractor", ValidFrom: &validFrom, ValidUntil: &validUntil},
+		}, nil)
+
+		got, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: roleIds, ValidFrom: &validFrom, ValidUntil: &validUntil})
+
+		a.NoError(err)
+		a.Equal(&validUntil, got[0].ValidUntil)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should refuse grant violating sod rule", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{2}
+
+		repo.On("FindExistingRoleIds", mock.Anything, roleIds).Return([]int64{2}, nil)
+		repo.On("Assign", mock.Anything, int64(5), roleIds, mock.AnythingOfType("time.Time"), (*time.Time)(nil), (*time.Time)(nil)).
+			Return("active", []SodConflictEntity{
+				{RuleId: 1, RuleName: "payments", RoleAName: "payments-approver", RoleBName: "payments-creator"},
+			}, nil)
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: roleIds})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		a.Contains(err.Error(), "'payments' (payments-approver + payments-creator)")
+		repo.AssertNotCalled(t, "FindRolesByEmployeeId", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not assign when sod check fails", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{2}
+
+		repo.On("FindExistingRoleIds", mock.Anything, roleIds).Return([]int64{2}, nil)
+		repo.On("Assign", mock.Anything, int64(5), roleIds, mock.AnythingOfType("time.Time"), (*time.Time)(nil), (*time.Time)(nil)).
+			Return("", []SodConflictEntity(nil), errors.New("db down"))
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: roleIds})
+
+		a.True(errors.As(err, &common.RepositoryError{}))
+		a.Contains(err.Error(), "db down")
+	})
+
+	t.Run("should reject empty validity period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		validFrom := time.Now().Add(time.Hour)
+		validUntil := validFrom
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: []int64{1}, ValidFrom: &validFrom, ValidUntil: &validUntil})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should reject already expired period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		validUntil := time.Now().Add(-time.Minute)
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: []int64{1}, ValidUntil: &validUntil})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should reject terminated employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("FindExistingRoleIds", mock.Anything, []int64{1}).Return([]int64{1}, nil)
+		repo.On("Assign", mock.Anything, int64(5), []int64{1}, mock.AnythingOfType("time.Time"), (*time.Time)(nil), (*time.Time)(nil)).
+			Return("terminated", []SodConflictEntity{}, nil)
+
+		_, err := svc.AssignRoles(context.Background(), 5, AssignRolesRequest{RoleIds: []int64{1}})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		a.Contains(err.Error(), "terminated")
+		repo.AssertNotCalled(t, "FindRolesByEmployeeId", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("FindExistingRoleIds", mock.Anything, []int64{1}).Return([]int64{1}, nil)
+		repo.On("Assign", mock.Anything, int64(42), []int64{1}, mock.AnythingOfType("time.Time"), (*time.Time)(nil), (*time.Time)(nil)).
+			Return("", []SodConflictEntity(nil), sql.ErrNoRows)
+
+		_, err := svc.AssignRoles(context.Background(), 42, AssignRolesRequest{RoleIds: []int64{1}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should not reach repository on validation error", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := AssignRolesRequest{}
+
+		validator.On("ValidateWithCustomMessages", request).Return(errors.New("ids list cannot be empty"))
+
+		_, err := svc.AssignRoles(context.Background(), 5, request)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
+
+func TestAssignmentService_RevokeRoles(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should revoke roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+		roleIds := []int64{1}
+
+		repo.On("EmployeeExists", mock.Anything, int64(5)).Return(true, nil)
+		repo.On("Revoke", mock.Anything, int64(5), roleIds).Return(nil)
+
+		err := svc.RevokeRoles(context.Background(), 5, RevokeRolesRequest{RoleIds: roleIds})
+
+		a.NoError(err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, &StubValidator{})
+
+		repo.On("EmployeeExists", mock.Anything, int64(5)).Return(false, nil)
+
+		err := svc.RevokeRoles(context.Background(), 5, RevokeRolesRequest{RoleIds: []int64{1}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+// MockValidator - mock-объект валидатора
+type MockValidator struct {
+	mock.Mock
+}
+
+func (m *MockValidator) Validate(request any) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockValidator) ValidateWithCustomMessages(request any) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
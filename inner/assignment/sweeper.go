@@ -0,0 +1,110 @@
+package assignment
+
+import (
+	"context"
+	"encoding/json"
+	"idm/inner/audit"
+	"idm/inner/common"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Описание фоновой очистки в журнале аудита
+const (
+	sweeperActor = "system"
+	sweeperName  = "assignment-sweeper"
+	sweeperRoute = "SWEEP expired role assignments"
+)
+
+// Recorder записывает изменения в журнал аудита
+type Recorder interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Sweeper периодически отзывает назначения ролей с истекшим сроком действия
+// и фиксирует каждый отзыв в журнале аудита
+type Sweeper struct {
+	service  *Service
+	recorder Recorder
+	interval time.Duration
+	logger   *common.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewSweeper создает фоновую очистку, которая запускается раз в interval
+func NewSweeper(service *Service, recorder Recorder, interval time.Duration, logger *common.Logger) *Sweeper {
+	return &Sweeper{
+		service:  service,
+		recorder: recorder,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start запускает очистку в отдельной горутине; первая очистка выполняется сразу
+func (s *Sweeper) Start() {
+	go s.run()
+}
+
+// Stop останавливает очистку и дожидается завершения текущего прохода
+func (s *Sweeper) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+func (s *Sweeper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(context.Background())
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep отзывает просроченные назначения и записывает отзыв каждого из них в журнал аудита
+func (s *Sweeper) sweep(ctx context.Context) {
+	revoked, err := s.service.RevokeExpired(ctx, time.Now())
+	if err != nil {
+		s.logger.Error("assignment sweeper: failed to revoke expired assignments", zap.Error(err))
+		return
+	}
+
+	for _, grant := range revoked {
+		before, err := json.Marshal(grant.toResponse())
+		if err != nil {
+			s.logger.Error("assignment sweeper: failed to encode revoked assignment", zap.Error(err))
+			continue
+		}
+		employeeId := grant.EmployeeId
+		entry := audit.Entry{
+			Actor:      sweeperActor,
+			ActorName:  sweeperName,
+			Action:     audit.ActionDelete,
+			Route:      sweeperRoute,
+			TargetType: "employees",
+			TargetId:   &employeeId,
+			Before:     before,
+		}
+		if err := s.recorder.Record(ctx, entry); err != nil {
+			s.logger.Error("assignment sweeper: failed to record revocation", zap.Error(err),
+				zap.Int64("employee_id", grant.EmployeeId), zap.Int64("role_id", grant.RoleId))
+		}
+	}
+	if len(revoked) > 0 {
+		s.logger.Info("assignment sweeper: revoked expired assignments", zap.Int("count", len(revoked)))
+	}
+}
@@ -0,0 +1,98 @@
+package assignment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"idm/inner/audit"
+	"idm/inner/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRecorder - mock-объект журнала аудита
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) Record(ctx context.Context, entry audit.Entry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should record each revoked assignment", func(t *testing.T) {
+		repo := new(MockRepo)
+		recorder := new(MockRecorder)
+		sweeper := NewSweeper(NewService(repo, &StubValidator{}), recorder, time.Minute, common.NewTestLogger())
+		validUntil := time.Now().Add(-time.Minute)
+
+		repo.On("DeleteExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return([]Entity{
+			{EmployeeId: 5, RoleId: 1, ValidUntil: &validUntil},
+			{EmployeeId: 7, RoleId: 2, ValidUntil: &validUntil},
+		}, nil)
+		recorder.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+		sweeper.sweep(context.Background())
+
+		recorder.AssertNumberOfCalls(t, "Record", 2)
+		entry := recorder.Calls[0].Arguments.Get(1).(audit.Entry)
+		a.Equal(audit.ActionDelete, entry.Action)
+		a.Equal("employees", entry.TargetType)
+		a.Equal(int64(5), *entry.TargetId)
+
+		var before GrantResponse
+		a.NoError(json.Unmarshal(entry.Before, &before))
+		a.Equal(int64(1), before.RoleId)
+	})
+
+	t.Run("should not record anything when repository fails", func(t *testing.T) {
+		repo := new(MockRepo)
+		recorder := new(MockRecorder)
+		sweeper := NewSweeper(NewService(repo, &StubValidator{}), recorder, time.Minute, common.NewTestLogger())
+
+		repo.On("DeleteExpired", mock.Anything, mock.Anything).Return([]Entity(nil), errors.New("connection refused"))
+
+		sweeper.sweep(context.Background())
+
+		a.Empty(recorder.Calls)
+	})
+}
+
+func TestSweeper_StartStop(t *testing.T) {
+	repo := new(MockRepo)
+	recorder := new(MockRecorder)
+	sweeper := NewSweeper(NewService(repo, &StubValidator{}), recorder, time.Hour, common.NewTestLogger())
+	swept := make(chan struct{}, 1)
+
+	repo.On("DeleteExpired", mock.Anything, mock.Anything).Return([]Entity{}, nil).Run(func(mock.Arguments) {
+		select {
+		case swept <- struct{}{}:
+		default:
+		}
+	})
+
+	sweeper.Start()
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not run after start")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		sweeper.Stop()
+		sweeper.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop")
+	}
+}
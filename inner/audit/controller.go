@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для просмотра журнала аудита
+type Controller struct {
+	server       *web.Server
+	auditService Svc
+	logger       *common.Logger
+}
+
+// Svc интерфейс сервиса журнала аудита
+type Svc interface {
+	FindPage(ctx context.Context, req PageRequest) (PageResponse, error) // страница журнала
+}
+
+// NewController создает новый экземпляр контроллера журнала аудита
+func NewController(server *web.Server, auditService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:       server,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты журнала аудита
+func (c *Controller) RegisterRoutes() {
+	c.server.GroupApiV1.Get("/audit", c.server.Require(web.AuditRead), c.GetAuditPage)
+}
+
+// GetAuditPage получает страницу журнала аудита
+// @Summary Получить журнал аудита
+// @Description Получить страницу журнала изменений (от новых к старым) с фильтрами
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param pageNumber query int false "Номер страницы"
+// @Param pageSize query int false "Размер страницы"
+// @Param actor query string false "Субъект (sub) или имя пользователя"
+// @Param action query string false "Действие: create, update, delete"
+// @Param targetType query string false "Тип объекта, например employees"
+// @Param targetId query int false "ID объекта"
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода (RFC3339), не включительно"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /audit [get]
+func (c *Controller) GetAuditPage(ctx *fiber.Ctx) error {
+	req, err := parsePageRequest(ctx)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.auditService.FindPage(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get audit page: failed to find records", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning audit records")
+	}
+	return nil
+}
+
+// parsePageRequest читает параметры страницы и фильтры из query-строки
+func parsePageRequest(ctx *fiber.Ctx) (PageRequest, error) {
+	req := PageRequest{
+		Actor:      ctx.Query("actor"),
+		Action:     ctx.Query("action"),
+		TargetType: ctx.Query("targetType"),
+	}
+
+	var err error
+	if req.PageNumber, err = strconv.Atoi(ctx.Query("pageNumber", "0")); err != nil {
+		return PageRequest{}, errors.New("invalid pageNumber")
+	}
+	if req.PageSize, err = strconv.Atoi(ctx.Query("pageSize", "20")); err != nil {
+		return PageRequest{}, errors.New("invalid pageSize")
+	}
+	if req.TargetId, err = strconv.ParseInt(ctx.Query("targetId", "0"), 10, 64); err != nil {
+		return PageRequest{}, errors.New("invalid targetId")
+	}
+	if req.From, err = parseTime(ctx.Query("from")); err != nil {
+		return PageRequest{}, errors.New("invalid from: expected RFC3339 time")
+	}
+	if req.To, err = parseTime(ctx.Query("to")); err != nil {
+		return PageRequest{}, errors.New("invalid to: expected RFC3339 time")
+	}
+	return req, nil
+}
+
+// parseTime разбирает необязательный параметр времени в формате RFC3339
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
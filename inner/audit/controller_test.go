@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditService - мок для интерфейса Svc
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) FindPage(ctx context.Context, req PageRequest) (PageResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(PageResponse), args.Error(1)
+}
+
+// setupControllerTest инициализирует тестовое окружение контроллера
+func setupControllerTest(t *testing.T) (*fiber.App, *MockAuditService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+	mockService := new(MockAuditService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+func getAudit(t *testing.T, app *fiber.App, url string, roles []string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", url, nil)
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	recorder.Code = resp.StatusCode
+	_, _ = recorder.Body.ReadFrom(resp.Body)
+	return recorder
+}
+
+func TestGetAuditPage(t *testing.T) {
+	t.Run("should return page for admin", func(t *testing.T) {
+		app, svc := setupControllerTest(t)
+		expected := PageResponse{Result: []Response{{Id: 1, Action: ActionCreate}}, PageSize: 10, PageNumber: 0, Total: 1}
+		svc.On("FindPage", mock.Anything, mock.MatchedBy(func(req PageRequest) bool {
+			return req.PageSize == 10 && req.Actor == "alice" && req.TargetType == "roles" &&
+				req.TargetId == 3 && req.From != nil && req.To == nil
+		})).Return(expected, nil)
+
+		resp := getAudit(t, app, "/api/v1/audit?pageSize=10&actor=alice&targetType=roles&targetId=3&from=2025-06-01T00:00:00Z", []string{web.IdmAdmin})
+		assert.Equal(t, 200, resp.Code)
+
+		var result common.Response[PageResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, int64(1), result.Data.Total)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupControllerTest(t)
+
+		resp := getAudit(t, app, "/api/v1/audit", []string{web.IdmUser})
+		assert.Equal(t, 403, resp.Code)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 400 for invalid time", func(t *testing.T) {
+		app, svc := setupControllerTest(t)
+
+		resp := getAudit(t, app, "/api/v1/audit?from=yesterday", []string{web.IdmAdmin})
+		assert.Equal(t, 400, resp.Code)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 400 for validation error", func(t *testing.T) {
+		app, svc := setupControllerTest(t)
+		svc.On("FindPage", mock.Anything, mock.Anything).Return(PageResponse{}, common.RequestValidationError{Message: "invalid action"})
+
+		resp := getAudit(t, app, "/api/v1/audit?action=read", []string{web.IdmAdmin})
+		assert.Equal(t, 400, resp.Code)
+	})
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Действия, которые фиксируются в журнале аудита
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entity представляет запись журнала аудита в базе данных
+type Entity struct {
+	Id          int64     `db:"id"`
+	OccurredAt  time.Time `db:"occurred_at"`
+	Actor       string    `db:"actor"`
+	ActorName   string    `db:"actor_name"`
+	Action      string    `db:"action"`
+	Route       string    `db:"route"`
+	TargetType  string    `db:"target_type"`
+	TargetId    *int64    `db:"target_id"`
+	BeforeState []byte    `db:"before_state"`
+	AfterState  []byte    `db:"after_state"`
+	RequestBody []byte    `db:"request_body"`
+	RequestId   string    `db:"request_id"`
+}
+
+// toResponse преобразует Entity в Response
+func (e *Entity) toResponse() Response {
+	return Response{
+		Id:         e.Id,
+		OccurredAt: e.OccurredAt,
+		Actor:      e.Actor,
+		ActorName:  e.ActorName,
+		Action:     e.Action,
+		Route:      e.Route,
+		TargetType: e.TargetType,
+		TargetId:   e.TargetId,
+		Before:     rawJson(e.BeforeState),
+		After:      rawJson(e.AfterState),
+		Request:    rawJson(e.RequestBody),
+		RequestId:  e.RequestId,
+	}
+}
+
+// Response представляет ответ API для записи журнала аудита
+type Response struct {
+	Id         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"`
+	Route      string          `json:"route"`
+	TargetType string          `json:"target_type"`
+	TargetId   *int64          `json:"target_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	Request    json.RawMessage `json:"request" swaggertype:"object"`
+	RequestId  string          `json:"request_id"`
+}
+
+// PageResponse представляет страницу записей журнала аудита
+type PageResponse struct {
+	Result     []Response `json:"result"`
+	PageSize   int        `json:"page_size"`
+	PageNumber int        `json:"page_number"`
+	Total      int64      `json:"total"`
+}
+
+// Entry описывает изменение, которое нужно зафиксировать в журнале
+type Entry struct {
+	Actor      string
+	ActorName  string
+	Action     string
+	Route      string
+	TargetType string
+	TargetId   *int64
+	Before     json.RawMessage
+	After      json.RawMessage
+	Request    json.RawMessage
+	RequestId  string
+}
+
+// rawJson возвращает сохраненный JSON или nil (null в ответе), если снимка нет
+func rawJson(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
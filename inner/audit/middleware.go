@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// apiPrefix префикс путей, относительно которого определяется тип объекта аудита
+const apiPrefix = "/api/v1/"
+
+// Recorder интерфейс сервиса, в который middleware записывает изменения
+type Recorder interface {
+	Snapshot(ctx context.Context, targetType string, id int64) json.RawMessage
+	Record(ctx context.Context, entry Entry) error
+}
+
+// actions сопоставляет изменяющим HTTP-методам действие аудита
+var actions = map[string]string{
+	fiber.MethodPost:   ActionCreate,
+	fiber.MethodPut:    ActionUpdate,
+	fiber.MethodPatch:  ActionUpdate,
+	fiber.MethodDelete: ActionDelete,
+}
+
+// sensitiveFields поля, которые не попадают в журнал (например, значение выпущенного API-ключа)
+var sensitiveFields = []string{"key"}
+
+// Middleware фиксирует в журнале аудита каждый успешный изменяющий запрос к API:
+// кто (claims токена), что (действие и маршрут), над каким объектом, его состояние до и после, request id.
+// Тип и ID объекта берутся из пути /api/v1/{type}/{id}/..., снимок "до" - из зарегистрированного SnapshotFunc
+// после проверки прав в web.Server.Require, снимок "после" - из поля data ответа.
+// Запросы, отмеченные обработчиком через web.SkipAudit, не фиксируются.
+// Ошибка записи в журнал логируется и не влияет на ответ клиенту
+func Middleware(recorder Recorder, logger *common.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		action, ok := actions[ctx.Method()]
+		if !ok {
+			return ctx.Next()
+		}
+
+		targetType, targetId := parseTarget(ctx.Path())
+		var before json.RawMessage
+		if targetId != nil {
+			id := *targetId
+			// снимок читается только после проверки прав в Require
+			web.OnAuthorized(ctx, func() {
+				before = recorder.Snapshot(ctx.Context(), targetType, id)
+			})
+		}
+		request := redact(jsonOrNil(ctx.Body()))
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if status := ctx.Response().StatusCode(); status < 200 || status >= 300 || web.AuditSkipped(ctx) {
+			return nil
+		}
+
+		after := redact(responseData(ctx.Response().Body()))
+		if targetId == nil {
+			targetId = idFromSnapshot(after)
+		}
+
+		entry := Entry{
+			Action:     action,
+			Route:      ctx.Method() + " " + ctx.Route().Path,
+			TargetType: targetType,
+			TargetId:   targetId,
+			Before:     before,
+			After:      after,
+			Request:    request,
+			RequestId:  ctx.GetRespHeader(fiber.HeaderXRequestID),
+		}
+		if claims, err := web.GetClaims(ctx); err == nil {
+			entry.Actor = claims.Subject
+			entry.ActorName = claims.PreferredUsername
+		}
+
+		if err := recorder.Record(ctx.Context(), entry); err != nil {
+			logger.ErrorCtx(ctx.Context(), "audit: failed to record change", zap.Error(err), zap.String("route", entry.Route))
+		}
+		return nil
+	}
+}
+
+// parseTarget определяет тип и ID объекта по пути запроса: /api/v1/employees/5/roles -> ("employees", 5)
+func parseTarget(path string) (string, *int64) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, apiPrefix), "/"), "/")
+	if len(segments) < 2 {
+		return segments[0], nil
+	}
+	id, err := strconv.ParseInt(segments[1], 10, 64)
+	if err != nil || id <= 0 {
+		return segments[0], nil
+	}
+	return segments[0], &id
+}
+
+// responseData извлекает поле data из ответа в формате common.Response
+func responseData(body []byte) json.RawMessage {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || bytes.Equal(response.Data, []byte("null")) {
+		return nil
+	}
+	return response.Data
+}
+
+// idFromSnapshot возвращает ID созданного объекта из снимка "после", если он там есть
+func idFromSnapshot(snapshot json.RawMessage) *int64 {
+	var object struct {
+		Id int64 `json:"id"`
+	}
+	if err := json.Unmarshal(snapshot, &object); err != nil || object.Id <= 0 {
+		return nil
+	}
+	return &object.Id
+}
+
+// jsonOrNil копирует тело запроса, если это корректный JSON
+func jsonOrNil(body []byte) json.RawMessage {
+	if len(body) == 0 || !json.Valid(body) {
+		return nil
+	}
+	return bytes.Clone(body)
+}
+
+// redact удаляет чувствительные поля верхнего уровня из JSON-объекта
+func redact(data json.RawMessage) json.RawMessage {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return data
+	}
+
+	changed := false
+	for _, field := range sensitiveFields {
+		if _, ok := object[field]; ok {
+			delete(object, field)
+			changed = true
+		}
+	}
+	if !changed {
+		return data
+	}
+
+	redacted, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// StubRecorder - заглушка Recorder, запоминающая записанные изменения
+type StubRecorder struct {
+	mu        sync.Mutex
+	snapshots map[int64]json.RawMessage
+	entries   []Entry
+	// snapshotCalls - количество чтений снимка "до"
+	snapshotCalls int
+}
+
+func (s *StubRecorder) Snapshot(_ context.Context, _ string, id int64) json.RawMessage {
+	s.snapshotCalls++
+	return s.snapshots[id]
+}
+
+func (s *StubRecorder) Record(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+// setupMiddlewareTest собирает сервер с middleware аудита и тестовыми маршрутами
+func setupMiddlewareTest(t *testing.T) (*fiber.App, *StubRecorder) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+	recorder := &StubRecorder{snapshots: map[int64]json.RawMessage{5: []byte(`{"id":5,"name":"old"}`)}}
+	server.GroupApiV1.Use(Middleware(recorder, logger))
+
+	api := server.GroupApiV1
+	api.Post("/roles", func(ctx *fiber.Ctx) error {
+		return common.OkResponse(ctx, fiber.Map{"id": 9, "name": "new"})
+	})
+	api.Put("/roles/:id", server.Require(web.RoleWrite), func(ctx *fiber.Ctx) error {
+		return common.OkResponse(ctx, fiber.Map{"id": 5, "name": "new"})
+	})
+	api.Delete("/roles/:id", server.Require(web.RoleDelete), func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusNoContent)
+	})
+	api.Post("/roles/by-ids", func(ctx *fiber.Ctx) error {
+		web.SkipAudit(ctx)
+		return common.OkResponse(ctx, []fiber.Map{{"id": 5}})
+	})
+	api.Get("/roles/:id", func(ctx *fiber.Ctx) error {
+		return common.OkResponse(ctx, fiber.Map{"id": 5})
+	})
+	api.Post("/api-keys", func(ctx *fiber.Ctx) error {
+		return common.OkResponse(ctx, fiber.Map{"id": 2, "name": "ci", "key": "secret"})
+	})
+	api.Post("/fail", func(ctx *fiber.Ctx) error {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "bad request")
+	})
+	return server.App, recorder
+}
+
+func sendRequest(t *testing.T, app *fiber.App, method, url, body string) int {
+	t.Helper()
+	return sendRequestAs(t, app, method, url, body, web.IdmAdmin)
+}
+
+func sendRequestAs(t *testing.T, app *fiber.App, method, url, body string, role string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken([]string{role}))
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Run("should record create with id from response", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 200, sendRequest(t, app, "POST", "/api/v1/roles", `{"name":"new"}`))
+		assert.Len(t, recorder.entries, 1)
+		entry := recorder.entries[0]
+		assert.Equal(t, ActionCreate, entry.Action)
+		assert.Equal(t, "POST /api/v1/roles", entry.Route)
+		assert.Equal(t, "roles", entry.TargetType)
+		assert.Equal(t, int64(9), *entry.TargetId)
+		assert.Nil(t, entry.Before)
+		assert.JSONEq(t, `{"id":9,"name":"new"}`, string(entry.After))
+		assert.JSONEq(t, `{"name":"new"}`, string(entry.Request))
+		assert.NotEmpty(t, entry.RequestId)
+	})
+
+	t.Run("should record update with before and after state", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 200, sendRequest(t, app, "PUT", "/api/v1/roles/5", `{"name":"new"}`))
+		assert.Len(t, recorder.entries, 1)
+		entry := recorder.entries[0]
+		assert.Equal(t, ActionUpdate, entry.Action)
+		assert.Equal(t, "PUT /api/v1/roles/:id", entry.Route)
+		assert.Equal(t, int64(5), *entry.TargetId)
+		assert.JSONEq(t, `{"id":5,"name":"old"}`, string(entry.Before))
+		assert.JSONEq(t, `{"id":5,"name":"new"}`, string(entry.After))
+	})
+
+	t.Run("should record delete without response body", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 204, sendRequest(t, app, "DELETE", "/api/v1/roles/5", ""))
+		assert.Len(t, recorder.entries, 1)
+		assert.Equal(t, ActionDelete, recorder.entries[0].Action)
+		assert.NotNil(t, recorder.entries[0].Before)
+		assert.Nil(t, recorder.entries[0].After)
+	})
+
+	t.Run("should redact issued api key", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 200, sendRequest(t, app, "POST", "/api/v1/api-keys", `{"name":"ci"}`))
+		assert.Len(t, recorder.entries, 1)
+		assert.JSONEq(t, `{"id":2,"name":"ci"}`, string(recorder.entries[0].After))
+	})
+
+	t.Run("should skip reads and failed requests", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 200, sendRequest(t, app, "GET", "/api/v1/roles/5", ""))
+		assert.Equal(t, 400, sendRequest(t, app, "POST", "/api/v1/fail", `{}`))
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("should skip requests marked by handler", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 200, sendRequest(t, app, "POST", "/api/v1/roles/by-ids", `{"ids":[5]}`))
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("should not snapshot before authorization", func(t *testing.T) {
+		app, recorder := setupMiddlewareTest(t)
+
+		assert.Equal(t, 403, sendRequestAs(t, app, "PUT", "/api/v1/roles/5", `{"name":"new"}`, web.IdmUser))
+		assert.Empty(t, recorder.entries)
+		assert.Zero(t, recorder.snapshotCalls)
+	})
+}
+
+func TestParseTarget(t *testing.T) {
+	targetType, id := parseTarget("/api/v1/employees/7/roles")
+	assert.Equal(t, "employees", targetType)
+	assert.Equal(t, int64(7), *id)
+
+	targetType, id = parseTarget("/api/v1/employees/batch")
+	assert.Equal(t, "employees", targetType)
+	assert.Nil(t, id)
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository представляет репозиторий журнала аудита. Записи только добавляются и читаются
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// Add добавляет запись в журнал аудита
+func (r *Repository) Add(ctx context.Context, e *Entity) error {
+	query := `INSERT INTO audit_log (occurred_at, actor, actor_name, action, route, target_type, target_id,
+			before_state, after_state, request_body, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, e.OccurredAt, e.Actor, e.ActorName, e.Action, e.Route, e.TargetType, e.TargetId,
+		jsonParam(e.BeforeState), jsonParam(e.AfterState), jsonParam(e.RequestBody), e.RequestId).Scan(&e.Id)
+}
+
+// FindPage возвращает страницу записей журнала, отфильтрованных по запросу, от новых к старым
+func (r *Repository) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	where, args := buildFilter(req)
+	args = append(args, req.PageSize, req.PageNumber*req.PageSize)
+	query := fmt.Sprintf("SELECT * FROM audit_log%s ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d", where, len(args)-1, len(args))
+	var res []Entity
+	err := r.db.SelectContext(ctx, &res, query, args...)
+	return res, err
+}
+
+// CountAll возвращает количество записей журнала, подходящих под фильтры запроса
+func (r *Repository) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	where, args := buildFilter(req)
+	var total int64
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM audit_log"+where, args...)
+	return total, err
+}
+
+// buildFilter формирует условие WHERE и его параметры из непустых фильтров запроса
+func buildFilter(req PageRequest) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if req.Actor != "" {
+		add("(actor = $%[1]d OR actor_name = $%[1]d)", req.Actor)
+	}
+	if req.Action != "" {
+		add("action = $%d", req.Action)
+	}
+	if req.TargetType != "" {
+		add("target_type = $%d", req.TargetType)
+	}
+	if req.TargetId > 0 {
+		add("target_id = $%d", req.TargetId)
+	}
+	if req.From != nil {
+		add("occurred_at >= $%d", *req.From)
+	}
+	if req.To != nil {
+		add("occurred_at < $%d", *req.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// jsonParam передает снимок в запрос как текст: []byte драйвер отправил бы как bytea
+func jsonParam(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
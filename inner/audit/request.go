@@ -0,0 +1,15 @@
+package audit
+
+import "time"
+
+// PageRequest используется для постраничного просмотра журнала аудита с фильтрами
+type PageRequest struct {
+	PageSize   int        `json:"pageSize" validate:"min=1,max=100"`
+	PageNumber int        `json:"pageNumber" validate:"min=0"`
+	Actor      string     `json:"actor"`
+	Action     string     `json:"action" validate:"omitempty,oneof=create update delete"`
+	TargetType string     `json:"targetType"`
+	TargetId   int64      `json:"targetId" validate:"min=0"`
+	From       *time.Time `json:"from"`
+	To         *time.Time `json:"to"`
+}
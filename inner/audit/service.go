@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"sync"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику журнала аудита
+type Service struct {
+	repo      Repo
+	validator Validator
+
+	mu        sync.RWMutex
+	snapshots map[string]SnapshotFunc
+}
+
+// Repo интерфейс репозитория журнала аудита
+type Repo interface {
+	Add(ctx context.Context, e *Entity) error
+	FindPage(ctx context.Context, req PageRequest) ([]Entity, error)
+	CountAll(ctx context.Context, req PageRequest) (int64, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// SnapshotFunc возвращает текущее состояние объекта для снимка "до" изменения
+type SnapshotFunc func(ctx context.Context, id int64) (any, error)
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+		snapshots: make(map[string]SnapshotFunc),
+	}
+}
+
+// RegisterSnapshot задает способ получения снимка объектов типа targetType
+// (тип совпадает с первым сегментом пути API, например "employees")
+func (svc *Service) RegisterSnapshot(targetType string, snapshot SnapshotFunc) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.snapshots[targetType] = snapshot
+}
+
+// Snapshot возвращает JSON-снимок объекта или nil, если снимок получить нельзя
+// (тип не зарегистрирован, объект не найден)
+func (svc *Service) Snapshot(ctx context.Context, targetType string, id int64) json.RawMessage {
+	svc.mu.RLock()
+	snapshot, ok := svc.snapshots[targetType]
+	svc.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	state, err := snapshot(ctx, id)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Record добавляет запись в журнал аудита
+func (svc *Service) Record(ctx context.Context, entry Entry) error {
+	entity := &Entity{
+		OccurredAt:  time.Now(),
+		Actor:       entry.Actor,
+		ActorName:   entry.ActorName,
+		Action:      entry.Action,
+		Route:       entry.Route,
+		TargetType:  entry.TargetType,
+		TargetId:    entry.TargetId,
+		BeforeState: entry.Before,
+		AfterState:  entry.After,
+		RequestBody: entry.Request,
+		RequestId:   entry.RequestId,
+	}
+	if err := svc.repo.Add(ctx, entity); err != nil {
+		return common.RepositoryError{Message: "error adding audit record", Err: err}
+	}
+	return nil
+}
+
+// FindPage возвращает страницу журнала аудита с учетом фильтров
+func (svc *Service) FindPage(ctx context.Context, req PageRequest) (PageResponse, error) {
+	if err := svc.validator.ValidateWithCustomMessages(req); err != nil {
+		return PageResponse{}, common.RequestValidationError{Message: err.Error()}
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return PageResponse{}, common.RequestValidationError{Message: "from must be earlier than to"}
+	}
+
+	entities, err := svc.repo.FindPage(ctx, req)
+	if err != nil {
+		return PageResponse{}, common.RepositoryError{Message: "error finding audit records", Err: err}
+	}
+	total, err := svc.repo.CountAll(ctx, req)
+	if err != nil {
+		return PageResponse{}, common.RepositoryError{Message: "error counting audit records", Err: err}
+	}
+
+	responses := make([]Response, len(entities))
+	for i, e := range entities {
+		responses[i] = e.toResponse()
+	}
+	return PageResponse{
+		Result:     responses,
+		PageSize:   req.PageSize,
+		PageNumber: req.PageNumber,
+		Total:      total,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория журнала аудита
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAuditService_Record(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should save entry with snapshots", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		id := int64(5)
+		entry := Entry{
+			Actor:      "user-1",
+			Action:     ActionUpdate,
+			Route:      "PUT /api/v1/roles/:id",
+			TargetType: "roles",
+			TargetId:   &id,
+			Before:     []byte(`{"name":"old"}`),
+			After:      []byte(`{"name":"new"}`),
+		}
+		repo.On("Add", ctx, mock.MatchedBy(func(e *Entity) bool {
+			return e.Actor == "user-1" && e.Action == ActionUpdate && *e.TargetId == 5 &&
+				string(e.BeforeState) == `{"name":"old"}` && string(e.AfterState) == `{"name":"new"}` &&
+				!e.OccurredAt.IsZero()
+		})).Return(nil)
+
+		assert.NoError(t, svc.Record(ctx, entry))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Add", ctx, mock.Anything).Return(errors.New("database error"))
+
+		err := svc.Record(ctx, Entry{Action: ActionCreate})
+		assert.ErrorAs(t, err, &common.RepositoryError{})
+	})
+}
+
+func TestAuditService_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(new(MockRepo), validator.New())
+	svc.RegisterSnapshot("roles", func(ctx context.Context, id int64) (any, error) {
+		if id == 404 {
+			return nil, common.NotFoundError{Message: "role not found"}
+		}
+		return map[string]any{"id": id, "name": "admin"}, nil
+	})
+
+	assert.JSONEq(t, `{"id":1,"name":"admin"}`, string(svc.Snapshot(ctx, "roles", 1)))
+	assert.Nil(t, svc.Snapshot(ctx, "roles", 404))
+	assert.Nil(t, svc.Snapshot(ctx, "unknown", 1))
+}
+
+func TestAuditService_FindPage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return page with total", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		req := PageRequest{PageSize: 10, PageNumber: 1, Action: ActionDelete}
+		repo.On("FindPage", ctx, req).Return([]Entity{{Id: 3, Action: ActionDelete, AfterState: []byte(`{}`)}}, nil)
+		repo.On("CountAll", ctx, req).Return(int64(11), nil)
+
+		got, err := svc.FindPage(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), got.Total)
+		assert.Len(t, got.Result, 1)
+		assert.Nil(t, got.Result[0].Before)
+		assert.JSONEq(t, `{}`, string(got.Result[0].After))
+	})
+
+	t.Run("should reject invalid action", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindPage(ctx, PageRequest{PageSize: 10, Action: "read"})
+		assert.ErrorAs(t, err, &common.RequestValidationError{})
+		repo.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject empty period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+		to := from.Add(-time.Hour)
+
+		_, err := svc.FindPage(ctx, PageRequest{PageSize: 10, From: &from, To: &to})
+		assert.ErrorAs(t, err, &common.RequestValidationError{})
+		assert.Contains(t, err.Error(), "from must be earlier than to")
+	})
+}
@@ -0,0 +1,275 @@
+package certification
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с кампаниями пересмотра доступа
+type Controller struct {
+	server               *web.Server
+	certificationService Svc
+	logger               *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с кампаниями пересмотра доступа
+type Svc interface {
+	Launch(ctx context.Context, actor string, request LaunchRequest) (CampaignResponse, error)                         // запуск кампании
+	FindById(ctx context.Context, id int64) (CampaignResponse, error)                                                  // кампания по ID
+	FindAll(ctx context.Context) ([]CampaignResponse, error)                                                           // все кампании
+	FindItems(ctx context.Context, campaignId int64, decision string) ([]ItemResponse, error)                          // назначения кампании
+	Decide(ctx context.Context, actor string, campaignId, itemId int64, request DecisionRequest) (ItemResponse, error) // решение по назначению
+	Close(ctx context.Context, actor string, id int64) (CampaignResponse, error)                                       // закрытие с отзывом
+}
+
+// NewController создает новый экземпляр контроллера кампаний пересмотра доступа
+func NewController(server *web.Server, certificationService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:               server,
+		certificationService: certificationService,
+		logger:               logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с кампаниями пересмотра доступа
+func (c *Controller) RegisterRoutes() {
+	api := c.server.GroupApiV1
+
+	api.Post("/certifications", c.server.Require(web.CertificationManage), c.LaunchCampaign)
+	api.Get("/certifications", c.server.Require(web.CertificationRead), c.GetAllCampaigns)
+	api.Get("/certifications/:id", c.server.Require(web.CertificationRead), c.GetCampaign)
+	api.Get("/certifications/:id/items", c.server.Require(web.CertificationRead), c.GetCampaignItems)
+	api.Put("/certifications/:id/items/:itemId", c.server.Require(web.CertificationReview), c.DecideItem)
+	api.Post("/certifications/:id/close", c.server.Require(web.CertificationManage), c.CloseCampaign)
+}
+
+// LaunchCampaign запускает кампанию пересмотра доступа
+// @Summary Запустить кампанию пересмотра доступа
+// @Description Запустить кампанию по роли (role_id) или подразделению вместе с вложенными (org_unit_id).
+// @Description В кампанию попадает снимок действующих на момент запуска назначений
+// @Tags certification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body certification.LaunchRequest true "данные кампании"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /certifications [post]
+func (c *Controller) LaunchCampaign(ctx *fiber.Ctx) error {
+	var req LaunchRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "launch campaign: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.certificationService.Launch(ctx.Context(), actorName(ctx), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "launch campaign: failed to launch campaign", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning launched campaign")
+	}
+	return nil
+}
+
+// GetAllCampaigns получает все кампании пересмотра доступа
+// @Summary Получить все кампании пересмотра доступа
+// @Description Получить кампании с ходом проверки, начиная с новых
+// @Tags certification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /certifications [get]
+func (c *Controller) GetAllCampaigns(ctx *fiber.Ctx) error {
+	resp, err := c.certificationService.FindAll(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get all campaigns: failed to find campaigns", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning campaigns")
+	}
+	return nil
+}
+
+// GetCampaign получает кампанию пересмотра доступа по ID
+// @Summary Получить кампанию пересмотра доступа по ID
+// @Description Получить кампанию с ходом проверки; у закрытой кампании есть сохраненные итоги
+// @Tags certification
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID кампании"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /certifications/{id} [get]
+func (c *Controller) GetCampaign(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid campaign id")
+	}
+
+	resp, err := c.certificationService.FindById(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get campaign: failed to find campaign", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning campaign")
+	}
+	return nil
+}
+
+// GetCampaignItems получает назначения в составе кампании
+// @Summary Получить назначения кампании
+// @Description Получить назначения кампании; фильтр decision: pending (без решения), keep или revoke
+// @Tags certification
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID кампании"
+// @Param decision query string false "решение: pending, keep, revoke"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /certifications/{id}/items [get]
+func (c *Controller) GetCampaignItems(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid campaign id")
+	}
+
+	resp, err := c.certificationService.FindItems(ctx.Context(), id, ctx.Query("decision"))
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get campaign items: failed to find items", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning campaign items")
+	}
+	return nil
+}
+
+// DecideItem сохраняет решение по назначению
+// @Summary Принять решение по назначению
+// @Description Оставить (keep) или отозвать (revoke) назначение; пока кампания открыта, решение можно изменить.
+// @Description Отзыв выполняется при закрытии кампании
+// @Tags certification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID кампании"
+// @Param itemId path int true "ID назначения в кампании"
+// @Param request body certification.DecisionRequest true "решение"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /certifications/{id}/items/{itemId} [put]
+func (c *Controller) DecideItem(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid campaign id")
+	}
+	itemId, err := strconv.ParseInt(ctx.Params("itemId"), 10, 64)
+	if err != nil || itemId <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid item id")
+	}
+
+	var req DecisionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "decide item: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.certificationService.Decide(ctx.Context(), actorName(ctx), id, itemId, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "decide item: failed to save decision", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning decided item")
+	}
+	return nil
+}
+
+// CloseCampaign закрывает кампанию
+// @Summary Закрыть кампанию пересмотра доступа
+// @Description Закрыть кампанию: отозвать назначения с решением revoke и сохранить итоги. Назначения без решения сохраняются
+// @Tags certification
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID кампании"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /certifications/{id}/close [post]
+func (c *Controller) CloseCampaign(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid campaign id")
+	}
+
+	resp, err := c.certificationService.Close(ctx.Context(), actorName(ctx), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "close campaign: failed to close campaign", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning closed campaign")
+	}
+	return nil
+}
+
+// actorName возвращает имя пользователя запроса для истории кампании: логин, а если его нет - subject токена
+func actorName(ctx *fiber.Ctx) string {
+	claims, err := web.GetClaims(ctx)
+	if err != nil {
+		return ""
+	}
+	if claims.PreferredUsername != "" {
+		return claims.PreferredUsername
+	}
+	return claims.Subject
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	case errors.As(err, &common.ConflictError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
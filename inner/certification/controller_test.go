@@ -0,0 +1,180 @@
+package certification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCertificationService - полный мок для интерфейса Svc
+type MockCertificationService struct {
+	mock.Mock
+}
+
+func (m *MockCertificationService) Launch(ctx context.Context, actor string, request LaunchRequest) (CampaignResponse, error) {
+	args := m.Called(ctx, actor, request)
+	return args.Get(0).(CampaignResponse), args.Error(1)
+}
+
+func (m *MockCertificationService) FindById(ctx context.Context, id int64) (CampaignResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(CampaignResponse), args.Error(1)
+}
+
+func (m *MockCertificationService) FindAll(ctx context.Context) ([]CampaignResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]CampaignResponse), args.Error(1)
+}
+
+func (m *MockCertificationService) FindItems(ctx context.Context, campaignId int64, decision string) ([]ItemResponse, error) {
+	args := m.Called(ctx, campaignId, decision)
+	return args.Get(0).([]ItemResponse), args.Error(1)
+}
+
+func (m *MockCertificationService) Decide(ctx context.Context, actor string, campaignId, itemId int64, request DecisionRequest) (ItemResponse, error) {
+	args := m.Called(ctx, actor, campaignId, itemId, request)
+	return args.Get(0).(ItemResponse), args.Error(1)
+}
+
+func (m *MockCertificationService) Close(ctx context.Context, actor string, id int64) (CampaignResponse, error) {
+	args := m.Called(ctx, actor, id)
+	return args.Get(0).(CampaignResponse), args.Error(1)
+}
+
+func setupTest(t *testing.T) (*fiber.App, *MockCertificationService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockCertificationService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestLaunchCampaign(t *testing.T) {
+	t.Run("should launch campaign for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		roleId := int64(3)
+		request := LaunchRequest{Name: "Q3 payments", RoleId: &roleId}
+		svc.On("Launch", mock.Anything, mock.Anything, request).Return(CampaignResponse{Id: 1, Status: StatusOpen}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/certifications", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/certifications", LaunchRequest{Name: "Q3"}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestGetCampaignItems(t *testing.T) {
+	t.Run("should pass decision filter", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindItems", mock.Anything, int64(1), "pending").Return([]ItemResponse{{Id: 7}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/certifications/1/items?decision=pending", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+}
+
+func TestDecideItem(t *testing.T) {
+	t.Run("should save decision", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := DecisionRequest{Decision: DecisionRevoke}
+		decision := DecisionRevoke
+		svc.On("Decide", mock.Anything, mock.Anything, int64(1), int64(7), request).Return(ItemResponse{Id: 7, Decision: &decision}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "PUT", "/api/v1/certifications/1/items/7", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid item id", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "PUT", "/api/v1/certifications/1/items/abc", DecisionRequest{Decision: DecisionKeep}, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 409 for closed campaign", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Decide", mock.Anything, mock.Anything, int64(1), int64(7), mock.Anything).
+			Return(ItemResponse{}, common.ConflictError{Message: "certification campaign 1 is already closed"})
+
+		resp, err := app.Test(createAuthRequest(t, "PUT", "/api/v1/certifications/1/items/7", DecisionRequest{Decision: DecisionKeep}, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestCloseCampaign(t *testing.T) {
+	t.Run("should close campaign", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Close", mock.Anything, mock.Anything, int64(1)).
+			Return(CampaignResponse{Id: 1, Status: StatusClosed, Summary: &Summary{Total: 2, Revoked: 1, Kept: 1}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/certifications/1/close", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[CampaignResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, 1, result.Data.Summary.Revoked)
+	})
+
+	t.Run("should return 404 for missing campaign", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Close", mock.Anything, mock.Anything, int64(9)).
+			Return(CampaignResponse{}, common.NotFoundError{Message: "certification campaign with id 9 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/certifications/9/close", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
@@ -0,0 +1,162 @@
+package certification
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Охват кампании
+const (
+	ScopeRole    = "role"
+	ScopeOrgUnit = "org_unit"
+)
+
+// Статусы кампании
+const (
+	StatusOpen   = "open"
+	StatusClosed = "closed"
+)
+
+// Решения по назначению
+const (
+	DecisionKeep   = "keep"
+	DecisionRevoke = "revoke"
+)
+
+// CampaignEntity представляет кампанию пересмотра доступа вместе с ходом проверки
+type CampaignEntity struct {
+	Id         int64      `db:"id"`
+	Name       string     `db:"name"`
+	ScopeType  string     `db:"scope_type"`
+	ScopeId    int64      `db:"scope_id"`
+	Status     string     `db:"status"`
+	LaunchedBy string     `db:"launched_by"`
+	ClosedBy   string     `db:"closed_by"`
+	Summary    []byte     `db:"summary"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ClosedAt   *time.Time `db:"closed_at"`
+	Total      int        `db:"total"`
+	Kept       int        `db:"kept"`
+	Revoked    int        `db:"revoked"`
+}
+
+// toResponse преобразует CampaignEntity в CampaignResponse
+func (e *CampaignEntity) toResponse() CampaignResponse {
+	resp := CampaignResponse{
+		Id:         e.Id,
+		Name:       e.Name,
+		Scope:      Scope{Type: e.ScopeType, Id: e.ScopeId},
+		Status:     e.Status,
+		LaunchedBy: e.LaunchedBy,
+		ClosedBy:   e.ClosedBy,
+		CreatedAt:  e.CreatedAt,
+		ClosedAt:   e.ClosedAt,
+		Progress: Summary{
+			Total:     e.Total,
+			Kept:      e.Kept,
+			Revoked:   e.Revoked,
+			Undecided: e.Total - e.Kept - e.Revoked,
+		},
+	}
+	if len(e.Summary) > 0 {
+		var summary Summary
+		if err := json.Unmarshal(e.Summary, &summary); err == nil {
+			resp.Summary = &summary
+		}
+	}
+	return resp
+}
+
+// ItemEntity представляет назначение роли в составе кампании.
+// EmployeeId и RoleId равны nil, если сотрудник или роль окончательно удалены; имена при этом сохраняются
+type ItemEntity struct {
+	Id           int64      `db:"id"`
+	CampaignId   int64      `db:"campaign_id"`
+	EmployeeId   *int64     `db:"employee_id"`
+	EmployeeName string     `db:"employee_name"`
+	RoleId       *int64     `db:"role_id"`
+	RoleName     string     `db:"role_name"`
+	AssignedAt   *time.Time `db:"assigned_at"`
+	ValidUntil   *time.Time `db:"valid_until"`
+	Decision     *string    `db:"decision"`
+	Comment      string     `db:"comment"`
+	ReviewedBy   string     `db:"reviewed_by"`
+	ReviewedAt   *time.Time `db:"reviewed_at"`
+}
+
+// toResponse преобразует ItemEntity в ItemResponse
+func (e *ItemEntity) toResponse() ItemResponse {
+	return ItemResponse{
+		Id:           e.Id,
+		CampaignId:   e.CampaignId,
+		EmployeeId:   e.EmployeeId,
+		EmployeeName: e.EmployeeName,
+		RoleId:       e.RoleId,
+		RoleName:     e.RoleName,
+		AssignedAt:   e.AssignedAt,
+		ValidUntil:   e.ValidUntil,
+		Decision:     e.Decision,
+		Comment:      e.Comment,
+		ReviewedBy:   e.ReviewedBy,
+		ReviewedAt:   e.ReviewedAt,
+	}
+}
+
+// RevokedEntity представляет назначение, отозванное при закрытии кампании
+type RevokedEntity struct {
+	EmployeeId int64 `db:"employee_id"`
+	RoleId     int64 `db:"role_id"`
+}
+
+// RevokedResponse представляет отозванное назначение в журнале аудита
+type RevokedResponse struct {
+	CampaignId int64 `json:"campaign_id"`
+	EmployeeId int64 `json:"employee_id"`
+	RoleId     int64 `json:"role_id"`
+}
+
+// Scope представляет охват кампании
+type Scope struct {
+	Type string `json:"type"`
+	Id   int64  `json:"id"`
+}
+
+// Summary представляет итоги кампании: сколько назначений оставлено, отозвано и осталось без решения.
+// Назначения без решения при закрытии кампании сохраняются
+type Summary struct {
+	Total     int `json:"total"`
+	Kept      int `json:"kept"`
+	Revoked   int `json:"revoked"`
+	Undecided int `json:"undecided"`
+}
+
+// CampaignResponse представляет ответ API для кампании. Progress отражает текущее состояние проверки,
+// Summary - итоги, сохраненные при закрытии
+type CampaignResponse struct {
+	Id         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scope      Scope      `json:"scope"`
+	Status     string     `json:"status"`
+	LaunchedBy string     `json:"launched_by"`
+	ClosedBy   string     `json:"closed_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	Progress   Summary    `json:"progress"`
+	Summary    *Summary   `json:"summary,omitempty"`
+}
+
+// ItemResponse представляет ответ API для назначения в составе кампании
+type ItemResponse struct {
+	Id           int64      `json:"id"`
+	CampaignId   int64      `json:"campaign_id"`
+	EmployeeId   *int64     `json:"employee_id"`
+	EmployeeName string     `json:"employee_name"`
+	RoleId       *int64     `json:"role_id"`
+	RoleName     string     `json:"role_name"`
+	AssignedAt   *time.Time `json:"assigned_at,omitempty"`
+	ValidUntil   *time.Time `json:"valid_until,omitempty"`
+	Decision     *string    `json:"decision"`
+	Comment      string     `json:"comment,omitempty"`
+	ReviewedBy   string     `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+}
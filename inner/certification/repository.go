@@ -0,0 +1,146 @@
+package certification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository представляет репозиторий для работы с кампаниями пересмотра доступа
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// selectCampaigns - выборка кампаний с ходом проверки
+const selectCampaigns = `SELECT c.*, COUNT(i.id) AS total,
+		COUNT(i.id) FILTER (WHERE i.decision = 'keep') AS kept,
+		COUNT(i.id) FILTER (WHERE i.decision = 'revoke') AS revoked
+	FROM certification_campaign c
+	LEFT JOIN certification_item i ON i.campaign_id = c.id`
+
+// snapshotColumns - вставка снимка действующих назначений в кампанию $1
+const snapshotColumns = `INSERT INTO certification_item (campaign_id, employee_id, employee_name, role_id, role_name, assigned_at, valid_until)
+	SELECT $1, e.id, e.name, r.id, r.name, er.created_at, er.valid_until
+	FROM employee_role er
+	JOIN employee e ON e.id = er.employee_id AND e.deleted_at IS NULL
+	JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+	WHERE (er.valid_from IS NULL OR er.valid_from <= now()) AND (er.valid_until IS NULL OR er.valid_until > now())`
+
+// snapshotQueries - запросы снимка назначений для каждого охвата; $2 - ID роли или подразделения
+var snapshotQueries = map[string]string{
+	ScopeRole: snapshotColumns + ` AND er.role_id = $2`,
+	ScopeOrgUnit: `WITH RECURSIVE unit AS (
+			SELECT id FROM org_unit WHERE id = $2
+			UNION
+			SELECT u.id FROM org_unit u JOIN unit ON u.parent_id = unit.id
+		)
+		` + snapshotColumns + ` AND e.org_unit_id IN (SELECT id FROM unit)`,
+}
+
+// RoleExists проверяет существование неудаленной роли по ID
+func (r *Repository) RoleExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM role WHERE id = $1 AND deleted_at IS NULL)", id)
+	return exists, err
+}
+
+// OrgUnitExists проверяет существование подразделения по ID
+func (r *Repository) OrgUnitExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM org_unit WHERE id = $1)", id)
+	return exists, err
+}
+
+// Launch сохраняет кампанию и в той же транзакции делает снимок действующих назначений из ее охвата
+func (r *Repository) Launch(ctx context.Context, c *CampaignEntity) error {
+	snapshot, ok := snapshotQueries[c.ScopeType]
+	if !ok {
+		return errors.New("unknown certification scope: " + c.ScopeType)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO certification_campaign (name, scope_type, scope_id, status, launched_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, c.Name, c.ScopeType, c.ScopeId, c.Status, c.LaunchedBy, c.CreatedAt).Scan(&c.Id); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if _, err := tx.ExecContext(ctx, snapshot, c.Id, c.ScopeId); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// FindById возвращает кампанию по ID
+func (r *Repository) FindById(ctx context.Context, id int64) (res CampaignEntity, err error) {
+	err = r.db.GetContext(ctx, &res, selectCampaigns+" WHERE c.id = $1 GROUP BY c.id", id)
+	return res, err
+}
+
+// FindAll возвращает все кампании, начиная с новых
+func (r *Repository) FindAll(ctx context.Context) (res []CampaignEntity, err error) {
+	err = r.db.SelectContext(ctx, &res, selectCampaigns+" GROUP BY c.id ORDER BY c.created_at DESC, c.id DESC")
+	return res, err
+}
+
+// FindItems возвращает назначения кампании. decision фильтрует по решению: keep, revoke,
+// pending - назначения без решения; пустое значение - все назначения
+func (r *Repository) FindItems(ctx context.Context, campaignId int64, decision string) (res []ItemEntity, err error) {
+	query := `SELECT * FROM certification_item WHERE campaign_id = $1
+		AND ($2 = '' OR ($2 = 'pending' AND decision IS NULL) OR decision = $2)
+		ORDER BY employee_name, role_name, id`
+	err = r.db.SelectContext(ctx, &res, query, campaignId, decision)
+	return res, err
+}
+
+// Decide сохраняет решение по назначению, если кампания еще открыта.
+// Возвращает sql.ErrNoRows, если назначение не найдено в кампании или кампания уже закрыта
+func (r *Repository) Decide(ctx context.Context, item *ItemEntity) error {
+	query := `UPDATE certification_item i SET decision = $3, comment = $4, reviewed_by = $5, reviewed_at = $6
+		FROM certification_campaign c
+		WHERE i.id = $1 AND i.campaign_id = $2 AND c.id = i.campaign_id AND c.status = 'open'
+		RETURNING i.*`
+	return r.db.GetContext(ctx, item, query, item.Id, item.CampaignId, item.Decision, item.Comment, item.ReviewedBy, item.ReviewedAt)
+}
+
+// Close закрывает кампанию: в одной транзакции отзывает назначения с решением revoke, сохраняет итоги
+// и возвращает отозванные назначения.
+// Возвращает sql.ErrNoRows, если кампания не найдена или уже закрыта
+func (r *Repository) Close(ctx context.Context, id int64, closedBy string, at time.Time) ([]RevokedEntity, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var closedId int64
+	query := `UPDATE certification_campaign c SET status = 'closed', closed_by = $2, closed_at = $3,
+			summary = (SELECT json_build_object(
+				'total', COUNT(*),
+				'kept', COUNT(*) FILTER (WHERE decision = 'keep'),
+				'revoked', COUNT(*) FILTER (WHERE decision = 'revoke'),
+				'undecided', COUNT(*) FILTER (WHERE decision IS NULL))
+			FROM certification_item WHERE campaign_id = c.id)
+		WHERE c.id = $1 AND c.status = 'open' RETURNING c.id`
+	if err := tx.QueryRowContext(ctx, query, id, closedBy, at).Scan(&closedId); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+
+	var revoked []RevokedEntity
+	revoke := `DELETE FROM employee_role er USING certification_item i
+		WHERE i.campaign_id = $1 AND i.decision = 'revoke' AND er.employee_id = i.employee_id AND er.role_id = i.role_id
+		RETURNING er.employee_id, er.role_id`
+	if err := tx.SelectContext(ctx, &revoked, revoke, id); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	return revoked, tx.Commit()
+}
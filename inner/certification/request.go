@@ -0,0 +1,14 @@
+package certification
+
+// LaunchRequest используется для запуска кампании. Задается ровно одно из полей role_id и org_unit_id
+type LaunchRequest struct {
+	Name      string `json:"name" validate:"required,min=2,max=100"`
+	RoleId    *int64 `json:"role_id" validate:"omitempty,gt=0"`
+	OrgUnitId *int64 `json:"org_unit_id" validate:"omitempty,gt=0"`
+}
+
+// DecisionRequest используется для решения по назначению: оставить (keep) или отозвать (revoke)
+type DecisionRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=keep revoke"`
+	Comment  string `json:"comment" validate:"max=1000"`
+}
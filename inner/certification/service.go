@@ -0,0 +1,271 @@
+package certification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"idm/inner/audit"
+	"idm/inner/common"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// closeRoute - описание закрытия кампании в записях журнала аудита об отозванных назначениях
+const closeRoute = "CLOSE certification campaign"
+
+// Service структура, которая инкапсулирует бизнес-логику кампаний пересмотра доступа
+type Service struct {
+	repo      Repo
+	recorder  Recorder
+	validator Validator
+	logger    *common.Logger
+}
+
+// Repo интерфейс репозитория для кампаний пересмотра доступа
+type Repo interface {
+	RoleExists(ctx context.Context, id int64) (bool, error)
+	OrgUnitExists(ctx context.Context, id int64) (bool, error)
+	Launch(ctx context.Context, c *CampaignEntity) error
+	FindById(ctx context.Context, id int64) (CampaignEntity, error)
+	FindAll(ctx context.Context) ([]CampaignEntity, error)
+	FindItems(ctx context.Context, campaignId int64, decision string) ([]ItemEntity, error)
+	Decide(ctx context.Context, item *ItemEntity) error
+	Close(ctx context.Context, id int64, closedBy string, at time.Time) ([]RevokedEntity, error)
+}
+
+// Recorder записывает изменения в журнал аудита
+type Recorder interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// itemFilters - допустимые значения фильтра назначений по решению
+var itemFilters = []string{"", "pending", DecisionKeep, DecisionRevoke}
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, recorder Recorder, validator Validator, logger *common.Logger) *Service {
+	return &Service{
+		repo:      repo,
+		recorder:  recorder,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	if err := svc.validator.ValidateWithCustomMessages(request); err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Launch запускает кампанию по роли или подразделению и делает снимок действующих назначений из ее охвата
+func (svc *Service) Launch(ctx context.Context, actor string, request LaunchRequest) (CampaignResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return CampaignResponse{}, err
+	}
+	if (request.RoleId == nil) == (request.OrgUnitId == nil) {
+		return CampaignResponse{}, common.RequestValidationError{Message: "exactly one of role_id and org_unit_id must be set"}
+	}
+
+	entity := &CampaignEntity{
+		Name:       request.Name,
+		Status:     StatusOpen,
+		LaunchedBy: actor,
+		CreatedAt:  time.Now(),
+	}
+	if request.RoleId != nil {
+		entity.ScopeType, entity.ScopeId = ScopeRole, *request.RoleId
+	} else {
+		entity.ScopeType, entity.ScopeId = ScopeOrgUnit, *request.OrgUnitId
+	}
+	if err := svc.checkScope(ctx, entity.ScopeType, entity.ScopeId); err != nil {
+		return CampaignResponse{}, err
+	}
+
+	if err := svc.repo.Launch(ctx, entity); err != nil {
+		return CampaignResponse{}, common.RepositoryError{Message: "error launching certification campaign", Err: err}
+	}
+	return svc.FindById(ctx, entity.Id)
+}
+
+// FindById возвращает кампанию по ID
+func (svc *Service) FindById(ctx context.Context, id int64) (CampaignResponse, error) {
+	entity, err := svc.findById(ctx, id)
+	if err != nil {
+		return CampaignResponse{}, err
+	}
+	return entity.toResponse(), nil
+}
+
+// FindAll возвращает все кампании, начиная с новых
+func (svc *Service) FindAll(ctx context.Context) ([]CampaignResponse, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding certification campaigns", Err: err}
+	}
+
+	responses := make([]CampaignResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// FindItems возвращает назначения кампании; decision фильтрует их по решению (pending, keep, revoke)
+func (svc *Service) FindItems(ctx context.Context, campaignId int64, decision string) ([]ItemResponse, error) {
+	decision = strings.ToLower(strings.TrimSpace(decision))
+	if !slices.Contains(itemFilters, decision) {
+		return nil, common.RequestValidationError{Message: fmt.Sprintf("invalid decision filter: %s", decision)}
+	}
+	if _, err := svc.findById(ctx, campaignId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindItems(ctx, campaignId, decision)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding items of certification campaign %d", campaignId), Err: err}
+	}
+
+	responses := make([]ItemResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// Decide сохраняет решение проверяющего по назначению. Пока кампания открыта, решение можно изменить
+func (svc *Service) Decide(ctx context.Context, actor string, campaignId, itemId int64, request DecisionRequest) (ItemResponse, error) {
+	if itemId <= 0 {
+		return ItemResponse{}, common.RequestValidationError{Message: fmt.Sprintf("invalid certification item id: %d", itemId)}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return ItemResponse{}, err
+	}
+	campaign, err := svc.findById(ctx, campaignId)
+	if err != nil {
+		return ItemResponse{}, err
+	}
+	if campaign.Status != StatusOpen {
+		return ItemResponse{}, closedError(campaignId)
+	}
+
+	now := time.Now()
+	item := &ItemEntity{
+		Id:         itemId,
+		CampaignId: campaignId,
+		Decision:   &request.Decision,
+		Comment:    strings.TrimSpace(request.Comment),
+		ReviewedBy: actor,
+		ReviewedAt: &now,
+	}
+	err = svc.repo.Decide(ctx, item)
+	if errors.Is(err, sql.ErrNoRows) {
+		// кампания могла быть закрыта параллельно, иначе назначения в ней нет
+		if campaign, findErr := svc.findById(ctx, campaignId); findErr == nil && campaign.Status != StatusOpen {
+			return ItemResponse{}, closedError(campaignId)
+		}
+		return ItemResponse{}, common.NotFoundError{Message: fmt.Sprintf("item %d not found in certification campaign %d", itemId, campaignId)}
+	}
+	if err != nil {
+		return ItemResponse{}, common.RepositoryError{Message: fmt.Sprintf("error deciding certification item %d", itemId), Err: err}
+	}
+	return item.toResponse(), nil
+}
+
+// Close закрывает кампанию: отзывает назначения с решением revoke и сохраняет итоги.
+// Назначения без решения сохраняются и учитываются в итогах отдельно
+func (svc *Service) Close(ctx context.Context, actor string, id int64) (CampaignResponse, error) {
+	campaign, err := svc.findById(ctx, id)
+	if err != nil {
+		return CampaignResponse{}, err
+	}
+	if campaign.Status != StatusOpen {
+		return CampaignResponse{}, closedError(id)
+	}
+
+	revoked, err := svc.repo.Close(ctx, id, actor, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return CampaignResponse{}, closedError(id)
+	}
+	if err != nil {
+		return CampaignResponse{}, common.RepositoryError{Message: fmt.Sprintf("error closing certification campaign %d", id), Err: err}
+	}
+	svc.recordRevoked(ctx, actor, id, revoked)
+	return svc.FindById(ctx, id)
+}
+
+// recordRevoked записывает отзыв каждого назначения при закрытии кампании в журнал аудита.
+// Кампания к этому моменту уже закрыта, поэтому ошибки записи только журналируются
+func (svc *Service) recordRevoked(ctx context.Context, actor string, campaignId int64, revoked []RevokedEntity) {
+	for _, grant := range revoked {
+		before, err := json.Marshal(RevokedResponse{CampaignId: campaignId, EmployeeId: grant.EmployeeId, RoleId: grant.RoleId})
+		if err != nil {
+			svc.logger.ErrorCtx(ctx, "close campaign: failed to encode revoked assignment", zap.Error(err))
+			continue
+		}
+		employeeId := grant.EmployeeId
+		entry := audit.Entry{
+			Actor:      actor,
+			ActorName:  actor,
+			Action:     audit.ActionDelete,
+			Route:      closeRoute,
+			TargetType: "employees",
+			TargetId:   &employeeId,
+			Before:     before,
+		}
+		if err := svc.recorder.Record(ctx, entry); err != nil {
+			svc.logger.ErrorCtx(ctx, "close campaign: failed to record revocation", zap.Error(err),
+				zap.Int64("campaign_id", campaignId), zap.Int64("employee_id", grant.EmployeeId), zap.Int64("role_id", grant.RoleId))
+		}
+	}
+}
+
+// checkScope проверяет, что роль или подразделение, по которым запускается кампания, существуют
+func (svc *Service) checkScope(ctx context.Context, scopeType string, scopeId int64) error {
+	var found bool
+	var err error
+	switch scopeType {
+	case ScopeRole:
+		found, err = svc.repo.RoleExists(ctx, scopeId)
+	case ScopeOrgUnit:
+		found, err = svc.repo.OrgUnitExists(ctx, scopeId)
+	}
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding %s with id %d", scopeType, scopeId), Err: err}
+	}
+	if !found {
+		return common.NotFoundError{Message: fmt.Sprintf("%s with id %d not found", scopeType, scopeId)}
+	}
+	return nil
+}
+
+// findById возвращает кампанию по ID
+func (svc *Service) findById(ctx context.Context, id int64) (CampaignEntity, error) {
+	if id <= 0 {
+		return CampaignEntity{}, common.RequestValidationError{Message: fmt.Sprintf("invalid certification campaign id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CampaignEntity{}, common.NotFoundError{Message: fmt.Sprintf("certification campaign with id %d not found", id)}
+	}
+	if err != nil {
+		return CampaignEntity{}, common.RepositoryError{Message: fmt.Sprintf("error finding certification campaign with id %d", id), Err: err}
+	}
+	return entity, nil
+}
+
+// closedError сообщает, что кампания уже закрыта
+func closedError(id int64) error {
+	return common.ConflictError{Message: fmt.Sprintf("certification campaign %d is already closed", id)}
+}
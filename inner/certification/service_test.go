@@ -0,0 +1,302 @@
+package certification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"idm/inner/audit"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория кампаний пересмотра доступа
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) RoleExists(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) OrgUnitExists(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) Launch(ctx context.Context, c *CampaignEntity) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindById(ctx context.Context, id int64) (CampaignEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(CampaignEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindAll(ctx context.Context) ([]CampaignEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]CampaignEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindItems(ctx context.Context, campaignId int64, decision string) ([]ItemEntity, error) {
+	args := m.Called(ctx, campaignId, decision)
+	return args.Get(0).([]ItemEntity), args.Error(1)
+}
+
+func (m *MockRepo) Decide(ctx context.Context, item *ItemEntity) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockRepo) Close(ctx context.Context, id int64, closedBy string, at time.Time) ([]RevokedEntity, error) {
+	args := m.Called(ctx, id, closedBy, at)
+	return args.Get(0).([]RevokedEntity), args.Error(1)
+}
+
+// MockRecorder - mock-объект журнала аудита
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) Record(ctx context.Context, entry audit.Entry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+func openCampaign() CampaignEntity {
+	return CampaignEntity{Id: 1, Name: "Q3 payments", ScopeType: ScopeRole, ScopeId: 3, Status: StatusOpen, Total: 4, Kept: 1, Revoked: 1}
+}
+
+func TestCertificationService_Launch(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should launch campaign for role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("RoleExists", mock.Anything, int64(3)).Return(true, nil)
+		repo.On("Launch", mock.Anything, mock.MatchedBy(func(c *CampaignEntity) bool {
+			return c.ScopeType == ScopeRole && c.ScopeId == 3 && c.Status == StatusOpen && c.LaunchedBy == "auditor"
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*CampaignEntity).Id = 1
+		})
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil)
+
+		got, err := svc.Launch(context.Background(), "auditor", LaunchRequest{Name: "Q3 payments", RoleId: ptr(3)})
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Id)
+		a.Equal(Scope{Type: ScopeRole, Id: 3}, got.Scope)
+		a.Equal(Summary{Total: 4, Kept: 1, Revoked: 1, Undecided: 2}, got.Progress)
+		a.Nil(got.Summary)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should launch campaign for org unit", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("OrgUnitExists", mock.Anything, int64(2)).Return(true, nil)
+		repo.On("Launch", mock.Anything, mock.MatchedBy(func(c *CampaignEntity) bool {
+			return c.ScopeType == ScopeOrgUnit && c.ScopeId == 2
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*CampaignEntity).Id = 2
+		})
+		repo.On("FindById", mock.Anything, int64(2)).Return(CampaignEntity{Id: 2, ScopeType: ScopeOrgUnit, ScopeId: 2}, nil)
+
+		_, err := svc.Launch(context.Background(), "auditor", LaunchRequest{Name: "Бухгалтерия", OrgUnitId: ptr(2)})
+
+		a.Nil(err)
+		repo.AssertNotCalled(t, "RoleExists", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should require exactly one scope", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+
+		_, errNone := svc.Launch(context.Background(), "auditor", LaunchRequest{Name: "Q3"})
+		_, errBoth := svc.Launch(context.Background(), "auditor", LaunchRequest{Name: "Q3", RoleId: ptr(3), OrgUnitId: ptr(2)})
+
+		a.True(errors.As(errNone, &common.RequestValidationError{}))
+		a.True(errors.As(errBoth, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("RoleExists", mock.Anything, int64(9)).Return(false, nil)
+
+		_, err := svc.Launch(context.Background(), "auditor", LaunchRequest{Name: "Q3", RoleId: ptr(9)})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		repo.AssertNotCalled(t, "Launch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCertificationService_FindItems(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return pending items", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil)
+		repo.On("FindItems", mock.Anything, int64(1), "pending").Return([]ItemEntity{{Id: 7, CampaignId: 1, EmployeeId: ptr(5), RoleId: ptr(3)}}, nil)
+
+		got, err := svc.FindItems(context.Background(), 1, "Pending")
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Nil(got[0].Decision)
+	})
+
+	t.Run("should reject unknown decision filter", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+
+		_, err := svc.FindItems(context.Background(), 1, "maybe")
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
+
+func TestCertificationService_Decide(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should record decision with reviewer", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil)
+		repo.On("Decide", mock.Anything, mock.MatchedBy(func(item *ItemEntity) bool {
+			return item.Id == 7 && item.CampaignId == 1 && *item.Decision == DecisionRevoke && item.ReviewedBy == "auditor"
+		})).Return(nil)
+
+		got, err := svc.Decide(context.Background(), "auditor", 1, 7, DecisionRequest{Decision: DecisionRevoke, Comment: " уволился из отдела "})
+
+		a.Nil(err)
+		a.Equal(DecisionRevoke, *got.Decision)
+		a.Equal("уволился из отдела", got.Comment)
+		a.NotNil(got.ReviewedAt)
+	})
+
+	t.Run("should reject unknown decision", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+
+		_, err := svc.Decide(context.Background(), "auditor", 1, 7, DecisionRequest{Decision: "maybe"})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should return conflict for closed campaign", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		closed := openCampaign()
+		closed.Status = StatusClosed
+		repo.On("FindById", mock.Anything, int64(1)).Return(closed, nil)
+
+		_, err := svc.Decide(context.Background(), "auditor", 1, 7, DecisionRequest{Decision: DecisionKeep})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		repo.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found for item of another campaign", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil)
+		repo.On("Decide", mock.Anything, mock.Anything).Return(sql.ErrNoRows)
+
+		_, err := svc.Decide(context.Background(), "auditor", 1, 99, DecisionRequest{Decision: DecisionKeep})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestCertificationService_Close(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should close campaign and return stored summary", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		closedAt := time.Now()
+		closed := openCampaign()
+		closed.Status = StatusClosed
+		closed.ClosedBy = "auditor"
+		closed.ClosedAt = &closedAt
+		closed.Summary = []byte(`{"total": 4, "kept": 1, "revoked": 1, "undecided": 2}`)
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil).Once()
+		repo.On("Close", mock.Anything, int64(1), "auditor", mock.AnythingOfType("time.Time")).Return([]RevokedEntity{}, nil)
+		repo.On("FindById", mock.Anything, int64(1)).Return(closed, nil).Once()
+
+		got, err := svc.Close(context.Background(), "auditor", 1)
+
+		a.Nil(err)
+		a.Equal(StatusClosed, got.Status)
+		a.Equal(&Summary{Total: 4, Kept: 1, Revoked: 1, Undecided: 2}, got.Summary)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should record each revoked assignment", func(t *testing.T) {
+		repo := new(MockRepo)
+		recorder := new(MockRecorder)
+		svc := NewService(repo, recorder, validator.New(), common.NewTestLogger())
+		closed := openCampaign()
+		closed.Status = StatusClosed
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil).Once()
+		repo.On("Close", mock.Anything, int64(1), "auditor", mock.Anything).Return([]RevokedEntity{
+			{EmployeeId: 5, RoleId: 3},
+			{EmployeeId: 7, RoleId: 3},
+		}, nil)
+		repo.On("FindById", mock.Anything, int64(1)).Return(closed, nil).Once()
+		recorder.On("Record", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+		recorder.On("Record", mock.Anything, mock.Anything).Return(nil).Once()
+
+		got, err := svc.Close(context.Background(), "auditor", 1)
+
+		a.Nil(err)
+		a.Equal(StatusClosed, got.Status)
+		recorder.AssertNumberOfCalls(t, "Record", 2)
+		entry := recorder.Calls[1].Arguments.Get(1).(audit.Entry)
+		a.Equal(audit.ActionDelete, entry.Action)
+		a.Equal("auditor", entry.ActorName)
+		a.Equal("employees", entry.TargetType)
+		a.Equal(int64(7), *entry.TargetId)
+
+		var before RevokedResponse
+		a.NoError(json.Unmarshal(entry.Before, &before))
+		a.Equal(RevokedResponse{CampaignId: 1, EmployeeId: 7, RoleId: 3}, before)
+	})
+
+	t.Run("should return conflict when closed concurrently", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("FindById", mock.Anything, int64(1)).Return(openCampaign(), nil)
+		repo.On("Close", mock.Anything, int64(1), "auditor", mock.Anything).Return([]RevokedEntity(nil), sql.ErrNoRows)
+
+		_, err := svc.Close(context.Background(), "auditor", 1)
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should return not found for missing campaign", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockRecorder), validator.New(), common.NewTestLogger())
+		repo.On("FindById", mock.Anything, int64(9)).Return(CampaignEntity{}, sql.ErrNoRows)
+
+		_, err := svc.Close(context.Background(), "auditor", 9)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2/log"
@@ -13,17 +14,26 @@ import (
 
 // Config общая конфигурация всего приложения
 type Config struct {
-	DbDriverName   string `validate:"required"`
-	Dsn            string `validate:"required"`
-	AppName        string `validate:"required"`
-	AppVersion     string `validate:"required"`
-	SslSert        string `validate:"required"`
-	SslKey         string `validate:"required"`
-	KeycloakJwkUrl string `validate:"required"`
-	LogLevel       string
-	LogDevelopMode bool
+	DbDriverName     string `validate:"required"`
+	Dsn              string `validate:"required"`
+	AppName          string `validate:"required"`
+	AppVersion       string `validate:"required"`
+	SslSert          string `validate:"required"`
+	SslKey           string `validate:"required"`
+	KeycloakJwkUrl   string `validate:"required"`
+	KeycloakIssuer   string
+	KeycloakAudience string
+	AuthClockSkew    time.Duration
+	PolicyFile       string
+	LogLevel         string
+	LogDevelopMode   bool
+	// AssignmentSweepInterval - период фоновой очистки просроченных назначений ролей
+	AssignmentSweepInterval time.Duration
 }
 
+// defaultAssignmentSweepInterval - период очистки просроченных назначений ролей, если он не задан
+const defaultAssignmentSweepInterval = time.Minute
+
 // GetConfig получение конфигурации из .env файла или переменных окружения
 func GetConfig(envFile string) Config {
 	var err = godotenv.Load(envFile)
@@ -32,15 +42,33 @@ func GetConfig(envFile string) Config {
 		log.Info("Error loading .env file: %v\n", zap.Error(err))
 	}
 	var cfg = Config{
-		DbDriverName:   os.Getenv("DB_DRIVER_NAME"),
-		Dsn:            os.Getenv("DB_DSN"),
-		AppName:        os.Getenv("APP_NAME"),
-		AppVersion:     os.Getenv("APP_VERSION"),
-		SslSert:        os.Getenv("SSL_SERT"),
-		SslKey:         os.Getenv("SSL_KEY"),
-		KeycloakJwkUrl: os.Getenv("KEYCLOAK_JWK_URL"),
-		LogLevel:       os.Getenv("LOG_LEVEL"),
-		LogDevelopMode: os.Getenv("LOG_DEVELOP_MODE") == "true",
+		DbDriverName:     os.Getenv("DB_DRIVER_NAME"),
+		Dsn:              os.Getenv("DB_DSN"),
+		AppName:          os.Getenv("APP_NAME"),
+		AppVersion:       os.Getenv("APP_VERSION"),
+		SslSert:          os.Getenv("SSL_SERT"),
+		SslKey:           os.Getenv("SSL_KEY"),
+		KeycloakJwkUrl:   os.Getenv("KEYCLOAK_JWK_URL"),
+		KeycloakIssuer:   os.Getenv("KEYCLOAK_ISSUER"),
+		KeycloakAudience: os.Getenv("KEYCLOAK_AUDIENCE"),
+		PolicyFile:       os.Getenv("POLICY_FILE"),
+		LogLevel:         os.Getenv("LOG_LEVEL"),
+		LogDevelopMode:   os.Getenv("LOG_DEVELOP_MODE") == "true",
+
+		AssignmentSweepInterval: defaultAssignmentSweepInterval,
+	}
+	if skew := os.Getenv("AUTH_CLOCK_SKEW"); skew != "" {
+		cfg.AuthClockSkew, err = time.ParseDuration(skew)
+		if err != nil || cfg.AuthClockSkew < 0 {
+			// некорректное расхождение часов - такая же ошибка конфигурации, как и отсутствие обязательных полей
+			panic(fmt.Sprintf("config validation error: invalid AUTH_CLOCK_SKEW %q", skew))
+		}
+	}
+	if interval := os.Getenv("ASSIGNMENT_SWEEP_INTERVAL"); interval != "" {
+		cfg.AssignmentSweepInterval, err = time.ParseDuration(interval)
+		if err != nil || cfg.AssignmentSweepInterval <= 0 {
+			panic(fmt.Sprintf("config validation error: invalid ASSIGNMENT_SWEEP_INTERVAL %q", interval))
+		}
 	}
 	err = validator.New().Struct(cfg)
 	if err != nil {
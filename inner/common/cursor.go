@@ -0,0 +1,38 @@
+package common
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix отличает курсоры сервиса от произвольных строк и позволяет менять формат курсора в будущем
+const cursorPrefix = "id:"
+
+// EncodeCursor упаковывает ID последней записи страницы в непрозрачный курсор для keyset-пагинации
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// DecodeCursor возвращает ID, после которого нужно продолжить обход.
+// Пустой курсор означает начало списка; поврежденный курсор - RequestValidationError
+func DecodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	invalid := RequestValidationError{Message: "invalid cursor"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalid
+	}
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, invalid
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, invalid
+	}
+	return id, nil
+}
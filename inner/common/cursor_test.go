@@ -0,0 +1,37 @@
+package common
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should decode encoded cursor", func(t *testing.T) {
+		id, err := DecodeCursor(EncodeCursor(42))
+		a.NoError(err)
+		a.Equal(int64(42), id)
+	})
+
+	t.Run("should start from beginning for empty cursor", func(t *testing.T) {
+		id, err := DecodeCursor("")
+		a.NoError(err)
+		a.Equal(int64(0), id)
+	})
+
+	t.Run("should reject malformed cursors", func(t *testing.T) {
+		for _, cursor := range []string{
+			"not base64!",
+			base64.RawURLEncoding.EncodeToString([]byte("42")),
+			base64.RawURLEncoding.EncodeToString([]byte("id:abc")),
+			base64.RawURLEncoding.EncodeToString([]byte("id:-1")),
+		} {
+			_, err := DecodeCursor(cursor)
+			a.True(errors.As(err, &RequestValidationError{}), cursor)
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package common
 
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
 type RequestValidationError struct {
 	Message string
 }
@@ -55,3 +61,48 @@ type NotFoundError struct {
 func (err NotFoundError) Error() string {
 	return err.Message
 }
+
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (err PreconditionFailedError) Error() string {
+	return err.Message
+}
+
+// ConflictError - операция недопустима в текущем состоянии объекта
+type ConflictError struct {
+	Message string
+}
+
+func (err ConflictError) Error() string {
+	return err.Message
+}
+
+// ForbiddenError - у пользователя нет права на операцию с конкретным объектом
+type ForbiddenError struct {
+	Message string
+}
+
+func (err ForbiddenError) Error() string {
+	return err.Message
+}
+
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса или ограничения
+const uniqueViolation = "23505"
+
+// IsUniqueViolation сообщает, что операция отклонена базой данных из-за нарушения уникальности
+func IsUniqueViolation(err error) bool {
+	_, ok := UniqueViolationConstraint(err)
+	return ok
+}
+
+// UniqueViolationConstraint возвращает имя нарушенного уникального индекса или ограничения,
+// если операция отклонена базой данных из-за нарушения уникальности
+func UniqueViolationConstraint(err error) (string, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return pqErr.Constraint, true
+	}
+	return "", false
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should detect wrapped unique violation", func(t *testing.T) {
+		err := fmt.Errorf("error adding role: %w", &pq.Error{Code: "23505"})
+		a.True(IsUniqueViolation(err))
+	})
+
+	t.Run("should ignore other postgres errors", func(t *testing.T) {
+		a.False(IsUniqueViolation(&pq.Error{Code: "23503"}))
+	})
+
+	t.Run("should ignore non-postgres errors", func(t *testing.T) {
+		a.False(IsUniqueViolation(errors.New("database error")))
+		a.False(IsUniqueViolation(nil))
+	})
+}
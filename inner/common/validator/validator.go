@@ -61,6 +61,14 @@ func (v Validator) customValidationError(errs validator.ValidationErrors) error
 			messages = append(messages, fmt.Sprintf("%s must be at most %s characters long", strings.ToLower(err.Field()), err.Param()))
 		case "gt":
 			messages = append(messages, fmt.Sprintf("%s must be greater than %s", strings.ToLower(err.Field()), err.Param()))
+		case "email":
+			messages = append(messages, fmt.Sprintf("%s must be a valid email address", strings.ToLower(err.Field())))
+		case "e164":
+			messages = append(messages, fmt.Sprintf("%s must be a phone number in E.164 format", strings.ToLower(err.Field())))
+		case "oneof":
+			messages = append(messages, fmt.Sprintf("%s must be one of: %s", strings.ToLower(err.Field()), err.Param()))
+		case "excludesall":
+			messages = append(messages, fmt.Sprintf("%s must not contain spaces", strings.ToLower(err.Field())))
 		default:
 			messages = append(messages, fmt.Sprintf("%s validation failed on %s", strings.ToLower(err.Field()), err.Tag()))
 		}
@@ -95,6 +95,49 @@ func TestValidator_AddEmployeeRequest(t *testing.T) {
 		err := v.Validate(req)
 		assert.NoError(t, err)
 	})
+
+	t.Run("valid profile", func(t *testing.T) {
+		req := employee.AddEmployeeRequest{
+			Name: "John Doe",
+			Profile: employee.Profile{
+				Email: "john.doe@example.com",
+				Login: "j.doe",
+				Phone: "+79991234567",
+			},
+			Status: employee.StatusPreHire,
+		}
+
+		err := v.Validate(req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		req := employee.AddEmployeeRequest{
+			Name: "John Doe",
+			Profile: employee.Profile{
+				Email: "not-an-email",
+				Login: "john doe",
+				Phone: "8 (999) 123-45-67",
+			},
+			Status: "retired",
+		}
+
+		err := v.Validate(req)
+		assert.Error(t, err)
+
+		validationErrs, ok := err.(validator.ValidationErrors)
+		assert.True(t, ok)
+		tags := make(map[string]string)
+		for _, fieldErr := range validationErrs {
+			tags[fieldErr.Field()] = fieldErr.Tag()
+		}
+		assert.Equal(t, map[string]string{
+			"Email":  "email",
+			"Login":  "excludesall",
+			"Phone":  "e164",
+			"Status": "oneof",
+		}, tags)
+	})
 }
 
 func TestValidator_FindByIdRequest(t *testing.T) {
@@ -783,6 +826,16 @@ func TestCustomValidationMessages(t *testing.T) {
 			request:         employee.AddEmployeeRequest{Name: strings.Repeat("A", 101)},
 			expectedMessage: "name must be at most 100 characters long",
 		},
+		{
+			name:            "invalid_email_message",
+			request:         employee.AddEmployeeRequest{Name: "John Doe", Profile: employee.Profile{Email: "john"}},
+			expectedMessage: "email must be a valid email address",
+		},
+		{
+			name:            "invalid_status_message",
+			request:         employee.AddEmployeeRequest{Name: "John Doe", Status: "retired"},
+			expectedMessage: "status must be one of: pre_hire active",
+		},
 		{
 			name:            "invalid_id_message",
 			request:         employee.FindByIdRequest{Id: 0},
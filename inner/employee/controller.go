@@ -1,14 +1,14 @@
 package employee
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"fmt"
-	"github.com/golang-jwt/jwt/v5"
 	"idm/inner/common"
 	"idm/inner/web"
-	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
@@ -23,16 +23,28 @@ type Controller struct {
 
 // Svc интерфейс сервиса для работы с сотрудниками
 type Svc interface {
-	FindById(ctx context.Context, id int64) (Response, error)                           // поиск сотрудника по ID
-	AddTransactional(ctx context.Context, request AddEmployeeRequest) (Response, error) // добавление сотрудника в транзакции
-	Add(ctx context.Context, name string) (Response, error)                             // простое добавление сотрудника
-	FindAll(ctx context.Context) ([]Response, error)                                    // получение всех сотрудников
-	FindByIds(ctx context.Context, ids []int64) ([]Response, error)                     // поиск сотрудников по списку ID
-	DeleteById(ctx context.Context, id int64) error                                     // удаление сотрудника по ID
-	DeleteByIds(ctx context.Context, ids []int64) error                                 // удаление сотрудников по списку ID
+	FindById(ctx context.Context, id int64) (Response, error)                                             // поиск сотрудника по ID
+	FindByIdWithDeleted(ctx context.Context, id int64) (Response, error)                                  // поиск сотрудника по ID, включая удаленных
+	AddTransactional(ctx context.Context, request AddEmployeeRequest) (Response, error)                   // добавление сотрудника в транзакции
+	Add(ctx context.Context, request AddEmployeeRequest) (Response, error)                                // простое добавление сотрудника
+	FindAll(ctx context.Context) ([]Response, error)                                                      // получение всех сотрудников
+	FindByIds(ctx context.Context, ids []int64) ([]Response, error)                                       // поиск сотрудников по списку ID
+	Update(ctx context.Context, id int64, request UpdateEmployeeRequest, version int64) (Response, error) // обновление сотрудника
+	DeleteById(ctx context.Context, id int64) error                                                       // удаление сотрудника по ID
+	DeleteByIdIfMatch(ctx context.Context, id, version int64) error                                       // удаление сотрудника с проверкой версии
+	DeleteByIds(ctx context.Context, ids []int64) error                                                   // удаление сотрудников по списку ID
+	FindAllWithDeleted(ctx context.Context) ([]Response, error)                                           // получение всех сотрудников, включая удаленных
+	Restore(ctx context.Context, id int64) (Response, error)                                              // восстановление удаленного сотрудника
+	PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error)                 // окончательное удаление давно удаленных сотрудников
+	Activate(ctx context.Context, id int64) (Response, error)                                             // выход сотрудника на работу
+	Suspend(ctx context.Context, id int64) (Response, error)                                              // приостановка работы сотрудника
+	Terminate(ctx context.Context, id int64, request TerminateRequest) (Response, error)                  // увольнение сотрудника с отзывом ролей
 	ValidateRequest(request interface{}) error
 
 	FindPage(ctx context.Context, req PageRequest) (PageResponse, error)
+	FindAfter(ctx context.Context, req CursorRequest) (CursorResponse, error)
+	Search(ctx context.Context, req SearchRequest) ([]SearchResult, error)
+	Import(ctx context.Context, rows []ImportRow, dryRun bool) (ImportReport, error)
 }
 
 // NewController создает новый экземпляр контроллера сотрудников
@@ -44,15 +56,27 @@ func NewController(server *web.Server, employeeService Svc, logger *common.Logge
 func (c *Controller) RegisterRoutes() {
 	api := c.server.GroupApiV1 // группа маршрутов API v1
 
-	// CRUD операции для сотрудников
-	api.Post("/employees", c.CreateEmployee)                            // создание сотрудника
-	api.Post("/employees/transactional", c.CreateEmployeeTransactional) // создание сотрудника в транзакции
-	api.Get("/employees/page", c.GetEmployeesPage)
-	api.Get("/employees/:id", c.GetEmployee)           // получение сотрудника по ID
-	api.Get("/employees", c.GetAllEmployees)           // получение всех сотрудников
-	api.Post("/employees/by-ids", c.GetEmployeesByIds) // получение сотрудников по списку ID
-	api.Delete("/employees/:id", c.DeleteEmployee)     // удаление сотрудника по ID
-	api.Delete("/employees", c.DeleteEmployeesByIds)   // удаление сотрудников по списку ID
+	// CRUD операции для сотрудников (права доступа проверяются политикой сервера)
+	api.Post("/employees", c.server.Require(web.EmployeeWrite), c.CreateEmployee)                            // создание сотрудника
+	api.Post("/employees/transactional", c.server.Require(web.EmployeeWrite), c.CreateEmployeeTransactional) // создание сотрудника в транзакции
+	api.Post("/employees/import", c.server.Require(web.EmployeeWrite), c.ImportEmployees)                    // массовый импорт сотрудников
+	api.Get("/employees/page", c.server.Require(web.EmployeeRead), c.GetEmployeesPage)
+	api.Get("/employees/search", c.server.Require(web.EmployeeRead), c.SearchEmployees)
+	api.Get("/employees/:id", c.server.Require(web.EmployeeRead), c.GetEmployee)           // получение сотрудника по ID
+	api.Get("/employees", c.server.Require(web.EmployeeRead), c.GetAllEmployees)           // получение всех сотрудников
+	api.Post("/employees/by-ids", c.server.Require(web.EmployeeRead), c.GetEmployeesByIds) // получение сотрудников по списку ID
+	api.Put("/employees/:id", c.server.Require(web.EmployeeWrite), c.UpdateEmployee)       // обновление сотрудника по ID
+	api.Delete("/employees/:id", c.server.Require(web.EmployeeDelete), c.DeleteEmployee)   // удаление сотрудника по ID
+	api.Delete("/employees", c.server.Require(web.EmployeeDelete), c.DeleteEmployeesByIds) // удаление сотрудников по списку ID
+
+	// Восстановление и окончательное удаление (мягко) удаленных сотрудников
+	api.Post("/employees/:id/restore", c.server.Require(web.EmployeeRestore), c.RestoreEmployee)
+	api.Post("/employees/purge", c.server.Require(web.EmployeeDelete), c.PurgeDeletedEmployees)
+
+	// Жизненный цикл сотрудника: прием, приостановка, увольнение
+	api.Post("/employees/:id/activate", c.server.Require(web.EmployeeWrite), c.ActivateEmployee)
+	api.Post("/employees/:id/suspend", c.server.Require(web.EmployeeWrite), c.SuspendEmployee)
+	api.Post("/employees/:id/terminate", c.server.Require(web.EmployeeWrite), c.TerminateEmployee)
 }
 
 // CreateEmployeeTransactional создает нового сотрудника в рамках транзакции
@@ -67,16 +91,9 @@ func (c *Controller) RegisterRoutes() {
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 409 {object} common.ResponseExample "Conflict"
 // @Router /employees/transactional [post]
 func (c *Controller) CreateEmployeeTransactional(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
-	}
-
 	var req AddEmployeeRequest
 
 	// Парсинг JSON
@@ -104,6 +121,67 @@ func (c *Controller) CreateEmployeeTransactional(ctx *fiber.Ctx) error {
 	return nil
 }
 
+// ImportEmployees создает и обновляет сотрудников из CSV- или NDJSON-файла в одной транзакции
+// @Summary Массовый импорт сотрудников
+// @Description Импортировать сотрудников из CSV (text/csv, первая строка - заголовок с названиями полей) или
+// @Description NDJSON (application/x-ndjson, по одному объекту в строке). Сотрудник ищется по имени без учета регистра:
+// @Description найденный обновляется, иначе создается. У найденного сотрудника меняются только поля, заданные в строке:
+// @Description столбцы CSV с непустым значением или ключи объекта NDJSON. Если хотя бы одна строка содержит ошибку или конфликт,
+// @Description ничего не сохраняется и возвращается 422 с отчетом. С dryRun=true изменения не сохраняются никогда,
+// @Description а отчет по строкам показывает, что было бы сделано
+// @Tags employee
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Security BearerAuth
+// @Param dryRun query bool false "Только проверить файл и вернуть отчет, не сохраняя изменения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Failure 415 {object} common.ResponseExample "Unsupported Media Type"
+// @Failure 422 {object} common.ResponseExample "Отчет импорта со строками, содержащими ошибки или конфликты"
+// @Router /employees/import [post]
+func (c *Controller) ImportEmployees(ctx *fiber.Ctx) error {
+	body := bytes.NewReader(ctx.Body())
+	var rows []ImportRow
+	var err error
+	switch mediaType, _, _ := strings.Cut(ctx.Get(fiber.HeaderContentType), ";"); strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "text/csv":
+		rows, err = parseCSV(body)
+	case "application/x-ndjson", "application/ndjson":
+		rows, err = parseNDJSON(body)
+	default:
+		return common.ErrResponse(ctx, fiber.StatusUnsupportedMediaType, "expected text/csv or application/x-ndjson body")
+	}
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "import employees: invalid file", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	dryRun := ctx.QueryBool("dryRun", false)
+	if dryRun {
+		// пробный запуск всегда откатывает транзакцию
+		web.SkipAudit(ctx)
+	}
+	report, err := c.employeeService.Import(ctx.Context(), rows, dryRun)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "import employees: failed to import employees", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// отчет нужен клиенту и при отказе, чтобы исправить строки с ошибками и конфликтами
+	if !dryRun && !report.Applied {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&common.Response[ImportReport]{
+			Success: false,
+			Message: "import rejected: some rows have errors or conflicts",
+			Data:    report,
+		})
+	}
+	return common.OkResponse(ctx, report)
+}
+
 // CreateEmployee создает нового сотрудника (без транзакции)
 // @Summary Создать нового сотрудника
 // @Description Create a new employee
@@ -116,16 +194,9 @@ func (c *Controller) CreateEmployeeTransactional(ctx *fiber.Ctx) error {
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 409 {object} common.ResponseExample "Conflict"
 // @Router /employees [post]
 func (c *Controller) CreateEmployee(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
-	}
-
 	var req AddEmployeeRequest
 
 	// Парсинг JSON
@@ -138,15 +209,10 @@ func (c *Controller) CreateEmployee(ctx *fiber.Ctx) error {
 	c.logger.DebugCtx(ctx.Context(), "create employee: received request", zap.Any("request", req))
 
 	// Вызов сервиса
-	resp, err := c.employeeService.Add(ctx.Context(), req.Name)
+	resp, err := c.employeeService.Add(ctx.Context(), req)
 	if err != nil {
 		c.logger.ErrorCtx(ctx.Context(), "create employee: failed to add employee", zap.Error(err))
-
-		if errors.As(err, &common.RequestValidationError{}) {
-			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
-		} else {
-			return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
-		}
+		return handleError(ctx, err)
 	}
 
 	// Ответ
@@ -166,63 +232,130 @@ func (c *Controller) CreateEmployee(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID сотрудника"
+// @Param includeDeleted query bool false "Искать и среди удаленных сотрудников (требует права employee:restore)"
+// @Param If-None-Match header string false "ETag ранее полученной версии"
 // @Success 200 {object} common.ResponseExample
+// @Success 304 {string} string "Not Modified"
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Failure 404 {object} common.ResponseExample
 // @Router /employees/{id} [get]
 func (c *Controller) GetEmployee(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
+	// Извлечение и парсинг ID из параметров маршрута
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
 	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
 	}
-	if !(slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) || slices.Contains(claims.RealmAccess.Roles, web.IdmUser)) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+
+	// Удаленные сотрудники видны только тем, кому политика разрешает их восстанавливать
+	findById := c.employeeService.FindById
+	if ctx.QueryBool("includeDeleted") {
+		if !c.server.Allowed(ctx, web.EmployeeRestore) {
+			return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+		}
+		findById = c.employeeService.FindByIdWithDeleted
+	}
+
+	// Поиск сотрудника по ID через сервис
+	resp, err := findById(ctx.Context(), id)
+	if err != nil {
+		return handleError(ctx, err)
+	}
+
+	// Версия записи не изменилась - возвращаем 304 Not Modified
+	etag := web.ETag(resp.Version)
+	if web.NotModified(ctx, etag) {
+		return nil
 	}
+	ctx.Set(fiber.HeaderETag, etag)
 
+	// Возврат данных найденного сотрудника
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee")
+	}
+	return nil
+}
+
+// UpdateEmployee обновляет сотрудника по его ID
+// @Summary Обновить сотрудника
+// @Description Обновить имя сотрудника по идентификатору
+// @Tags employee
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param If-Match header string false "ETag версии, которую изменяет клиент"
+// @Param request body employee.UpdateEmployeeRequest true "update employee request"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Failure 412 {object} common.ResponseExample "Precondition Failed"
+// @Router /employees/{id} [put]
+func (c *Controller) UpdateEmployee(ctx *fiber.Ctx) error {
 	// Извлечение и парсинг ID из параметров маршрута
 	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
 	if err != nil {
 		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
 	}
 
-	// Поиск сотрудника по ID через сервис
-	resp, err := c.employeeService.FindById(ctx.Context(), id)
+	// Ожидаемая версия записи из заголовка If-Match
+	version, err := web.IfMatchVersion(ctx)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	var req UpdateEmployeeRequest
+
+	// Парсинг JSON
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update employee: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// Вызов сервиса
+	resp, err := c.employeeService.Update(ctx.Context(), id, req, version)
 	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update employee: failed to update employee", zap.Error(err))
 		return handleError(ctx, err)
 	}
+	ctx.Set(fiber.HeaderETag, web.ETag(resp.Version))
 
-	// Возврат данных найденного сотрудника
+	// Ответ
 	if err := common.OkResponse(ctx, resp); err != nil {
-		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning updated employee")
 	}
 	return nil
 }
 
 // GetAllEmployees получает список всех сотрудников
 // @Summary Получить всех сотрудников
-// @Description Получить список всех сотрудников
+// @Description Получить список всех сотрудников; удаленные сотрудники возвращаются только с includeDeleted=true
 // @Tags employee
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param includeDeleted query bool false "Включить удаленных сотрудников (требует права employee:restore)"
 // @Success 200 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Failure 500 {object} common.ResponseExample
 // @Router /employees [get]
 func (c *Controller) GetAllEmployees(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !(slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) || slices.Contains(claims.RealmAccess.Roles, web.IdmUser)) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+	// Удаленные сотрудники видны только тем, кому политика разрешает их восстанавливать
+	findAll := c.employeeService.FindAll
+	if ctx.QueryBool("includeDeleted") {
+		if !c.server.Allowed(ctx, web.EmployeeRestore) {
+			return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+		}
+		findAll = c.employeeService.FindAllWithDeleted
 	}
 
 	// Получение всех сотрудников через сервис
-	resp, err := c.employeeService.FindAll(ctx.Context())
+	resp, err := findAll(ctx.Context())
 	if err != nil {
 		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
 	}
@@ -248,13 +381,8 @@ func (c *Controller) GetAllEmployees(ctx *fiber.Ctx) error {
 // @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Router /employees/by-ids [post]
 func (c *Controller) GetEmployeesByIds(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !(slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) || slices.Contains(claims.RealmAccess.Roles, web.IdmUser)) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
-	}
+	// POST используется только для передачи списка ID, данные не изменяются
+	web.SkipAudit(ctx)
 
 	// Парсинг JSON тела запроса в структуру FindByIdsRequest
 	var req FindByIdsRequest
@@ -285,55 +413,191 @@ func (c *Controller) GetEmployeesByIds(ctx *fiber.Ctx) error {
 
 // GetEmployeesPage получает страницу сотрудников
 // @Summary Получить страницу сотрудников
-// @Description Получить страницу сотрудников с фильтрацией
+// @Description Получить страницу сотрудников с фильтрацией и сортировкой. При равных значениях поля сортировки
+// @Description сотрудники упорядочиваются по id, поэтому страницы не пересекаются.
+// @Description С параметрами after или limit включается режим курсора: сотрудники возвращаются по возрастанию id
+// @Description порциями до limit записей вместе с next_cursor для следующего запроса (null в конце списка)
 // @Tags employee
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param pageNumber query int false "Номер страницы"
 // @Param pageSize query int false "Размер страницы"
-// @Param textFilter query string false "Фильтр по имени"
+// @Param textFilter query string false "Фильтр по имени, email, логину, табельному номеру, ФИО и должности"
+// @Param sort query string false "Поле и направление сортировки, например name или created_at:desc (id, name, email, login, employee_number, last_name, job_title, status, hire_date, created_at, updated_at)"
+// @Param createdFrom query string false "Создан не раньше (RFC3339)"
+// @Param createdTo query string false "Создан раньше (RFC3339)"
+// @Param updatedFrom query string false "Изменен не раньше (RFC3339)"
+// @Param updatedTo query string false "Изменен раньше (RFC3339)"
+// @Param ids query string false "Список ID через запятую"
+// @Param includeDeleted query bool false "Включить удаленных сотрудников (требует права employee:restore)"
+// @Param after query string false "Курсор из next_cursor предыдущего ответа (режим курсора)"
+// @Param limit query int false "Размер порции в режиме курсора (1-1000, по умолчанию 100)"
 // @Success 200 {object} common.ResponseExample
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Router /employees/page [get]
 func (c *Controller) GetEmployeesPage(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
+	// обход по курсору для выгрузки всего справочника: глубокие OFFSET слишком медленные
+	if ctx.Query("after") != "" || ctx.Query("limit") != "" {
+		return c.getEmployeesAfter(ctx)
+	}
+
+	req, err := parsePageRequest(ctx)
 	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
+		c.logger.ErrorCtx(ctx.Context(), "get employees page: invalid query", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
 	}
-	if !(slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) || slices.Contains(claims.RealmAccess.Roles, web.IdmUser)) {
+	if req.IncludeDeleted && !c.server.Allowed(ctx, web.EmployeeRestore) {
 		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
 	}
 
-	pageNumber, err := strconv.Atoi(ctx.Query("pageNumber", "0"))
+	resp, err := c.employeeService.FindPage(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get employees page: failed to find employees", zap.Error(err))
+		return handleError(ctx, err)
+	}
+	return common.OkResponse(ctx, resp)
+}
+
+// getEmployeesAfter возвращает порцию сотрудников после курсора
+func (c *Controller) getEmployeesAfter(ctx *fiber.Ctx) error {
+	page, err := parsePageRequest(ctx)
 	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid pageNumber")
+		c.logger.ErrorCtx(ctx.Context(), "get employees after cursor: invalid query", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	if page.Sort != "" {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "sort is not supported with cursor pagination")
+	}
+	if page.IncludeDeleted && !c.server.Allowed(ctx, web.EmployeeRestore) {
+		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
 	}
-	pageSize, err := strconv.Atoi(ctx.Query("pageSize", "20"))
+	limit, err := strconv.Atoi(ctx.Query("limit", "100"))
 	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid pageSize")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid limit")
 	}
 
-	textFilter := ctx.Query("textFilter", "")
-	// Формируем запрос с фильтром
-	req := PageRequest{PageNumber: pageNumber, PageSize: pageSize, TextFilter: textFilter}
-	resp, err := c.employeeService.FindPage(context.Background(), req)
+	req := CursorRequest{
+		Limit:          limit,
+		After:          ctx.Query("after"),
+		TextFilter:     page.TextFilter,
+		CreatedFrom:    page.CreatedFrom,
+		CreatedTo:      page.CreatedTo,
+		UpdatedFrom:    page.UpdatedFrom,
+		UpdatedTo:      page.UpdatedTo,
+		Ids:            page.Ids,
+		IncludeDeleted: page.IncludeDeleted,
+	}
+	resp, err := c.employeeService.FindAfter(ctx.Context(), req)
 	if err != nil {
-		fmt.Println("ERROR in GetEmployeesPage:", err)
+		c.logger.ErrorCtx(ctx.Context(), "get employees after cursor: failed to find employees", zap.Error(err))
 		return handleError(ctx, err)
 	}
 	return common.OkResponse(ctx, resp)
 }
 
+// SearchEmployees ищет сотрудников по имени и анкетным данным с учетом опечаток
+// @Summary Поиск сотрудников
+// @Description Полнотекстовый поиск по началу слов в имени, ФИО, логине, email, табельном номере и должности
+// @Description с нечетким сравнением имени, поэтому находятся неполные и написанные с опечатками имена.
+// @Description Результаты упорядочены по убыванию rank; в highlight совпавшие слова обрамлены тегами <mark>
+// @Tags employee
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Текст запроса, например ivan pet"
+// @Param limit query int false "Максимальное количество результатов (1-100, по умолчанию 20)"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /employees/search [get]
+func (c *Controller) SearchEmployees(ctx *fiber.Ctx) error {
+	limit, err := strconv.Atoi(ctx.Query("limit", "20"))
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid limit")
+	}
+
+	resp, err := c.employeeService.Search(ctx.Context(), SearchRequest{Query: ctx.Query("q"), Limit: limit})
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "search employees: failed to find employees", zap.Error(err))
+		return handleError(ctx, err)
+	}
+	return common.OkResponse(ctx, resp)
+}
+
+// parsePageRequest формирует запрос страницы сотрудников из параметров строки запроса
+func parsePageRequest(ctx *fiber.Ctx) (PageRequest, error) {
+	req := PageRequest{
+		TextFilter:     ctx.Query("textFilter", ""),
+		Sort:           ctx.Query("sort", ""),
+		IncludeDeleted: ctx.QueryBool("includeDeleted"),
+	}
+
+	var err error
+	if req.PageNumber, err = strconv.Atoi(ctx.Query("pageNumber", "0")); err != nil {
+		return PageRequest{}, errors.New("invalid pageNumber")
+	}
+	if req.PageSize, err = strconv.Atoi(ctx.Query("pageSize", "20")); err != nil {
+		return PageRequest{}, errors.New("invalid pageSize")
+	}
+	if req.CreatedFrom, err = parseTime(ctx.Query("createdFrom")); err != nil {
+		return PageRequest{}, errors.New("invalid createdFrom: expected RFC3339 time")
+	}
+	if req.CreatedTo, err = parseTime(ctx.Query("createdTo")); err != nil {
+		return PageRequest{}, errors.New("invalid createdTo: expected RFC3339 time")
+	}
+	if req.UpdatedFrom, err = parseTime(ctx.Query("updatedFrom")); err != nil {
+		return PageRequest{}, errors.New("invalid updatedFrom: expected RFC3339 time")
+	}
+	if req.UpdatedTo, err = parseTime(ctx.Query("updatedTo")); err != nil {
+		return PageRequest{}, errors.New("invalid updatedTo: expected RFC3339 time")
+	}
+	if req.Ids, err = parseIds(ctx.Query("ids")); err != nil {
+		return PageRequest{}, errors.New("invalid ids: expected comma-separated list of numbers")
+	}
+	return req, nil
+}
+
+// parseTime разбирает время в формате RFC3339; пустое значение означает отсутствие фильтра
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// parseIds разбирает список ID через запятую; пустое значение означает отсутствие фильтра
+func parseIds(value string) ([]int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	ids := make([]int64, len(parts))
+	for i, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
 // handleError централизованная обработка ошибок с соответствующими HTTP статусами
 func handleError(ctx *fiber.Ctx, err error) error {
 	switch {
-	// Ошибки валидации и дублирования - 400 Bad Request
-	case errors.As(err, &common.RequestValidationError{}),
-		errors.As(err, &common.AlreadyExistsError{}):
+	// Ошибки валидации - 400 Bad Request
+	case errors.As(err, &common.RequestValidationError{}):
 		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	// Имя уже занято - 409 Conflict
+	case errors.As(err, &common.AlreadyExistsError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
 	// Ошибки транзакций и репозитория - 500 Internal Server Error
 	case errors.As(err, &common.TransactionError{}),
 		errors.As(err, &common.RepositoryError{}):
@@ -341,6 +605,12 @@ func handleError(ctx *fiber.Ctx, err error) error {
 	// Ошибки отсутствия данных - 404 Not Found
 	case errors.As(err, &common.NotFoundError{}):
 		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	// Переход недопустим в текущем статусе сотрудника - 409 Conflict
+	case errors.As(err, &common.ConflictError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	// Версия записи не совпала с If-Match - 412 Precondition Failed
+	case errors.As(err, &common.PreconditionFailedError{}):
+		return common.ErrResponse(ctx, fiber.StatusPreconditionFailed, err.Error())
 	// Все остальные ошибки - 500 Internal Server Error
 	default:
 		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
@@ -355,27 +625,35 @@ func handleError(ctx *fiber.Ctx, err error) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "ID сотрудника"
+// @Param If-Match header string false "ETag версии, которую удаляет клиент"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Failure 404 {object} common.ResponseExample
+// @Failure 412 {object} common.ResponseExample "Precondition Failed"
 // @Router /employees/{id} [delete]
 func (c *Controller) DeleteEmployee(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
-	}
-
 	// Извлечение и парсинг ID из параметров маршрута
 	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
 	if err != nil {
 		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
 	}
 
+	// Ожидаемая версия записи из заголовка If-Match
+	version, err := web.IfMatchVersion(ctx)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	if version > 0 {
+		if err := c.employeeService.DeleteByIdIfMatch(ctx.Context(), id, version); err != nil {
+			c.logger.ErrorCtx(ctx.Context(), "delete employee: failed to delete employee", zap.Error(err))
+			return handleError(ctx, err)
+		}
+		ctx.Status(fiber.StatusNoContent)
+		return nil
+	}
+
 	// Удаление сотрудника по ID через сервис
 	if err = c.employeeService.DeleteById(ctx.Context(), id); err != nil {
 		// Специальная обработка для случая "сотрудник не найден"
@@ -403,16 +681,9 @@ func (c *Controller) DeleteEmployee(ctx *fiber.Ctx) error {
 // @Failure 400 {object} common.ResponseExample
 // @Failure 401 {object} common.ResponseExample "Unauthorized"
 // @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample "Часть сотрудников не найдена, ничего не удалено"
 // @Router /employees [delete]
 func (c *Controller) DeleteEmployeesByIds(ctx *fiber.Ctx) error {
-	claims, err := getClaims(ctx)
-	if err != nil {
-		return common.ErrResponse(ctx, fiber.StatusUnauthorized, err.Error())
-	}
-	if !slices.Contains(claims.RealmAccess.Roles, web.IdmAdmin) {
-		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
-	}
-
 	// Парсинг JSON тела запроса в структуру DeleteByIdsRequest
 	var req DeleteByIdsRequest
 	if err := ctx.BodyParser(&req); err != nil {
@@ -427,9 +698,10 @@ func (c *Controller) DeleteEmployeesByIds(ctx *fiber.Ctx) error {
 		return common.ErrResponse(ctx, fiber.StatusBadRequest, "ids list cannot be empty")
 	}
 
-	// Удаление сотрудников по списку ID через сервис
+	// Удаление сотрудников по списку ID через сервис (если часть ID не найдена - 404 и ничего не удаляется)
 	if err := c.employeeService.DeleteByIds(ctx.Context(), req.Ids); err != nil {
-		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+		c.logger.ErrorCtx(ctx.Context(), "delete employees: failed to delete employees", zap.Error(err))
+		return handleError(ctx, err)
 	}
 
 	// Возврат статуса 204 No Content при успешном удалении
@@ -437,16 +709,188 @@ func (c *Controller) DeleteEmployeesByIds(ctx *fiber.Ctx) error {
 	return nil
 }
 
-// New helper: безопасно извлекает *jwt.Token и *web.IdmClaims, возвращает ошибку если нет токена или claims
-func getClaims(ctx *fiber.Ctx) (*web.IdmClaims, error) {
-	tokenVal := ctx.Locals(web.JwtKey)
-	token, ok := tokenVal.(*jwt.Token)
-	if !ok || token == nil {
-		return nil, errors.New("missing or invalid token")
+// RestoreEmployee восстанавливает удаленного сотрудника
+// @Summary Восстановить сотрудника
+// @Description Снять с сотрудника пометку об удалении
+// @Tags employee
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/restore [post]
+func (c *Controller) RestoreEmployee(ctx *fiber.Ctx) error {
+	// Извлечение и парсинг ID из параметров маршрута
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	// Восстановление сотрудника через сервис
+	resp, err := c.employeeService.Restore(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "restore employee: failed to restore employee", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// Ответ
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning restored employee")
+	}
+	return nil
+}
+
+// PurgeDeletedEmployees окончательно удаляет сотрудников, удаленных раньше срока хранения
+// @Summary Очистить удаленных сотрудников
+// @Description Окончательно удалить сотрудников, помеченных удаленными раньше чем older_than_days дней назад
+// @Tags employee
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body employee.PurgeDeletedRequest true "срок хранения удаленных сотрудников"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /employees/purge [post]
+func (c *Controller) PurgeDeletedEmployees(ctx *fiber.Ctx) error {
+	var req PurgeDeletedRequest
+
+	// Парсинг JSON
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "purge deleted employees: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// Вызов сервиса
+	resp, err := c.employeeService.PurgeDeleted(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "purge deleted employees: failed to purge employees", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// Ответ
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning purge result")
+	}
+	return nil
+}
+
+// ActivateEmployee выводит сотрудника на работу
+// @Summary Вывести сотрудника на работу
+// @Description Перевести сотрудника из статуса pre_hire или suspended в active. При первом выходе без даты приема устанавливается текущая дата
+// @Tags employee
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /employees/{id}/activate [post]
+func (c *Controller) ActivateEmployee(ctx *fiber.Ctx) error {
+	// Извлечение и парсинг ID из параметров маршрута
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	// Смена статуса через сервис
+	resp, err := c.employeeService.Activate(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "activate employee: failed to activate employee", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// Ответ
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning activated employee")
+	}
+	return nil
+}
+
+// SuspendEmployee приостанавливает работу сотрудника
+// @Summary Приостановить работу сотрудника
+// @Description Перевести сотрудника из статуса active в suspended; роли сотрудника сохраняются
+// @Tags employee
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /employees/{id}/suspend [post]
+func (c *Controller) SuspendEmployee(ctx *fiber.Ctx) error {
+	// Извлечение и парсинг ID из параметров маршрута
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
 	}
-	claims, ok := token.Claims.(*web.IdmClaims)
-	if !ok || claims == nil {
-		return nil, errors.New("invalid token claims")
+
+	// Смена статуса через сервис
+	resp, err := c.employeeService.Suspend(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "suspend employee: failed to suspend employee", zap.Error(err))
+		return handleError(ctx, err)
 	}
-	return claims, nil
+
+	// Ответ
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning suspended employee")
+	}
+	return nil
+}
+
+// TerminateEmployee увольняет сотрудника
+// @Summary Уволить сотрудника
+// @Description Перевести сотрудника в статус terminated и отозвать все его роли. Тело запроса необязательное; дата увольнения по умолчанию - текущая
+// @Tags employee
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param request body employee.TerminateRequest false "дата увольнения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /employees/{id}/terminate [post]
+func (c *Controller) TerminateEmployee(ctx *fiber.Ctx) error {
+	// Извлечение и парсинг ID из параметров маршрута
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	// Парсинг JSON, если тело передано
+	var req TerminateRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			c.logger.ErrorCtx(ctx.Context(), "terminate employee: invalid JSON", zap.Error(err))
+			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	// Смена статуса через сервис
+	resp, err := c.employeeService.Terminate(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "terminate employee: failed to terminate employee", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	// Ответ
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning terminated employee")
+	}
+	return nil
 }
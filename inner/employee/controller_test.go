@@ -31,6 +31,11 @@ func (m *MockEmployeeService) FindById(ctx context.Context, id int64) (Response,
 	return args.Get(0).(Response), args.Error(1)
 }
 
+func (m *MockEmployeeService) FindByIdWithDeleted(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
 func (m *MockEmployeeService) AddTransactional(ctx context.Context, request AddEmployeeRequest) (Response, error) {
 	args := m.Called(ctx, request)
 	return args.Get(0).(Response), args.Error(1)
@@ -39,8 +44,8 @@ func (m *MockEmployeeService) ValidateRequest(request interface{}) error {
 	args := m.Called(request)
 	return args.Error(0)
 }
-func (m *MockEmployeeService) Add(ctx context.Context, name string) (Response, error) {
-	args := m.Called(ctx, name)
+func (m *MockEmployeeService) Add(ctx context.Context, request AddEmployeeRequest) (Response, error) {
+	args := m.Called(ctx, request)
 	return args.Get(0).(Response), args.Error(1)
 }
 
@@ -49,21 +54,76 @@ func (m *MockEmployeeService) FindAll(ctx context.Context) ([]Response, error) {
 	return args.Get(0).([]Response), args.Error(1)
 }
 
+func (m *MockEmployeeService) FindAllWithDeleted(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockEmployeeService) Restore(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockEmployeeService) PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(PurgeResponse), args.Error(1)
+}
+
+func (m *MockEmployeeService) Activate(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockEmployeeService) Suspend(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockEmployeeService) Terminate(ctx context.Context, id int64, request TerminateRequest) (Response, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
 func (m *MockEmployeeService) FindByIds(ctx context.Context, ids []int64) ([]Response, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]Response), args.Error(1)
 }
 
+func (m *MockEmployeeService) Update(ctx context.Context, id int64, request UpdateEmployeeRequest, version int64) (Response, error) {
+	args := m.Called(ctx, id, request, version)
+	return args.Get(0).(Response), args.Error(1)
+}
+
 func (m *MockEmployeeService) DeleteById(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockEmployeeService) DeleteByIdIfMatch(ctx context.Context, id, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
 func (m *MockEmployeeService) DeleteByIds(ctx context.Context, ids []int64) error {
 	args := m.Called(ctx, ids)
 	return args.Error(0)
 }
 
+func (m *MockEmployeeService) FindAfter(ctx context.Context, req CursorRequest) (CursorResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(CursorResponse), args.Error(1)
+}
+
+func (m *MockEmployeeService) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]SearchResult), args.Error(1)
+}
+
+func (m *MockEmployeeService) Import(ctx context.Context, rows []ImportRow, dryRun bool) (ImportReport, error) {
+	args := m.Called(ctx, rows, dryRun)
+	return args.Get(0).(ImportReport), args.Error(1)
+}
+
 func (m *MockEmployeeService) FindPage(ctx context.Context, req PageRequest) (PageResponse, error) {
 	args := m.Called(ctx, req)
 	return args.Get(0).(PageResponse), args.Error(1)
@@ -149,7 +209,7 @@ func TestCreateEmployee(t *testing.T) {
 		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
 		request := AddEmployeeRequest{Name: "John Doe"}
 		expected := Response{Id: 1, Name: "John Doe"}
-		svc.On("Add", mock.Anything, "John Doe").Return(expected, nil)
+		svc.On("Add", mock.Anything, AddEmployeeRequest{Name: "John Doe"}).Return(expected, nil)
 
 		req := createTestRequest(t, "POST", "/api/v1/employees", request)
 		resp, err := app.Test(req)
@@ -164,6 +224,35 @@ func TestCreateEmployee(t *testing.T) {
 		svc.AssertExpectations(t)
 	})
 
+	t.Run("Success with profile", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		hireDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		request := AddEmployeeRequest{
+			Name:     "John Doe",
+			Profile:  Profile{Email: "john.doe@example.com", Login: "jdoe"},
+			Status:   StatusPreHire,
+			HireDate: &hireDate,
+		}
+		expected := Response{Id: 1, Name: "John Doe", Email: "john.doe@example.com", Login: "jdoe", HireDate: &hireDate, Status: StatusPreHire}
+		svc.On("Add", mock.Anything, mock.MatchedBy(func(got AddEmployeeRequest) bool {
+			return got.Login == "jdoe" && got.Status == StatusPreHire && got.HireDate != nil && got.HireDate.Equal(hireDate)
+		})).Return(expected, nil)
+
+		req := createTestRequest(t, "POST", "/api/v1/employees", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		var result common.Response[Response]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, "jdoe", result.Data.Login)
+		assert.Equal(t, StatusPreHire, result.Data.Status)
+		assert.True(t, hireDate.Equal(*result.Data.HireDate))
+		svc.AssertExpectations(t)
+	})
+
 	t.Run("Forbidden for non-admin role", func(t *testing.T) {
 		svc := new(MockEmployeeService)
 		app := setupAppWithAuth(t, svc, []string{"user"})
@@ -184,7 +273,7 @@ func TestCreateEmployee(t *testing.T) {
 	t.Run("Empty Name", func(t *testing.T) {
 		svc := new(MockEmployeeService)
 		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
-		svc.On("Add", mock.Anything, "").Return(
+		svc.On("Add", mock.Anything, AddEmployeeRequest{Name: ""}).Return(
 			Response{},
 			common.RequestValidationError{Message: "name cannot be empty"},
 		)
@@ -203,6 +292,23 @@ func TestCreateEmployee(t *testing.T) {
 		svc.AssertExpectations(t)
 	})
 
+	t.Run("Duplicate Name", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Add", mock.Anything, AddEmployeeRequest{Name: "John Doe"}).Return(
+			Response{},
+			common.AlreadyExistsError{Message: "employee with name 'John Doe' already exists"},
+		)
+
+		req := createTestRequest(t, "POST", "/api/v1/employees", AddEmployeeRequest{Name: "John Doe"})
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 409, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
 	t.Run("Invalid JSON", func(t *testing.T) {
 		svc := new(MockEmployeeService)
 		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
@@ -255,6 +361,24 @@ func TestCreateEmployeeTransactional(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode)
 		svc.AssertExpectations(t)
 	})
+
+	t.Run("Duplicate Name", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := AddEmployeeRequest{Name: "John Doe"}
+		svc.On("AddTransactional", mock.Anything, request).Return(
+			Response{},
+			common.AlreadyExistsError{Message: "employee with name 'John Doe' already exists"},
+		)
+
+		req := createTestRequest(t, "POST", "/api/v1/employees/transactional", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 409, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
 }
 
 func TestGetEmployee(t *testing.T) {
@@ -304,6 +428,28 @@ func TestGetEmployee(t *testing.T) {
 
 		assert.Equal(t, 400, resp.StatusCode)
 	})
+
+	t.Run("should look up deleted employee with restore permission", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindByIdWithDeleted", mock.Anything, int64(1)).Return(Response{Id: 1}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/1?includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+		svc.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 403 for includeDeleted without restore permission", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/1?includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		svc.AssertNotCalled(t, "FindByIdWithDeleted", mock.Anything, mock.Anything)
+	})
 }
 
 func TestGetAllEmployees(t *testing.T) {
@@ -330,6 +476,206 @@ func TestGetAllEmployees(t *testing.T) {
 	})
 }
 
+func TestGetEmployeesPage(t *testing.T) {
+	t.Run("should parse sort, date ranges and ids", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+		expected := PageRequest{
+			PageSize: 50, PageNumber: 1, Sort: "hire_date:desc",
+			CreatedFrom: &from, UpdatedTo: &to, Ids: []int64{3, 5, 8},
+		}
+		svc.On("FindPage", mock.Anything, mock.MatchedBy(func(req PageRequest) bool {
+			return req.PageSize == expected.PageSize && req.PageNumber == expected.PageNumber && req.Sort == expected.Sort &&
+				req.CreatedFrom.Equal(from) && req.CreatedTo == nil && req.UpdatedTo.Equal(to) &&
+				assert.ObjectsAreEqual(expected.Ids, req.Ids)
+		})).Return(PageResponse{Result: []Response{{Id: 3}}, Total: 1}, nil)
+
+		url := "/api/v1/employees/page?pageSize=50&pageNumber=1&sort=hire_date:desc" +
+			"&createdFrom=2025-01-01T00:00:00Z&updatedTo=2025-02-01T00:00:00Z&ids=3,5,8"
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for malformed date", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?createdFrom=yesterday", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for malformed ids", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?ids=1,x", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for sort outside whitelist", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindPage", mock.Anything, mock.Anything).Return(
+			PageResponse{}, common.RequestValidationError{Message: "invalid sort field 'password'"})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?sort=password", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestGetEmployeesPage_IncludeDeleted(t *testing.T) {
+	t.Run("should pass includeDeleted with restore permission", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindPage", mock.Anything, mock.MatchedBy(func(req PageRequest) bool {
+			return req.IncludeDeleted && req.PageSize == 10
+		})).Return(PageResponse{}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?pageSize=10&pageNumber=0&includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 without restore permission", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		for _, url := range []string{
+			"/api/v1/employees/page?pageSize=10&pageNumber=0&includeDeleted=true",
+			"/api/v1/employees/page?limit=10&includeDeleted=true",
+		} {
+			resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, 403, resp.StatusCode, url)
+		}
+		svc.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+		svc.AssertNotCalled(t, "FindAfter", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetEmployeesAfterCursor(t *testing.T) {
+	t.Run("should switch to cursor mode when after is given", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		cursor := common.EncodeCursor(20)
+		next := common.EncodeCursor(70)
+		svc.On("FindAfter", mock.Anything, CursorRequest{Limit: 50, After: cursor}).
+			Return(CursorResponse{Result: []Response{{Id: 21}}, Limit: 50, NextCursor: &next}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?limit=50&after="+cursor, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[CursorResponse]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, next, *result.Data.NextCursor)
+		svc.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should start from the beginning without after", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindAfter", mock.Anything, CursorRequest{Limit: 100, After: "", TextFilter: "john"}).
+			Return(CursorResponse{Limit: 100}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?limit=100&textFilter=john", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should reject sort in cursor mode", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?limit=10&sort=name", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "FindAfter", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should pass includeDeleted in cursor mode", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindAfter", mock.Anything, CursorRequest{Limit: 10, IncludeDeleted: true}).
+			Return(CursorResponse{Limit: 10}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?limit=10&includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid cursor", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("FindAfter", mock.Anything, mock.Anything).Return(CursorResponse{}, common.RequestValidationError{Message: "invalid cursor"})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/page?after=garbage", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestSearchEmployees(t *testing.T) {
+	t.Run("should return ranked results", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+		expected := []SearchResult{{Response: Response{Id: 7, Name: "Ivan Petrov"}, Rank: 0.8, Highlight: "<mark>Ivan</mark> Petrov"}}
+		svc.On("Search", mock.Anything, SearchRequest{Query: "ivan petorv", Limit: 20}).Return(expected, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/search?q=ivan%20petorv", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]SearchResult]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, expected[0].Id, result.Data[0].Id)
+		assert.Equal(t, expected[0].Highlight, result.Data[0].Highlight)
+	})
+
+	t.Run("should pass limit", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+		svc.On("Search", mock.Anything, SearchRequest{Query: "ivan", Limit: 5}).Return([]SearchResult{}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/search?q=ivan&limit=5", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid limit", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/search?q=ivan&limit=all", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 on validation error", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+		svc.On("Search", mock.Anything, mock.Anything).Return([]SearchResult{}, common.RequestValidationError{Message: "q is required"})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees/search", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
 func TestGetEmployeesByIds(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		svc := new(MockEmployeeService)
@@ -372,6 +718,57 @@ func TestGetEmployeesByIds(t *testing.T) {
 	})
 }
 
+func TestUpdateEmployee(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := UpdateEmployeeRequest{Name: "Jane Doe"}
+		expected := Response{Id: 1, Name: "Jane Doe"}
+		svc.On("Update", mock.Anything, int64(1), request, int64(0)).Return(expected, nil)
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/1", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		var result common.Response[Response]
+		parseResponse(t, resp, &result)
+		assert.True(t, result.Success)
+		assert.Equal(t, expected, result.Data)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := UpdateEmployeeRequest{Name: "Jane Doe"}
+		svc.On("Update", mock.Anything, int64(999), request, int64(0)).Return(
+			Response{}, common.NotFoundError{Message: "employee with id 999 not found"},
+		)
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/999", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 404, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Forbidden for non-admin role", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/1", UpdateEmployeeRequest{Name: "Jane Doe"})
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 403, resp.StatusCode)
+		svc.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestDeleteEmployee(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		svc := new(MockEmployeeService)
@@ -415,6 +812,103 @@ func TestDeleteEmployee(t *testing.T) {
 	})
 }
 
+func TestEmployeeConditionalRequests(t *testing.T) {
+	t.Run("GET returns ETag", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+		svc.On("FindById", mock.Anything, int64(1)).Return(Response{Id: 1, Name: "Test User", Version: 3}, nil)
+
+		req := httptest.NewRequest("GET", "/api/v1/employees/1", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `"3"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("GET with matching If-None-Match returns 304", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+		svc.On("FindById", mock.Anything, int64(1)).Return(Response{Id: 1, Name: "Test User", Version: 3}, nil)
+
+		req := httptest.NewRequest("GET", "/api/v1/employees/1", nil)
+		req.Header.Set("If-None-Match", `"3"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 304, resp.StatusCode)
+		assert.Equal(t, `"3"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("PUT passes If-Match version and returns new ETag", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := UpdateEmployeeRequest{Name: "Jane Doe"}
+		svc.On("Update", mock.Anything, int64(1), request, int64(3)).Return(Response{Id: 1, Name: "Jane Doe", Version: 4}, nil)
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/1", request)
+		req.Header.Set("If-Match", `"3"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("PUT with stale If-Match returns 412", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := UpdateEmployeeRequest{Name: "Jane Doe"}
+		svc.On("Update", mock.Anything, int64(1), request, int64(2)).Return(
+			Response{}, common.PreconditionFailedError{Message: "employee with id 1 has been modified"},
+		)
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/1", request)
+		req.Header.Set("If-Match", `"2"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 412, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("PUT with malformed If-Match returns 400", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		req := createTestRequest(t, "PUT", "/api/v1/employees/1", UpdateEmployeeRequest{Name: "Jane Doe"})
+		req.Header.Set("If-Match", "abc")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("DELETE with stale If-Match returns 412", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("DeleteByIdIfMatch", mock.Anything, int64(1), int64(2)).Return(
+			common.PreconditionFailedError{Message: "employee with id 1 has been modified"},
+		)
+
+		req := httptest.NewRequest("DELETE", "/api/v1/employees/1", nil)
+		req.Header.Set("If-Match", `"2"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 412, resp.StatusCode)
+		svc.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDeleteEmployeesByIds(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		svc := new(MockEmployeeService)
@@ -447,6 +941,23 @@ func TestDeleteEmployeesByIds(t *testing.T) {
 		assert.Equal(t, 400, resp.StatusCode)
 		svc.AssertExpectations(t)
 	})
+
+	t.Run("Missing IDs", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := DeleteByIdsRequest{Ids: []int64{1, 2}}
+		svc.On("ValidateRequest", request).Return(nil)
+		svc.On("DeleteByIds", mock.Anything, []int64{1, 2}).Return(
+			common.NotFoundError{Message: "employees not found: [2]; no employees were deleted"})
+
+		req := createTestRequest(t, "DELETE", "/api/v1/employees", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		assert.Equal(t, 404, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
 }
 
 // --- Тесты на аутентификацию и авторизацию для всех эндпоинтов ---
@@ -590,7 +1101,7 @@ func TestCreateEmployee_WithAuth(t *testing.T) {
 		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
 		request := AddEmployeeRequest{Name: "John Doe"}
 		expected := Response{Id: 1, Name: "John Doe"}
-		svc.On("Add", mock.Anything, "John Doe").Return(expected, nil)
+		svc.On("Add", mock.Anything, AddEmployeeRequest{Name: "John Doe"}).Return(expected, nil)
 
 		req := createTestRequest(t, "POST", "/api/v1/employees", request)
 		resp, err := app.Test(req)
@@ -642,7 +1153,7 @@ func TestIncorrectDataHttpResponses(t *testing.T) {
 			expectedStatus: 400,
 			expectedError:  "cannot be empty",
 			setupMock: func(m *MockEmployeeService) {
-				m.On("Add", mock.Anything, "").Return(
+				m.On("Add", mock.Anything, AddEmployeeRequest{Name: ""}).Return(
 					Response{}, common.RequestValidationError{Message: "name cannot be empty"})
 			},
 		},
@@ -892,7 +1403,7 @@ func TestEmployeeAuthenticationAndAuthorization(t *testing.T) {
 			body:          AddEmployeeRequest{Name: "New Employee"},
 			requiredRoles: []string{web.IdmAdmin},
 			setupMock: func(svc *MockEmployeeService) {
-				svc.On("Add", mock.Anything, "New Employee").Return(testEmployee, nil)
+				svc.On("Add", mock.Anything, AddEmployeeRequest{Name: "New Employee"}).Return(testEmployee, nil)
 			},
 		},
 		{
@@ -1128,3 +1639,291 @@ func createAuthenticatedRequest(t *testing.T, method, path string, body interfac
 
 	return req
 }
+
+func TestEmployeeSoftDelete(t *testing.T) {
+	t.Run("should return deleted employees for admin", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		deletedAt := time.Now().UTC().Truncate(time.Second)
+		expected := []Response{{Id: 1, Name: "User 1"}, {Id: 2, Name: "User 2", DeletedAt: &deletedAt}}
+		svc.On("FindAllWithDeleted", mock.Anything).Return(expected, nil)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees?includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]Response]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, expected, result.Data)
+		svc.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("should forbid deleted employees for user", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/employees?includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should restore employee", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Restore", mock.Anything, int64(5)).Return(Response{Id: 5, Name: "User 5", Version: 3}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/restore", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 when restoring employee that is not deleted", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Restore", mock.Anything, int64(5)).Return(Response{}, common.NotFoundError{Message: "deleted employee with id 5 not found"})
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/restore", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("should forbid restore for user", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/restore", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should purge deleted employees", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		request := PurgeDeletedRequest{OlderThanDays: 90}
+		svc.On("PurgeDeleted", mock.Anything, request).Return(PurgeResponse{Purged: 2}, nil)
+
+		resp, err := app.Test(createTestRequest(t, "POST", "/api/v1/employees/purge", request))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[PurgeResponse]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, int64(2), result.Data.Purged)
+	})
+}
+
+func TestEmployeeLifecycle(t *testing.T) {
+	t.Run("should terminate employee without body", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Terminate", mock.Anything, int64(5), TerminateRequest{}).Return(Response{Id: 5, Status: StatusTerminated}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/terminate", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[Response]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, StatusTerminated, result.Data.Status)
+	})
+
+	t.Run("should pass termination date", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		terminationDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		request := TerminateRequest{TerminationDate: &terminationDate}
+		svc.On("Terminate", mock.Anything, int64(5), request).Return(Response{Id: 5, Status: StatusTerminated}, nil)
+
+		resp, err := app.Test(createTestRequest(t, "POST", "/api/v1/employees/5/terminate", request))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 409 for forbidden transition", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Suspend", mock.Anything, int64(5)).
+			Return(Response{}, common.ConflictError{Message: "employee 5 cannot change status from 'terminated' to 'suspended'"})
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/suspend", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("should activate employee", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Activate", mock.Anything, int64(5)).Return(Response{Id: 5, Status: StatusActive}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/activate", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should forbid lifecycle changes for user", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmUser})
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/employees/5/terminate", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestImportEmployees(t *testing.T) {
+	newImportRequest := func(url, contentType, body string) *http.Request {
+		req := httptest.NewRequest("POST", url, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	t.Run("should parse CSV with header", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		hireDate := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		expected := []ImportRow{
+			{Line: 2, Request: AddEmployeeRequest{Name: "Ivan Petrov", Profile: Profile{Login: "ipetrov"}, HireDate: &hireDate},
+				Fields: []string{"name", "login", "hire_date"}},
+			{Line: 3, Request: AddEmployeeRequest{Name: "Maria Sidorova"}, Fields: []string{"name"}},
+		}
+		svc.On("Import", mock.Anything, expected, true).
+			Return(ImportReport{DryRun: true, Created: 2}, nil)
+
+		body := "\ufeffName,login,hire_date\nIvan Petrov, ipetrov,2025-07-01\nMaria Sidorova,,\n"
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import?dryRun=true", "text/csv; charset=utf-8", body))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[ImportReport]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, 2, result.Data.Created)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should report malformed CSV rows", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Import", mock.Anything, mock.MatchedBy(func(rows []ImportRow) bool {
+			return len(rows) == 2 && rows[0].Err != nil && rows[0].Err.Error() == "invalid hire_date: expected YYYY-MM-DD" &&
+				rows[1].Err != nil && rows[1].Line == 3
+		}), false).Return(ImportReport{Errors: 2}, nil)
+
+		body := "name,hire_date\nIvan Petrov,01.07.2025\nMaria Sidorova\n"
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "text/csv", body))
+		assert.NoError(t, err)
+		assert.Equal(t, 422, resp.StatusCode)
+
+		var result common.Response[ImportReport]
+		parseResponse(t, resp, &result)
+		assert.False(t, result.Success)
+		assert.Equal(t, 2, result.Data.Errors)
+	})
+
+	t.Run("should return 400 for unknown CSV column", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "text/csv", "name,password\nIvan,secret\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for termination_date column", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "text/csv", "name,termination_date\nIvan,2025-06-30\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		svc.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should parse NDJSON and skip blank lines", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+		svc.On("Import", mock.Anything, mock.MatchedBy(func(rows []ImportRow) bool {
+			return len(rows) == 2 && rows[0].Line == 1 && rows[0].Request.Name == "Ivan Petrov" && rows[0].Request.Status == StatusPreHire &&
+				assert.ObjectsAreEqual([]string{"name", "status"}, rows[0].Fields) && rows[1].Line == 3 && rows[1].Err != nil
+		}), false).Return(ImportReport{Applied: true, Created: 1}, nil)
+
+		body := `{"name":"Ivan Petrov","status":"pre_hire"}` + "\n\n" + `{"name":"Maria","salary":1}` + "\n"
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "application/x-ndjson", body))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 415 for unsupported content type", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "application/xml", "<employees/>"))
+		assert.NoError(t, err)
+		assert.Equal(t, 415, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for empty file", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app := setupAppWithAuth(t, svc, []string{web.IdmAdmin})
+
+		resp, err := app.Test(newImportRequest("/api/v1/employees/import", "text/csv", "name\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestAuditSkip(t *testing.T) {
+	// setup собирает приложение с middleware, которое запоминает отметку web.SkipAudit, как это делает журнал аудита
+	setup := func(svc *MockEmployeeService) (*fiber.App, *bool) {
+		app := fiber.New()
+		server := &web.Server{App: app, GroupApiV1: app.Group("/api/v1")}
+		skipped := new(bool)
+		server.GroupApiV1.Use(func(c *fiber.Ctx) error {
+			c.Locals(web.JwtKey, makeJWTToken([]string{web.IdmAdmin}))
+			err := c.Next()
+			*skipped = web.AuditSkipped(c)
+			return err
+		})
+		NewController(server, svc, &common.Logger{Logger: zap.NewNop()}).RegisterRoutes()
+		return app, skipped
+	}
+
+	t.Run("should skip audit for read-only by-ids", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app, skipped := setup(svc)
+		svc.On("ValidateRequest", mock.Anything).Return(nil)
+		svc.On("FindByIds", mock.Anything, []int64{1}).Return([]Response{{Id: 1}}, nil)
+
+		resp, err := app.Test(createTestRequest(t, "POST", "/api/v1/employees/by-ids", FindByIdsRequest{Ids: []int64{1}}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, *skipped)
+	})
+
+	t.Run("should skip audit for dry-run import only", func(t *testing.T) {
+		svc := new(MockEmployeeService)
+		app, skipped := setup(svc)
+		svc.On("Import", mock.Anything, mock.Anything, true).Return(ImportReport{DryRun: true}, nil)
+		svc.On("Import", mock.Anything, mock.Anything, false).Return(ImportReport{Applied: true}, nil)
+
+		req := httptest.NewRequest("POST", "/api/v1/employees/import?dryRun=true", strings.NewReader("name\nIvan Petrov\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, *skipped)
+
+		req = httptest.NewRequest("POST", "/api/v1/employees/import", strings.NewReader("name\nIvan Petrov\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.False(t, *skipped)
+	})
+}
@@ -1,29 +1,145 @@
 package employee
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+// Статусы сотрудника
+const (
+	StatusPreHire    = "pre_hire"
+	StatusActive     = "active"
+	StatusSuspended  = "suspended"
+	StatusTerminated = "terminated"
+)
+
+// transitions - допустимые переходы между статусами сотрудника.
+// Увольнение - конечный статус: повторный прием оформляется как новый сотрудник
+var transitions = map[string][]string{
+	StatusPreHire:   {StatusActive, StatusTerminated},
+	StatusActive:    {StatusSuspended, StatusTerminated},
+	StatusSuspended: {StatusActive, StatusTerminated},
+}
+
+// canTransition сообщает, допустим ли переход сотрудника из статуса from в статус to
+func canTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
 
 // Entity представляет сущность сотрудника в базе данных
 type Entity struct {
-	Id        int64     `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	Id              int64      `db:"id"`
+	Name            string     `db:"name"`
+	Email           string     `db:"email"`
+	Login           string     `db:"login"`
+	EmployeeNumber  string     `db:"employee_number"`
+	FirstName       string     `db:"first_name"`
+	LastName        string     `db:"last_name"`
+	JobTitle        string     `db:"job_title"`
+	Phone           string     `db:"phone"`
+	HireDate        *time.Time `db:"hire_date"`
+	TerminationDate *time.Time `db:"termination_date"`
+	Status          string     `db:"status"`
+	OrgUnitId       *int64     `db:"org_unit_id"`
+	ManagerId       *int64     `db:"manager_id"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+	Version         int64      `db:"version"`
+	DeletedAt       *time.Time `db:"deleted_at"`
 }
 
 // toResponse преобразует Entity в Response
 func (e *Entity) toResponse() Response {
 	return Response{
-		Id:        e.Id,
-		Name:      e.Name,
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		Id:              e.Id,
+		Name:            e.Name,
+		Email:           e.Email,
+		Login:           e.Login,
+		EmployeeNumber:  e.EmployeeNumber,
+		FirstName:       e.FirstName,
+		LastName:        e.LastName,
+		JobTitle:        e.JobTitle,
+		Phone:           e.Phone,
+		HireDate:        e.HireDate,
+		TerminationDate: e.TerminationDate,
+		Status:          e.Status,
+		OrgUnitId:       e.OrgUnitId,
+		ManagerId:       e.ManagerId,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+		Version:         e.Version,
+		DeletedAt:       e.DeletedAt,
 	}
 }
 
 // Response представляет ответ API для сотрудника
 type Response struct {
-	Id        int64     `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id              int64      `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Login           string     `json:"login"`
+	EmployeeNumber  string     `json:"employee_number"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	JobTitle        string     `json:"job_title"`
+	Phone           string     `json:"phone"`
+	HireDate        *time.Time `json:"hire_date,omitempty"`
+	TerminationDate *time.Time `json:"termination_date,omitempty"`
+	Status          string     `json:"status"`
+	OrgUnitId       *int64     `json:"org_unit_id,omitempty"`
+	ManagerId       *int64     `json:"manager_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int64      `json:"version"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// PurgeResponse представляет результат окончательного удаления сотрудников
+type PurgeResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// SearchEntity представляет сотрудника, найденного поиском, вместе с оценкой релевантности и фрагментом с подсветкой
+type SearchEntity struct {
+	Entity
+	Rank      float64 `db:"rank"`
+	Highlight string  `db:"highlight"`
+}
+
+// SearchResult представляет ответ API для сотрудника, найденного поиском.
+// Highlight содержит анкетные данные, в которых совпавшие слова обрамлены тегами <mark>;
+// при совпадении только по сходству написания (опечатке) выделений в нем нет
+type SearchResult struct {
+	Response
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+}
+
+// Действия импорта со строкой файла
+const (
+	ImportCreate   = "create"
+	ImportUpdate   = "update"
+	ImportConflict = "conflict"
+	ImportError    = "error"
+)
+
+// ImportRowResult представляет результат импорта одной строки файла: что сделано (или будет сделано при dryRun)
+// с сотрудником и почему строка не может быть импортирована
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Id     int64  `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport представляет отчет импорта сотрудников; Applied сообщает, сохранены ли изменения
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Applied   bool              `json:"applied"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Conflicts int               `json:"conflicts"`
+	Errors    int               `json:"errors"`
+	Rows      []ImportRowResult `json:"rows"`
 }
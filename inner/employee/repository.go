@@ -3,6 +3,10 @@ package employee
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -42,6 +46,57 @@ func (w *RowWrapper) Scan(dest ...interface{}) error {
 	return w.row.Scan(dest...)
 }
 
+// loginUniqueIndex - уникальный индекс на логин сотрудника без учета регистра
+const loginUniqueIndex = "employee_login_lower_key"
+
+// insertQuery добавляет сотрудника со всеми анкетными данными
+const insertQuery = `INSERT INTO employee (name, created_at, updated_at, email, login, employee_number,
+	first_name, last_name, job_title, phone, hire_date, termination_date, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+
+// textFilterCondition ищет подстроку в имени и анкетных данных сотрудника
+const textFilterCondition = `(name ilike $%[1]d OR email ilike $%[1]d OR login ilike $%[1]d OR employee_number ilike $%[1]d
+	OR first_name ilike $%[1]d OR last_name ilike $%[1]d OR job_title ilike $%[1]d)`
+
+// searchVector - взвешенный документ полнотекстового поиска: имя важнее учетных данных, а они важнее должности.
+// Выражение должно совпадать с индексом employee_search_idx, иначе поиск будет читать всю таблицу
+const searchVector = `(setweight(to_tsvector('simple', name || ' ' || first_name || ' ' || last_name), 'A') ||
+	setweight(to_tsvector('simple', login || ' ' || email || ' ' || employee_number), 'B') ||
+	setweight(to_tsvector('simple', job_title), 'C'))`
+
+// searchQuery находит сотрудников по префиксному tsquery ($1) или по сходству имени с текстом запроса ($2)
+// и упорядочивает их по сумме полнотекстового ранга и сходства имени; $3 - максимальное количество
+const searchQuery = `SELECT employee.*, ts_rank(` + searchVector + `, q) + word_similarity($2, name) AS rank,
+	ts_headline('simple', concat_ws(' | ', name, nullif(job_title, ''), nullif(email, ''), nullif(login, ''),
+		nullif(employee_number, '')), q, 'StartSel=<mark>, StopSel=</mark>') AS highlight
+	FROM employee, to_tsquery('simple', $1) AS q
+	WHERE deleted_at IS NULL AND (` + searchVector + ` @@ q OR $2 <% name)
+	ORDER BY rank DESC, id LIMIT $3`
+
+// searchSimilarityThreshold - минимальное сходство текста запроса со словами имени (pg_trgm word_similarity).
+// Порог по умолчанию 0.6 отсекает опечатки в коротких именах: "Ivna" и "Ivan" похожи лишь на 0.4
+const searchSimilarityThreshold = "0.3"
+
+// updateQuery изменяет имя и анкетные данные сотрудника; версия проверяется, только если она не равна 0.
+// Даты приема и увольнения не меняются: их устанавливает только ChangeStatus
+const updateQuery = `UPDATE employee SET name = $1, updated_at = $2, version = version + 1,
+	email = $5, login = $6, employee_number = $7, first_name = $8, last_name = $9, job_title = $10,
+	phone = $11
+	WHERE id = $3 AND deleted_at IS NULL AND ($4::bigint = 0 OR version = $4::bigint)
+	RETURNING created_at, version, status, org_unit_id, manager_id, hire_date, termination_date`
+
+// insertArgs возвращает значения для insertQuery
+func (e *Entity) insertArgs() []any {
+	return []any{e.Name, e.CreatedAt, e.UpdatedAt, e.Email, e.Login, e.EmployeeNumber,
+		e.FirstName, e.LastName, e.JobTitle, e.Phone, e.HireDate, e.TerminationDate, e.Status}
+}
+
+// updateArgs возвращает значения для updateQuery
+func (e *Entity) updateArgs() []any {
+	return []any{e.Name, e.UpdatedAt, e.Id, e.Version, e.Email, e.Login, e.EmployeeNumber,
+		e.FirstName, e.LastName, e.JobTitle, e.Phone}
+}
+
 // Repository представляет репозиторий для работы с сотрудниками
 type Repository struct {
 	db *sqlx.DB
@@ -52,37 +107,138 @@ func NewRepository(database *sqlx.DB) *Repository {
 	return &Repository{db: database}
 }
 
+// FindById возвращает сотрудника по ID; удаленные сотрудники не возвращаются
 func (r *Repository) FindById(ctx context.Context, id int64) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "select * from employee where id = $1 and deleted_at is null", id)
+	return res, err
+}
+
+// FindByIdWithDeleted возвращает сотрудника по ID, в том числе удаленного
+func (r *Repository) FindByIdWithDeleted(ctx context.Context, id int64) (res Entity, err error) {
 	err = r.db.GetContext(ctx, &res, "select * from employee where id = $1", id)
 	return res, err
 }
 
 func (r *Repository) Add(ctx context.Context, e *Entity) error {
-	query := `INSERT INTO employee (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id`
-	return r.db.QueryRowContext(ctx, query, e.Name, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+	return r.db.QueryRowContext(ctx, insertQuery, e.insertArgs()...).Scan(&e.Id)
 }
 
+// FindAll возвращает всех неудаленных сотрудников
 func (r *Repository) FindAll(ctx context.Context) ([]Entity, error) {
+	var res []Entity
+	err := r.db.SelectContext(ctx, &res, "SELECT * FROM employee WHERE deleted_at IS NULL")
+	return res, err
+}
+
+// FindAllWithDeleted возвращает всех сотрудников, включая удаленных
+func (r *Repository) FindAllWithDeleted(ctx context.Context) ([]Entity, error) {
 	var res []Entity
 	err := r.db.SelectContext(ctx, &res, "SELECT * FROM employee")
 	return res, err
 }
 
 func (r *Repository) FindByIds(ctx context.Context, ids []int64) ([]Entity, error) {
-	query := `SELECT * FROM employee WHERE id = ANY($1)`
+	query := `SELECT * FROM employee WHERE id = ANY($1) AND deleted_at IS NULL`
 	var res []Entity
 	err := r.db.SelectContext(ctx, &res, query, pq.Array(ids))
 	return res, err
 }
 
+// Update обновляет имя и анкетные данные сотрудника и увеличивает версию записи;
+// статус, подразделение и руководитель не меняются.
+// Если e.Version больше 0, обновление выполняется только при совпадении версии.
+// Возвращает sql.ErrNoRows, если сотрудник не найден, удален или версия не совпала
+func (r *Repository) Update(ctx context.Context, e *Entity) error {
+	return r.db.QueryRowContext(ctx, updateQuery, e.updateArgs()...).
+		Scan(&e.CreatedAt, &e.Version, &e.Status, &e.OrgUnitId, &e.ManagerId, &e.HireDate, &e.TerminationDate)
+}
+
+// DeleteById помечает сотрудника удаленным (мягкое удаление); запись можно восстановить через Restore.
+// Возвращает sql.ErrNoRows, если сотрудник не найден или уже удален
 func (r *Repository) DeleteById(ctx context.Context, id int64) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM employee WHERE id = $1", id)
-	return err
+	res, err := r.db.ExecContext(ctx, "UPDATE employee SET deleted_at = now(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
-func (r *Repository) DeleteByIds(ctx context.Context, ids []int64) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM employee WHERE id = ANY($1)", pq.Array(ids))
-	return err
+// DeleteByIdAndVersion помечает сотрудника удаленным только при совпадении версии, возвращает количество удаленных строк
+func (r *Repository) DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE employee SET deleted_at = now(), version = version + 1 WHERE id = $1 AND version = $2 AND deleted_at IS NULL", id, version)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DeleteByIds помечает сотрудников удаленными (мягкое удаление) и возвращает ID найденных сотрудников.
+// Удаление выполняется целиком или не выполняется: если часть ID не найдена, транзакция откатывается,
+// и по возвращенным ID можно определить, каких сотрудников не хватило
+func (r *Repository) DeleteByIds(ctx context.Context, ids []int64) (deleted []int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE employee SET deleted_at = now(), version = version + 1
+		WHERE id = ANY($1) AND deleted_at IS NULL RETURNING id`
+	if err = tx.SelectContext(ctx, &deleted, query, pq.Array(ids)); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	if len(deleted) < countUnique(ids) {
+		return deleted, tx.Rollback()
+	}
+	return deleted, tx.Commit()
+}
+
+// Restore снимает с сотрудника пометку об удалении. Возвращает sql.ErrNoRows, если удаленный сотрудник не найден
+func (r *Repository) Restore(ctx context.Context, id int64, at time.Time) (res Entity, err error) {
+	query := `UPDATE employee SET deleted_at = NULL, updated_at = $2, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL RETURNING *`
+	err = r.db.GetContext(ctx, &res, query, id, at)
+	return res, err
+}
+
+// PurgeDeleted окончательно удаляет сотрудников, помеченных удаленными раньше before, возвращает их количество
+func (r *Repository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM employee WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ChangeStatus переводит сотрудника в статус e.Status и сохраняет даты приема и увольнения из e.
+// Переход выполняется, только если сотрудник все еще находится в статусе from; при увольнении
+// в той же транзакции отзываются все его роли.
+// Возвращает sql.ErrNoRows, если неудаленный сотрудник в статусе from не найден
+func (r *Repository) ChangeStatus(ctx context.Context, e *Entity, from string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE employee SET status = $2, hire_date = $3, termination_date = $4,
+		updated_at = $5, version = version + 1
+		WHERE id = $1 AND status = $6 AND deleted_at IS NULL RETURNING *`
+	if err := tx.GetContext(ctx, e, query, e.Id, e.Status, e.HireDate, e.TerminationDate, e.UpdatedAt, from); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	if e.Status == StatusTerminated {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM employee_role WHERE employee_id = $1", e.Id); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+	return tx.Commit()
 }
 
 // BeginTransaction начинает новую транзакцию
@@ -94,13 +250,14 @@ func (r *Repository) BeginTransaction(ctx context.Context) (Transaction, error)
 	return &TxWrapper{tx: tx}, nil
 }
 
-// FindByNameTx проверяет наличие в базе данных сотрудника с заданным именем в рамках транзакции
-
+// FindByNameTx проверяет наличие в базе данных сотрудника с заданным именем в рамках транзакции.
+// Имя сравнивается без учета регистра, как в уникальном индексе employee_name_lower_key.
+// Удаленные сотрудники учитываются, чтобы их восстановление не приводило к дублированию имен
 func (r *Repository) FindByNameTx(_ context.Context, tx Transaction, name string) (bool, error) {
 	var exists bool
 	err := tx.Get(
 		&exists,
-		"SELECT EXISTS(SELECT 1 FROM employee WHERE name = $1)",
+		"SELECT EXISTS(SELECT 1 FROM employee WHERE lower(name) = lower($1))",
 		name,
 	)
 	return exists, err
@@ -108,49 +265,150 @@ func (r *Repository) FindByNameTx(_ context.Context, tx Transaction, name string
 
 // AddTx добавляет нового сотрудника в рамках транзакции
 func (r *Repository) AddTx(ctx context.Context, tx Transaction, e *Entity) error {
-	query := `INSERT INTO employee (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id`
-	return tx.QueryRowContext(ctx, query, e.Name, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+	return tx.QueryRowContext(ctx, insertQuery, e.insertArgs()...).Scan(&e.Id)
+}
+
+// FindByNameForUpdateTx возвращает сотрудника, включая удаленного, по имени без учета регистра и блокирует
+// его запись до конца транзакции. Возвращает sql.ErrNoRows, если сотрудника с таким именем нет
+func (r *Repository) FindByNameForUpdateTx(_ context.Context, tx Transaction, name string) (res Entity, err error) {
+	err = tx.Get(&res, "SELECT * FROM employee WHERE lower(name) = lower($1) FOR UPDATE", name)
+	return res, err
+}
+
+// FindIdByLoginTx возвращает ID сотрудника, включая удаленного, с заданным без учета регистра логином,
+// или 0, если логин свободен
+func (r *Repository) FindIdByLoginTx(_ context.Context, tx Transaction, login string) (int64, error) {
+	var id int64
+	err := tx.Get(&id, "SELECT id FROM employee WHERE lower(login) = lower($1) AND login <> ''", login)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// UpdateTx изменяет имя и анкетные данные сотрудника в рамках транзакции
+func (r *Repository) UpdateTx(ctx context.Context, tx Transaction, e *Entity) error {
+	return tx.QueryRowContext(ctx, updateQuery, e.updateArgs()...).
+		Scan(&e.CreatedAt, &e.Version, &e.Status, &e.OrgUnitId, &e.ManagerId, &e.HireDate, &e.TerminationDate)
+}
+
+// FindPage возвращает страницу сотрудников, отобранных по фильтрам запроса.
+// Сотрудники сортируются по полю из req.Sort, а при равных значениях - по id, чтобы страницы не пересекались
+func (r *Repository) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	column, direction, err := req.sortOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	order := column + " " + direction
+	if column != "id" {
+		order += ", id " + direction
+	}
+
+	where, args := buildFilter(req)
+	args = append(args, req.PageSize, req.PageNumber*req.PageSize)
+	query := fmt.Sprintf("SELECT * FROM employee%s ORDER BY %s LIMIT $%d OFFSET $%d", where, order, len(args)-1, len(args))
+	var res []Entity
+	err = r.db.SelectContext(ctx, &res, query, args...)
+	return res, err
 }
 
-// FindPage возвращает сотрудников с учетом пагинации (limit, offset, textFilter)
-func (r *Repository) FindPage(ctx context.Context, limit, offset int, textFilter string) ([]Entity, error) {
+// FindAfter возвращает не более limit сотрудников с id больше afterId, отобранных по фильтрам, по возрастанию id.
+// В отличие от FindPage, запрос не пропускает строки через OFFSET и одинаково быстр на любой глубине списка
+func (r *Repository) FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error) {
+	conditions, args := filterConditions(filter)
+	args = append(args, afterId, limit)
+	conditions = append(conditions, fmt.Sprintf("id > $%d", len(args)-1))
+	query := fmt.Sprintf("SELECT * FROM employee%s ORDER BY id LIMIT $%d", whereClause(conditions), len(args))
 	var res []Entity
-	var (
-		query string
-		args  []interface{}
-	)
-	baseQuery := "SELECT * FROM employee WHERE 1=1"
-	if validTextFilter(textFilter) {
-		baseQuery += " AND name ilike $1"
-		args = append(args, "%"+textFilter+"%")
-		baseQuery += " OFFSET $2 LIMIT $3"
-		args = append(args, offset, limit)
-	} else {
-		baseQuery += " OFFSET $1 LIMIT $2"
-		args = append(args, offset, limit)
-	}
-	query = baseQuery
 	err := r.db.SelectContext(ctx, &res, query, args...)
 	return res, err
 }
 
-// CountAll возвращает общее количество сотрудников с учетом фильтра
-func (r *Repository) CountAll(ctx context.Context, textFilter string) (int64, error) {
-	var total int64
-	var (
-		query string
-		args  []interface{}
-	)
-	baseQuery := "SELECT COUNT(*) FROM employee WHERE 1=1"
-	if validTextFilter(textFilter) {
-		baseQuery += " AND name ilike $1"
-		args = append(args, "%"+textFilter+"%")
+// Search возвращает не более limit сотрудников, найденных полнотекстовым поиском по tsQuery
+// или по сходству имени с text, в порядке убывания релевантности
+func (r *Repository) Search(ctx context.Context, tsQuery, text string, limit int) ([]SearchEntity, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
 	}
-	query = baseQuery
-	err := r.db.GetContext(ctx, &total, query, args...)
+	// порог задается только на время транзакции и не влияет на другие запросы через то же соединение
+	if _, err = tx.ExecContext(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", searchSimilarityThreshold); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	var res []SearchEntity
+	if err = tx.SelectContext(ctx, &res, searchQuery, tsQuery, text, limit); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	return res, tx.Commit()
+}
+
+// CountAll возвращает общее количество сотрудников с учетом фильтров запроса
+func (r *Repository) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	where, args := buildFilter(req)
+	var total int64
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM employee"+where, args...)
 	return total, err
 }
 
+// buildFilter формирует условие WHERE и его параметры из непустых фильтров запроса
+func buildFilter(req PageRequest) (string, []any) {
+	conditions, args := filterConditions(req)
+	return whereClause(conditions), args
+}
+
+// filterConditions возвращает условия отбора и их параметры из непустых фильтров запроса;
+// удаленные сотрудники попадают в выборку только при IncludeDeleted
+func filterConditions(req PageRequest) ([]string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !req.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if validTextFilter(req.TextFilter) {
+		add(textFilterCondition, "%"+req.TextFilter+"%")
+	}
+	if req.CreatedFrom != nil {
+		add("created_at >= $%d", *req.CreatedFrom)
+	}
+	if req.CreatedTo != nil {
+		add("created_at < $%d", *req.CreatedTo)
+	}
+	if req.UpdatedFrom != nil {
+		add("updated_at >= $%d", *req.UpdatedFrom)
+	}
+	if req.UpdatedTo != nil {
+		add("updated_at < $%d", *req.UpdatedTo)
+	}
+	if len(req.Ids) > 0 {
+		add("id = ANY($%d)", pq.Array(req.Ids))
+	}
+	return conditions, args
+}
+
+// whereClause объединяет условия отбора в WHERE; без условий возвращает пустую строку
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// countUnique возвращает количество различных ID в списке
+func countUnique(ids []int64) int {
+	unique := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return len(unique)
+}
+
 // validTextFilter проверяет, что фильтр содержит минимум 3 непробельных символа
 func validTextFilter(s string) bool {
 	count := 0
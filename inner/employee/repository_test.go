@@ -2,6 +2,7 @@ package employee
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -22,13 +23,14 @@ func TestRepository_TransactionalMethods(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Настраиваем mock для проверки существования сотрудника
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
 		// Настраиваем mock для создания сотрудника
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 		// Настраиваем mock для коммита транзакции
@@ -86,7 +88,7 @@ func TestRepository_TransactionalMethods(t *testing.T) {
 		mock.ExpectBegin()
 
 		// Настраиваем mock для проверки существования сотрудника
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("Existing Employee").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
@@ -121,3 +123,257 @@ func TestRepository_TransactionalMethods(t *testing.T) {
 		a.NoError(mock.ExpectationsWereMet())
 	})
 }
+
+func TestRepository_SoftDelete(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should mark employee deleted instead of deleting row", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectExec(`UPDATE employee SET deleted_at = now\(\), version = version \+ 1 WHERE id = \$1 AND deleted_at IS NULL`).
+			WithArgs(int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		a.NoError(repo.DeleteById(context.Background(), 7))
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should roll back bulk delete when some employees are missing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE employee SET deleted_at = now\(\), version = version \+ 1\s+WHERE id = ANY\(\$1\) AND deleted_at IS NULL RETURNING id`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+		mock.ExpectRollback()
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		deleted, err := repo.DeleteByIds(context.Background(), []int64{1, 2})
+		a.NoError(err)
+		a.Equal([]int64{1}, deleted)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should revoke all roles when terminating employee", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE employee SET status = \$2, hire_date = \$3, termination_date = \$4,\s+updated_at = \$5, version = version \+ 1\s+WHERE id = \$1 AND status = \$6 AND deleted_at IS NULL RETURNING \*`).
+			WithArgs(int64(1), StatusTerminated, nil, &now, now, StatusActive).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "version"}).AddRow(int64(1), "John Doe", StatusTerminated, int64(4)))
+		mock.ExpectExec(`DELETE FROM employee_role WHERE employee_id = \$1`).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		e := &Entity{Id: 1, Status: StatusTerminated, TerminationDate: &now, UpdatedAt: now}
+		a.NoError(repo.ChangeStatus(context.Background(), e, StatusActive))
+		a.Equal(int64(4), e.Version)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not change status already changed by another request", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE employee SET status = \$2`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		err = repo.ChangeStatus(context.Background(), &Entity{Id: 1, Status: StatusSuspended}, StatusActive)
+		a.ErrorIs(err, sql.ErrNoRows)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should purge only rows deleted before cutoff", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		before := time.Now().AddDate(0, 0, -30)
+		mock.ExpectExec(`DELETE FROM employee WHERE deleted_at < \$1`).
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		purged, err := repo.PurgeDeleted(context.Background(), before)
+		a.NoError(err)
+		a.Equal(int64(2), purged)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_FindPage(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should sort by requested field with id tie-break and apply filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT \* FROM employee WHERE deleted_at IS NULL AND created_at >= \$1 AND id = ANY\(\$2\)\s+ORDER BY lower\(name\) DESC, id DESC LIMIT \$3 OFFSET \$4`).
+			WithArgs(from, sqlmock.AnyArg(), 10, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(2), "Bob").AddRow(int64(1), "Alice"))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		got, err := repo.FindPage(context.Background(), PageRequest{
+			PageSize: 10, PageNumber: 2, Sort: "name:desc", CreatedFrom: &from, Ids: []int64{1, 2},
+		})
+		a.NoError(err)
+		a.Len(got, 2)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should order by id by default and reuse text filter parameter", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT \* FROM employee WHERE deleted_at IS NULL AND \(name ilike \$1 OR email ilike \$1 .*\)\s+ORDER BY id ASC LIMIT \$2 OFFSET \$3`).
+			WithArgs("%john%", 20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		_, err = repo.FindPage(context.Background(), PageRequest{PageSize: 20, TextFilter: "john"})
+		a.NoError(err)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+
+	t.Run("should include deleted rows on request", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT \* FROM employee\s+ORDER BY id ASC LIMIT \$1 OFFSET \$2`).
+			WithArgs(20, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		_, err = repo.FindPage(context.Background(), PageRequest{PageSize: 20, IncludeDeleted: true})
+		a.NoError(err)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_FindAfter(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should seek by id instead of offset", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT \* FROM employee WHERE deleted_at IS NULL AND id > \$1 ORDER BY id LIMIT \$2`).
+			WithArgs(int64(500), 101).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(501), "John Doe"))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		got, err := repo.FindAfter(context.Background(), PageRequest{}, 500, 101)
+		a.NoError(err)
+		a.Len(got, 1)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Search(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should lower similarity threshold within read-only transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT set_config\('pg_trgm.word_similarity_threshold', \$1, true\)`).
+			WithArgs(searchSimilarityThreshold).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`FROM employee, to_tsquery\('simple', \$1\) AS q\s+WHERE deleted_at IS NULL AND .+ @@ q OR \$2 <% name\)\s+ORDER BY rank DESC, id LIMIT \$3`).
+			WithArgs("ivan:*", "ivna", 20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "rank", "highlight"}).AddRow(int64(7), "Ivan Petrov", 0.4, "Ivan Petrov"))
+		mock.ExpectCommit()
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		got, err := repo.Search(context.Background(), "ivan:*", "ivna", 20)
+		a.NoError(err)
+		a.Len(got, 1)
+		a.Equal("Ivan Petrov", got[0].Name)
+		a.Equal(0.4, got[0].Rank)
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should not write hire and termination dates", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		now := time.Now()
+		terminationDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`UPDATE employee SET name = \$1, updated_at = \$2, version = version \+ 1,\s+email = \$5, login = \$6, employee_number = \$7, first_name = \$8, last_name = \$9, job_title = \$10,\s+phone = \$11\s+WHERE id = \$3`).
+			WithArgs("Jane Doe", now, int64(1), int64(0), "", "", "", "", "", "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "version", "status", "org_unit_id", "manager_id", "hire_date", "termination_date"}).
+				AddRow(now, int64(4), StatusTerminated, nil, nil, nil, terminationDate))
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		e := &Entity{Id: 1, Name: "Jane Doe", UpdatedAt: now}
+		a.NoError(repo.Update(context.Background(), e))
+		a.Equal(StatusTerminated, e.Status)
+		a.True(terminationDate.Equal(*e.TerminationDate))
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_ImportMethods(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should lock employee by name and update within transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		a.NoError(err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT \* FROM employee WHERE lower\(name\) = lower\(\$1\) FOR UPDATE`).
+			WithArgs("ivan petrov").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(int64(5), "Ivan Petrov", int64(2)))
+		mock.ExpectQuery(`SELECT id FROM employee WHERE lower\(login\) = lower\(\$1\) AND login <> ''`).
+			WithArgs("ipetrov").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(`UPDATE employee SET name = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "version", "status", "org_unit_id", "manager_id", "hire_date", "termination_date"}).
+				AddRow(time.Now(), int64(3), StatusActive, nil, nil, nil, nil))
+		mock.ExpectCommit()
+
+		repo := NewRepository(sqlx.NewDb(db, "sqlmock"))
+		ctx := context.Background()
+		tx, err := repo.BeginTransaction(ctx)
+		a.NoError(err)
+
+		e, err := repo.FindByNameForUpdateTx(ctx, tx, "ivan petrov")
+		a.NoError(err)
+		a.Equal(int64(5), e.Id)
+
+		ownerId, err := repo.FindIdByLoginTx(ctx, tx, "ipetrov")
+		a.NoError(err)
+		a.Zero(ownerId)
+
+		a.NoError(repo.UpdateTx(ctx, tx, &e))
+		a.Equal(int64(3), e.Version)
+		a.NoError(tx.Commit())
+		a.NoError(mock.ExpectationsWereMet())
+	})
+}
@@ -1,11 +1,103 @@
 package employee
 
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Profile содержит анкетные данные сотрудника, общие для запросов создания и изменения.
+// Все поля необязательные; логин, если указан, должен быть уникальным без учета регистра.
+// Даты приема и увольнения в анкету не входят: их устанавливают переходы Activate и Terminate
+type Profile struct {
+	Email          string `json:"email" validate:"omitempty,email,max=254"`
+	Login          string `json:"login" validate:"omitempty,min=2,max=64,excludesall= "`
+	EmployeeNumber string `json:"employee_number" validate:"omitempty,max=32"`
+	FirstName      string `json:"first_name" validate:"omitempty,max=100"`
+	LastName       string `json:"last_name" validate:"omitempty,max=100"`
+	JobTitle       string `json:"job_title" validate:"omitempty,max=200"`
+	Phone          string `json:"phone" validate:"omitempty,e164"`
+}
+
+// validateDates проверяет, что дата увольнения не раньше даты приема на работу
+func validateDates(hireDate, terminationDate *time.Time) error {
+	if hireDate != nil && terminationDate != nil && terminationDate.Before(*hireDate) {
+		return common.RequestValidationError{Message: "termination_date must not be before hire_date"}
+	}
+	return nil
+}
+
+// applyTo переносит анкетные данные в сущность сотрудника
+func (p Profile) applyTo(e *Entity) {
+	e.Email = p.Email
+	e.Login = p.Login
+	e.EmployeeNumber = p.EmployeeNumber
+	e.FirstName = p.FirstName
+	e.LastName = p.LastName
+	e.JobTitle = p.JobTitle
+	e.Phone = p.Phone
+}
+
+// applyFields переносит в сущность сотрудника только перечисленные поля анкеты (названия полей JSON);
+// остальные анкетные данные сотрудника не меняются
+func (p Profile) applyFields(e *Entity, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "email":
+			e.Email = p.Email
+		case "login":
+			e.Login = p.Login
+		case "employee_number":
+			e.EmployeeNumber = p.EmployeeNumber
+		case "first_name":
+			e.FirstName = p.FirstName
+		case "last_name":
+			e.LastName = p.LastName
+		case "job_title":
+			e.JobTitle = p.JobTitle
+		case "phone":
+			e.Phone = p.Phone
+		}
+	}
+}
+
 type AddEmployeeRequest struct {
 	Name string `json:"name" validate:"required,min=2,max=100"`
+	Profile
+	// Status - начальный статус сотрудника, по умолчанию active; остальные статусы достигаются переходами
+	Status string `json:"status" validate:"omitempty,oneof=pre_hire active"`
+	// HireDate - дата приема на работу; задается только при создании, например плановая дата выхода для pre_hire
+	HireDate *time.Time `json:"hire_date"`
 }
 
 func (req *AddEmployeeRequest) ToEntity() Entity {
-	return Entity{Name: req.Name}
+	e := Entity{Name: req.Name, Status: req.Status, HireDate: req.HireDate}
+	if e.Status == "" {
+		e.Status = StatusActive
+	}
+	req.Profile.applyTo(&e)
+	return e
+}
+
+type UpdateEmployeeRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	Profile
+}
+
+// TerminateRequest - запрос на увольнение сотрудника
+type TerminateRequest struct {
+	// TerminationDate - дата увольнения, по умолчанию текущая дата
+	TerminationDate *time.Time `json:"termination_date"`
 }
 
 type FindByIdRequest struct {
@@ -24,10 +116,272 @@ type DeleteByIdsRequest struct {
 	Ids []int64 `json:"ids" validate:"required,min=1,dive,gt=0"`
 }
 
-// PageRequest используется для пагинации и фильтрации сотрудников
-// textFilter — фильтр по имени (минимум 3 непробельных символа)
+// PageRequest используется для пагинации, фильтрации и сортировки сотрудников
+// textFilter — фильтр по имени и анкетным данным (минимум 3 непробельных символа);
+// sort — поле сортировки и направление в виде "field" или "field:desc", при равенстве значений порядок определяется id;
+// диапазоны дат создания и изменения включают начало и не включают конец; ids ограничивает выборку списком ID;
+// includeDeleted добавляет в выборку удаленных сотрудников
 type PageRequest struct {
-	PageSize   int    `json:"pageSize" validate:"min=1,max=100"`
-	PageNumber int    `json:"pageNumber" validate:"min=0"`
-	TextFilter string `json:"textFilter"`
+	PageSize       int        `json:"pageSize" validate:"min=1,max=100"`
+	PageNumber     int        `json:"pageNumber" validate:"min=0"`
+	TextFilter     string     `json:"textFilter"`
+	Sort           string     `json:"sort"`
+	CreatedFrom    *time.Time `json:"createdFrom"`
+	CreatedTo      *time.Time `json:"createdTo"`
+	UpdatedFrom    *time.Time `json:"updatedFrom"`
+	UpdatedTo      *time.Time `json:"updatedTo"`
+	Ids            []int64    `json:"ids" validate:"omitempty,max=100,dive,gt=0"`
+	IncludeDeleted bool       `json:"includeDeleted"`
+}
+
+// CursorRequest используется для последовательного обхода сотрудников по курсору (keyset-пагинация).
+// Сотрудники возвращаются по возрастанию id начиная после записи, на которую указывает after;
+// фильтры те же, что и у PageRequest, сортировка не поддерживается
+type CursorRequest struct {
+	Limit          int        `json:"limit" validate:"min=1,max=1000"`
+	After          string     `json:"after"`
+	TextFilter     string     `json:"textFilter"`
+	CreatedFrom    *time.Time `json:"createdFrom"`
+	CreatedTo      *time.Time `json:"createdTo"`
+	UpdatedFrom    *time.Time `json:"updatedFrom"`
+	UpdatedTo      *time.Time `json:"updatedTo"`
+	Ids            []int64    `json:"ids" validate:"omitempty,max=100,dive,gt=0"`
+	IncludeDeleted bool       `json:"includeDeleted"`
+}
+
+// filter возвращает фильтры обхода в виде PageRequest, чтобы использовать общие условия отбора
+func (req CursorRequest) filter() PageRequest {
+	return PageRequest{
+		TextFilter:     req.TextFilter,
+		CreatedFrom:    req.CreatedFrom,
+		CreatedTo:      req.CreatedTo,
+		UpdatedFrom:    req.UpdatedFrom,
+		UpdatedTo:      req.UpdatedTo,
+		Ids:            req.Ids,
+		IncludeDeleted: req.IncludeDeleted,
+	}
+}
+
+// sortColumns сопоставляет допустимые поля сортировки с выражениями SQL
+var sortColumns = map[string]string{
+	"id":              "id",
+	"name":            "lower(name)",
+	"email":           "lower(email)",
+	"login":           "lower(login)",
+	"employee_number": "employee_number",
+	"last_name":       "lower(last_name)",
+	"job_title":       "lower(job_title)",
+	"status":          "status",
+	"hire_date":       "hire_date",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
+}
+
+// sortOrder разбирает параметр sort и возвращает выражение и направление сортировки.
+// Без параметра сотрудники сортируются по id по возрастанию
+func (req PageRequest) sortOrder() (column, direction string, err error) {
+	if req.Sort == "" {
+		return "id", "ASC", nil
+	}
+
+	field, order, _ := strings.Cut(req.Sort, ":")
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", "", common.RequestValidationError{Message: fmt.Sprintf("invalid sort field '%s'", field)}
+	}
+	switch strings.ToLower(order) {
+	case "", "asc":
+		return column, "ASC", nil
+	case "desc":
+		return column, "DESC", nil
+	default:
+		return "", "", common.RequestValidationError{Message: fmt.Sprintf("invalid sort direction '%s': expected asc or desc", order)}
+	}
+}
+
+// validateRanges проверяет, что начало каждого диапазона дат не позже его конца
+func (req PageRequest) validateRanges() error {
+	if req.CreatedFrom != nil && req.CreatedTo != nil && req.CreatedFrom.After(*req.CreatedTo) {
+		return common.RequestValidationError{Message: "createdFrom must not be after createdTo"}
+	}
+	if req.UpdatedFrom != nil && req.UpdatedTo != nil && req.UpdatedFrom.After(*req.UpdatedTo) {
+		return common.RequestValidationError{Message: "updatedFrom must not be after updatedTo"}
+	}
+	return nil
+}
+
+// PurgeDeletedRequest используется для окончательного удаления сотрудников,
+// удаленных (мягко) раньше чем older_than_days дней назад
+type PurgeDeletedRequest struct {
+	OlderThanDays int `json:"older_than_days" validate:"required,min=1"`
+}
+
+// SearchRequest используется для поиска сотрудников по имени и анкетным данным.
+// Слова запроса ищутся по началу слов (полнотекстовый поиск), а имя дополнительно сравнивается
+// по сходству написания, поэтому находятся и неполные, и написанные с опечатками имена
+type SearchRequest struct {
+	Query string `json:"q" validate:"required,max=200"`
+	Limit int    `json:"limit" validate:"min=1,max=100"`
+}
+
+// tsQuery преобразует текст запроса в префиксный tsquery: "ivan pet" -> "ivan:* & pet:*".
+// Знаки препинания и операторы tsquery отбрасываются, поэтому пользовательский ввод не может сломать запрос
+func (req SearchRequest) tsQuery() (string, error) {
+	words := strings.FieldsFunc(strings.ToLower(req.Query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", common.RequestValidationError{Message: "q must contain letters or digits"}
+	}
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & "), nil
+}
+
+// maxImportRows - максимальное количество сотрудников в одном файле импорта
+const maxImportRows = 10000
+
+// maxImportLineSize - максимальная длина строки NDJSON-файла импорта в байтах
+const maxImportLineSize = 64 * 1024
+
+// ImportRow - строка файла импорта: номер строки в исходном файле и данные сотрудника.
+// Fields перечисляет поля, заданные в строке: при обновлении существующего сотрудника меняются только они.
+// Err содержит ошибку разбора строки; такая строка попадает в отчет импорта как ошибочная
+type ImportRow struct {
+	Line    int
+	Request AddEmployeeRequest
+	Fields  []string
+	Err     error
+}
+
+// importColumns сопоставляет столбцы CSV-файла импорта с полями запроса; названия столбцов совпадают с полями JSON
+var importColumns = map[string]func(req *AddEmployeeRequest, value string) error{
+	"name":            func(req *AddEmployeeRequest, v string) error { req.Name = v; return nil },
+	"email":           func(req *AddEmployeeRequest, v string) error { req.Email = v; return nil },
+	"login":           func(req *AddEmployeeRequest, v string) error { req.Login = v; return nil },
+	"employee_number": func(req *AddEmployeeRequest, v string) error { req.EmployeeNumber = v; return nil },
+	"first_name":      func(req *AddEmployeeRequest, v string) error { req.FirstName = v; return nil },
+	"last_name":       func(req *AddEmployeeRequest, v string) error { req.LastName = v; return nil },
+	"job_title":       func(req *AddEmployeeRequest, v string) error { req.JobTitle = v; return nil },
+	"phone":           func(req *AddEmployeeRequest, v string) error { req.Phone = v; return nil },
+	"status":          func(req *AddEmployeeRequest, v string) error { req.Status = v; return nil },
+	"hire_date": func(req *AddEmployeeRequest, v string) (err error) {
+		req.HireDate, err = parseDate("hire_date", v)
+		return err
+	},
+}
+
+// parseDate разбирает дату из CSV в формате YYYY-MM-DD или RFC3339; пустое значение означает отсутствие даты
+func parseDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s: expected YYYY-MM-DD", field)
+}
+
+// parseCSV разбирает CSV-файл импорта. Первая строка - заголовок с названиями столбцов из importColumns,
+// столбец name обязателен; пустые ячейки в Fields строки не попадают. Ошибки в отдельных строках не прерывают разбор, а ошибки формата файла возвращаются сразу
+func parseCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, common.RequestValidationError{Message: "import file is empty"}
+	}
+	if err != nil {
+		return nil, common.RequestValidationError{Message: "invalid CSV header: " + err.Error()}
+	}
+
+	columns := make([]string, len(header))
+	setters := make([]func(req *AddEmployeeRequest, value string) error, len(header))
+	hasName := false
+	for i, column := range header {
+		// Excel сохраняет CSV в UTF-8 с BOM в начале файла
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		setter, ok := importColumns[column]
+		if !ok {
+			return nil, common.RequestValidationError{Message: fmt.Sprintf("unknown CSV column %q", column)}
+		}
+		columns[i], setters[i] = column, setter
+		hasName = hasName || column == "name"
+	}
+	if !hasName {
+		return nil, common.RequestValidationError{Message: "CSV header must contain name column"}
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, common.RequestValidationError{Message: "invalid CSV: " + err.Error()}
+		}
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{Line: line}
+		if err != nil {
+			row.Err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+		} else {
+			for i, value := range record {
+				// пустая ячейка означает, что значение поля не задано, и не стирает данные существующего сотрудника
+				if value = strings.TrimSpace(value); value == "" {
+					continue
+				}
+				if row.Err = setters[i](&row.Request, value); row.Err != nil {
+					break
+				}
+				row.Fields = append(row.Fields, columns[i])
+			}
+		}
+		if rows = append(rows, row); len(rows) > maxImportRows {
+			return nil, common.RequestValidationError{Message: fmt.Sprintf("import file must not contain more than %d rows", maxImportRows)}
+		}
+	}
+	if len(rows) == 0 {
+		return nil, common.RequestValidationError{Message: "import file is empty"}
+	}
+	return rows, nil
+}
+
+// parseNDJSON разбирает NDJSON-файл импорта: по одному объекту AddEmployeeRequest в строке, пустые строки пропускаются.
+// Fields строки - ключи ее объекта, поэтому отсутствующий ключ не меняет данные существующего сотрудника.
+// Неизвестные поля считаются ошибкой строки, чтобы опечатка в названии поля не приводила к потере данных
+func parseNDJSON(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxImportLineSize)
+	var rows []ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		row := ImportRow{Line: line}
+		var fields map[string]json.RawMessage
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := json.Unmarshal(text, &fields); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+		} else if err := decoder.Decode(&row.Request); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+		} else {
+			row.Fields = slices.Sorted(maps.Keys(fields))
+		}
+		if rows = append(rows, row); len(rows) > maxImportRows {
+			return nil, common.RequestValidationError{Message: fmt.Sprintf("import file must not contain more than %d rows", maxImportRows)}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, common.RequestValidationError{Message: "invalid NDJSON: " + err.Error()}
+	}
+	if len(rows) == 0 {
+		return nil, common.RequestValidationError{Message: "import file is empty"}
+	}
+	return rows, nil
 }
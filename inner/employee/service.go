@@ -2,8 +2,12 @@ package employee
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"idm/inner/common"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -30,16 +34,28 @@ type Service struct {
 // Repo интерфейс репозитория для сотрудников
 type Repo interface {
 	FindById(ctx context.Context, id int64) (Entity, error)
+	FindByIdWithDeleted(ctx context.Context, id int64) (Entity, error)
 	Add(ctx context.Context, e *Entity) error
 	FindAll(ctx context.Context) ([]Entity, error)
 	FindByIds(ctx context.Context, ids []int64) ([]Entity, error)
+	Update(ctx context.Context, e *Entity) error
 	DeleteById(ctx context.Context, id int64) error
-	DeleteByIds(ctx context.Context, ids []int64) error
+	DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error)
+	DeleteByIds(ctx context.Context, ids []int64) ([]int64, error)
+	FindAllWithDeleted(ctx context.Context) ([]Entity, error)
+	Restore(ctx context.Context, id int64, at time.Time) (Entity, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	ChangeStatus(ctx context.Context, e *Entity, from string) error
 	BeginTransaction(ctx context.Context) (Transaction, error)
 	FindByNameTx(ctx context.Context, tx Transaction, name string) (bool, error)
 	AddTx(ctx context.Context, tx Transaction, e *Entity) error
-	FindPage(ctx context.Context, limit, offset int, textFilter string) ([]Entity, error)
-	CountAll(ctx context.Context, textFilter string) (int64, error)
+	FindByNameForUpdateTx(ctx context.Context, tx Transaction, name string) (Entity, error)
+	FindIdByLoginTx(ctx context.Context, tx Transaction, login string) (int64, error)
+	UpdateTx(ctx context.Context, tx Transaction, e *Entity) error
+	FindPage(ctx context.Context, req PageRequest) ([]Entity, error)
+	CountAll(ctx context.Context, req PageRequest) (int64, error)
+	FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error)
+	Search(ctx context.Context, tsQuery, text string, limit int) ([]SearchEntity, error)
 }
 
 type Validator interface {
@@ -62,17 +78,83 @@ type PageResponse struct {
 	Total      int64      `json:"total"`
 }
 
-// FindPage возвращает страницу сотрудников с учетом пагинации
+// CursorResponse представляет порцию сотрудников при обходе по курсору;
+// NextCursor равен null, если сотрудников больше нет
+type CursorResponse struct {
+	Result     []Response `json:"result"`
+	Limit      int        `json:"limit"`
+	NextCursor *string    `json:"next_cursor"`
+}
+
+// FindAfter возвращает следующую порцию сотрудников после курсора и курсор для продолжения обхода
+func (svc *Service) FindAfter(ctx context.Context, req CursorRequest) (CursorResponse, error) {
+	if err := svc.validator.ValidateWithCustomMessages(req); err != nil {
+		return CursorResponse{}, common.RequestValidationError{Message: err.Error()}
+	}
+	filter := req.filter()
+	if err := filter.validateRanges(); err != nil {
+		return CursorResponse{}, err
+	}
+	afterId, err := common.DecodeCursor(req.After)
+	if err != nil {
+		return CursorResponse{}, err
+	}
+
+	// запрашиваем на одну запись больше, чтобы узнать, есть ли продолжение
+	entities, err := svc.repo.FindAfter(ctx, filter, afterId, req.Limit+1)
+	if err != nil {
+		return CursorResponse{}, err
+	}
+	response := CursorResponse{Limit: req.Limit}
+	if len(entities) > req.Limit {
+		entities = entities[:req.Limit]
+		next := common.EncodeCursor(entities[len(entities)-1].Id)
+		response.NextCursor = &next
+	}
+	response.Result = make([]Response, len(entities))
+	for i, e := range entities {
+		response.Result[i] = e.toResponse()
+	}
+	return response, nil
+}
+
+// Search ищет сотрудников по неполному или написанному с ошибками имени и анкетным данным;
+// результаты упорядочены по убыванию релевантности
+func (svc *Service) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	if err := svc.validator.ValidateWithCustomMessages(req); err != nil {
+		return nil, common.RequestValidationError{Message: err.Error()}
+	}
+	tsQuery, err := req.tsQuery()
+	if err != nil {
+		return nil, err
+	}
+	entities, err := svc.repo.Search(ctx, tsQuery, req.Query, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SearchResult, len(entities))
+	for i, e := range entities {
+		results[i] = SearchResult{Response: e.toResponse(), Rank: e.Rank, Highlight: e.Highlight}
+	}
+	return results, nil
+}
+
+// FindPage возвращает страницу сотрудников с учетом пагинации, фильтров и сортировки
 func (svc *Service) FindPage(ctx context.Context, req PageRequest) (PageResponse, error) {
 	if err := svc.validator.ValidateWithCustomMessages(req); err != nil {
 		return PageResponse{}, common.RequestValidationError{Message: err.Error()}
 	}
-	offset := req.PageNumber * req.PageSize
-	entities, err := svc.repo.FindPage(ctx, req.PageSize, offset, req.TextFilter)
+	if _, _, err := req.sortOrder(); err != nil {
+		return PageResponse{}, err
+	}
+	if err := req.validateRanges(); err != nil {
+		return PageResponse{}, err
+	}
+	entities, err := svc.repo.FindPage(ctx, req)
 	if err != nil {
 		return PageResponse{}, err
 	}
-	total, err := svc.repo.CountAll(ctx, req.TextFilter)
+	total, err := svc.repo.CountAll(ctx, req)
 	if err != nil {
 		return PageResponse{}, err
 	}
@@ -138,18 +220,21 @@ func (svc *Service) AddTransactional(ctx context.Context, request AddEmployeeReq
 	}
 
 	if exists {
-		return Response{}, common.AlreadyExistsError{Message: fmt.Sprintf("employee with name '%s' already exists", request.Name)}
+		return Response{}, nameTakenError(request.Name)
 	}
 
 	// Создаем нового сотрудника
 	now := time.Now()
-	entity := &Entity{
-		Name:      request.Name,
-		CreatedAt: now,
-		UpdatedAt: now,
+	entity := request.ToEntity()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	entity.Version = 1
+
+	err = svc.repo.AddTx(ctx, tx, &entity)
+	// проверка выше не защищает от параллельного создания, окончательно уникальность имени гарантирует индекс
+	if constraint, ok := common.UniqueViolationConstraint(err); ok {
+		return Response{}, uniqueViolationError(constraint, request.Name, request.Login)
 	}
-
-	err = svc.repo.AddTx(ctx, tx, entity)
 	if err != nil {
 		// Возвращаем ошибку с нужным текстом для теста
 		return Response{}, fmt.Errorf("error adding employee: %w", err)
@@ -158,6 +243,161 @@ func (svc *Service) AddTransactional(ctx context.Context, request AddEmployeeReq
 	return entity.toResponse(), nil
 }
 
+// Import создает и обновляет сотрудников из строк файла импорта в одной транзакции.
+// Строка сопоставляется с сотрудником по имени без учета регистра (имя уникально в справочнике):
+// найденный сотрудник обновляется, иначе создается новый; статус из файла применяется только к новым сотрудникам.
+// Если хотя бы одна строка содержит ошибку или конфликт, изменения не сохраняются, а отчет возвращается
+// с Applied = false. При dryRun транзакция всегда откатывается, а отчет показывает, что было бы сделано
+func (svc *Service) Import(ctx context.Context, rows []ImportRow, dryRun bool) (report ImportReport, err error) {
+	if len(rows) == 0 {
+		return ImportReport{}, common.RequestValidationError{Message: "import file is empty"}
+	}
+
+	report = ImportReport{DryRun: dryRun, Rows: make([]ImportRowResult, len(rows))}
+	names := make(map[string]int, len(rows))
+	logins := make(map[string]int, len(rows))
+	for i, row := range rows {
+		report.Rows[i] = svc.checkImportRow(row, names, logins)
+	}
+
+	tx, err := svc.repo.BeginTransaction(ctx)
+	if err != nil {
+		return ImportReport{}, common.TransactionError{Message: "error creating transaction", Err: err}
+	}
+	defer func() {
+		if err != nil || !report.Applied {
+			if errTx := tx.Rollback(); errTx != nil {
+				err = errors.Join(err, common.TransactionError{Message: "error rolling back import", Err: errTx})
+			}
+			return
+		}
+		if errTx := tx.Commit(); errTx != nil {
+			report, err = ImportReport{}, common.TransactionError{Message: "error committing import", Err: errTx}
+		}
+	}()
+
+	// сотрудники блокируются до конца транзакции, поэтому отчет dryRun не устаревает к моменту записи
+	entities := make([]Entity, len(rows))
+	for i, row := range rows {
+		if report.Rows[i].Action != "" {
+			continue
+		}
+		entities[i], err = svc.planImportRow(ctx, tx, row, &report.Rows[i])
+		if err != nil {
+			return ImportReport{}, err
+		}
+	}
+
+	for _, result := range report.Rows {
+		switch result.Action {
+		case ImportCreate:
+			report.Created++
+		case ImportUpdate:
+			report.Updated++
+		case ImportConflict:
+			report.Conflicts++
+		case ImportError:
+			report.Errors++
+		}
+	}
+	if dryRun || report.Conflicts > 0 || report.Errors > 0 {
+		return report, nil
+	}
+
+	now := time.Now()
+	for i := range entities {
+		e := &entities[i]
+		e.UpdatedAt = now
+		switch report.Rows[i].Action {
+		case ImportCreate:
+			e.CreatedAt = now
+			e.Version = 1
+			err = svc.repo.AddTx(ctx, tx, e)
+		case ImportUpdate:
+			err = svc.repo.UpdateTx(ctx, tx, e)
+		}
+		// проверки выше не защищают от параллельной записи, окончательно уникальность гарантируют индексы
+		if constraint, ok := common.UniqueViolationConstraint(err); ok {
+			return ImportReport{}, uniqueViolationError(constraint, e.Name, e.Login)
+		}
+		if err != nil {
+			return ImportReport{}, fmt.Errorf("error importing employee from line %d: %w", rows[i].Line, err)
+		}
+		report.Rows[i].Id = e.Id
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// checkImportRow проверяет строку импорта без обращения к базе данных: ошибки разбора и валидации,
+// а также повторы имени и логина внутри файла. Для корректной строки возвращает результат без действия
+func (svc *Service) checkImportRow(row ImportRow, names, logins map[string]int) ImportRowResult {
+	result := ImportRowResult{Line: row.Line, Name: row.Request.Name}
+	fail := func(action string, err error) ImportRowResult {
+		result.Action, result.Error = action, err.Error()
+		return result
+	}
+
+	if row.Err != nil {
+		return fail(ImportError, row.Err)
+	}
+	if err := svc.validator.ValidateWithCustomMessages(row.Request); err != nil {
+		return fail(ImportError, err)
+	}
+
+	name := strings.ToLower(row.Request.Name)
+	if line, ok := names[name]; ok {
+		return fail(ImportConflict, fmt.Errorf("name %q is already used on line %d", row.Request.Name, line))
+	}
+	names[name] = row.Line
+	if row.Request.Login != "" {
+		login := strings.ToLower(row.Request.Login)
+		if line, ok := logins[login]; ok {
+			return fail(ImportConflict, fmt.Errorf("login %q is already used on line %d", row.Request.Login, line))
+		}
+		logins[login] = row.Line
+	}
+	return result
+}
+
+// planImportRow определяет, создать или обновить сотрудника из строки импорта, и записывает решение в result.
+// Возвращает сущность, которую нужно сохранить; конфликты с существующими сотрудниками отражаются в result.
+// Существующему сотруднику переносятся только поля, заданные в строке; дата приема применяется только при создании
+func (svc *Service) planImportRow(ctx context.Context, tx Transaction, row ImportRow, result *ImportRowResult) (Entity, error) {
+	request := row.Request
+	existing, err := svc.repo.FindByNameForUpdateTx(ctx, tx, request.Name)
+	found := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Entity{}, common.RepositoryError{Message: fmt.Sprintf("error finding employee %q", request.Name), Err: err}
+	}
+	if found && existing.DeletedAt != nil {
+		result.Action, result.Id = ImportConflict, existing.Id
+		result.Error = "employee is deleted, restore it before import"
+		return Entity{}, nil
+	}
+
+	if request.Login != "" {
+		ownerId, err := svc.repo.FindIdByLoginTx(ctx, tx, request.Login)
+		if err != nil {
+			return Entity{}, common.RepositoryError{Message: fmt.Sprintf("error checking login %q", request.Login), Err: err}
+		}
+		if ownerId != 0 && (!found || ownerId != existing.Id) {
+			result.Action = ImportConflict
+			result.Error = fmt.Sprintf("login %q is already taken by employee %d", request.Login, ownerId)
+			return Entity{}, nil
+		}
+	}
+
+	if !found {
+		result.Action = ImportCreate
+		return request.ToEntity(), nil
+	}
+	result.Action, result.Id = ImportUpdate, existing.Id
+	existing.Name = request.Name
+	request.Profile.applyFields(&existing, row.Fields)
+	return existing, nil
+}
+
 // NewService функция-конструктор для Service
 func NewService(repo Repo, validator Validator) *Service {
 	return &Service{
@@ -168,11 +408,24 @@ func NewService(repo Repo, validator Validator) *Service {
 
 // FindById возвращает сотрудника по ID
 func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
+	return svc.findById(ctx, id, svc.repo.FindById)
+}
+
+// FindByIdWithDeleted возвращает сотрудника по ID, в том числе удаленного
+func (svc *Service) FindByIdWithDeleted(ctx context.Context, id int64) (Response, error) {
+	return svc.findById(ctx, id, svc.repo.FindByIdWithDeleted)
+}
+
+// findById проверяет ID и ищет сотрудника переданной функцией репозитория
+func (svc *Service) findById(ctx context.Context, id int64, find func(ctx context.Context, id int64) (Entity, error)) (Response, error) {
 	if id <= 0 {
 		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
 	}
 
-	entity, err := svc.repo.FindById(ctx, id)
+	entity, err := find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
 	if err != nil {
 		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", id), Err: err}
 	}
@@ -181,21 +434,22 @@ func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
 }
 
 // Add добавляет нового сотрудника
-func (svc *Service) Add(ctx context.Context, name string) (Response, error) {
-	req := AddEmployeeRequest{Name: name}
-	err := svc.validator.ValidateWithCustomMessages(req)
+func (svc *Service) Add(ctx context.Context, request AddEmployeeRequest) (Response, error) {
+	err := svc.validator.ValidateWithCustomMessages(request)
 	if err != nil {
 		return Response{}, common.RequestValidationError{Message: err.Error()}
 	}
 
 	now := time.Now()
-	entity := &Entity{
-		Name:      name,
-		CreatedAt: now,
-		UpdatedAt: now,
+	entity := request.ToEntity()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	entity.Version = 1
+
+	err = svc.repo.Add(ctx, &entity)
+	if constraint, ok := common.UniqueViolationConstraint(err); ok {
+		return Response{}, uniqueViolationError(constraint, request.Name, request.Login)
 	}
-
-	err = svc.repo.Add(ctx, entity)
 	if err != nil {
 		return Response{}, fmt.Errorf("error adding employee: %w", err)
 	}
@@ -218,6 +472,20 @@ func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
 	return responses, nil
 }
 
+// FindAllWithDeleted возвращает всех сотрудников, включая удаленных
+func (svc *Service) FindAllWithDeleted(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAllWithDeleted(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding all employees including deleted", Err: err}
+	}
+
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
 // FindByIds возвращает сотрудников по списку ID
 func (svc *Service) FindByIds(ctx context.Context, ids []int64) ([]Response, error) {
 	// Validate the input before proceeding
@@ -238,6 +506,38 @@ func (svc *Service) FindByIds(ctx context.Context, ids []int64) ([]Response, err
 	return responses, nil
 }
 
+// Update обновляет имя и анкетные данные сотрудника; статус и даты приема и увольнения не меняются.
+// Если version больше 0, обновление выполняется только для этой версии записи (If-Match)
+func (svc *Service) Update(ctx context.Context, id int64, request UpdateEmployeeRequest, version int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+
+	entity := &Entity{
+		Id:        id,
+		Name:      request.Name,
+		UpdatedAt: time.Now(),
+		Version:   version,
+	}
+	request.Profile.applyTo(entity)
+
+	err := svc.repo.Update(ctx, entity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, svc.versionError(ctx, id, version)
+	}
+	if constraint, ok := common.UniqueViolationConstraint(err); ok {
+		return Response{}, uniqueViolationError(constraint, request.Name, request.Login)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error updating employee with id %d", id), Err: err}
+	}
+
+	return entity.toResponse(), nil
+}
+
 // DeleteById удаляет сотрудника по ID
 func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 	if id <= 0 {
@@ -245,6 +545,9 @@ func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 	}
 
 	err := svc.repo.DeleteById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
 	if err != nil {
 		return fmt.Errorf("error deleting employee with id %d: %w", id, err)
 	}
@@ -252,12 +555,186 @@ func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 	return nil
 }
 
-// DeleteByIds удаляет сотрудников по списку ID
+// DeleteByIdIfMatch удаляет сотрудника по ID только при совпадении версии записи (If-Match)
+func (svc *Service) DeleteByIdIfMatch(ctx context.Context, id, version int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
+	}
+
+	deleted, err := svc.repo.DeleteByIdAndVersion(ctx, id, version)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error deleting employee with id %d", id), Err: err}
+	}
+	if deleted == 0 {
+		return svc.versionError(ctx, id, version)
+	}
+
+	return nil
+}
+
+// versionError определяет причину, по которой запись не была изменена:
+// сотрудник отсутствует (NotFoundError) или его версия не совпала с ожидаемой (PreconditionFailedError)
+func (svc *Service) versionError(ctx context.Context, id, version int64) error {
+	if version <= 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
+
+	current, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", id), Err: err}
+	}
+	return common.PreconditionFailedError{
+		Message: fmt.Sprintf("employee with id %d has been modified: expected version %d, current version %d", id, version, current.Version),
+	}
+}
+
+// DeleteByIds удаляет сотрудников по списку ID.
+// Если хотя бы один сотрудник не найден, не удаляется ни один, а ошибка перечисляет отсутствующие ID
 func (svc *Service) DeleteByIds(ctx context.Context, ids []int64) error {
-	err := svc.repo.DeleteByIds(ctx, ids)
+	deleted, err := svc.repo.DeleteByIds(ctx, ids)
 	if err != nil {
 		return fmt.Errorf("error deleting employees by ids: %w", err)
 	}
+	if missing := missingIds(ids, deleted); len(missing) > 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("employees not found: %v; no employees were deleted", missing)}
+	}
 
 	return nil
 }
+
+// nameTakenError возвращает ошибку о том, что имя уже занято другим сотрудником
+func nameTakenError(name string) error {
+	return common.AlreadyExistsError{Message: fmt.Sprintf("employee with name '%s' already exists", name)}
+}
+
+// uniqueViolationError по имени нарушенного индекса определяет, что уже занято: логин или имя
+func uniqueViolationError(constraint, name, login string) error {
+	if constraint == loginUniqueIndex {
+		return common.AlreadyExistsError{Message: fmt.Sprintf("employee with login '%s' already exists", login)}
+	}
+	return nameTakenError(name)
+}
+
+// missingIds возвращает ID из requested, которых нет среди found
+func missingIds(requested, found []int64) []int64 {
+	var missing []int64
+	for _, id := range requested {
+		if !slices.Contains(found, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// Restore восстанавливает удаленного сотрудника
+func (svc *Service) Restore(ctx context.Context, id int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
+	}
+
+	entity, err := svc.repo.Restore(ctx, id, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("deleted employee with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error restoring employee with id %d", id), Err: err}
+	}
+
+	return entity.toResponse(), nil
+}
+
+// PurgeDeleted окончательно удаляет сотрудников, удаленных раньше чем request.OlderThanDays дней назад
+func (svc *Service) PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return PurgeResponse{}, err
+	}
+
+	before := time.Now().AddDate(0, 0, -request.OlderThanDays)
+	purged, err := svc.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return PurgeResponse{}, common.RepositoryError{Message: "error purging deleted employees", Err: err}
+	}
+
+	return PurgeResponse{Purged: purged}, nil
+}
+
+// Activate выводит сотрудника на работу: первый рабочий день после оформления или возврат после приостановки.
+// При первом выходе на работу без даты приема датой приема становится текущая дата
+func (svc *Service) Activate(ctx context.Context, id int64) (Response, error) {
+	return svc.changeStatus(ctx, id, StatusActive, func(e *Entity) error {
+		if e.HireDate == nil {
+			today := today()
+			e.HireDate = &today
+		}
+		return nil
+	})
+}
+
+// Suspend приостанавливает работу сотрудника (отпуск, расследование); роли сотрудника сохраняются
+func (svc *Service) Suspend(ctx context.Context, id int64) (Response, error) {
+	return svc.changeStatus(ctx, id, StatusSuspended, nil)
+}
+
+// Terminate увольняет сотрудника и отзывает все его роли.
+// Дата увольнения по умолчанию - текущая дата; она не может быть раньше даты приема
+func (svc *Service) Terminate(ctx context.Context, id int64, request TerminateRequest) (Response, error) {
+	return svc.changeStatus(ctx, id, StatusTerminated, func(e *Entity) error {
+		terminationDate := today()
+		if request.TerminationDate != nil {
+			terminationDate = *request.TerminationDate
+		}
+		e.TerminationDate = &terminationDate
+		return validateDates(e.HireDate, e.TerminationDate)
+	})
+}
+
+// changeStatus проверяет, что переход из текущего статуса сотрудника в статус to допустим,
+// применяет к сотруднику изменения дат из apply и сохраняет новый статус
+func (svc *Service) changeStatus(ctx context.Context, id int64, to string, apply func(e *Entity) error) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", id), Err: err}
+	}
+
+	from := entity.Status
+	if !canTransition(from, to) {
+		return Response{}, common.ConflictError{
+			Message: fmt.Sprintf("employee %d cannot change status from '%s' to '%s'", id, from, to),
+		}
+	}
+
+	entity.Status = to
+	entity.UpdatedAt = time.Now()
+	if apply != nil {
+		if err := apply(&entity); err != nil {
+			return Response{}, err
+		}
+	}
+
+	err = svc.repo.ChangeStatus(ctx, &entity, from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.ConflictError{
+			Message: fmt.Sprintf("status of employee %d was changed by another request, retry", id),
+		}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error changing status of employee %d to '%s'", id, to), Err: err}
+	}
+
+	return entity.toResponse(), nil
+}
+
+// today возвращает текущую дату без времени
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
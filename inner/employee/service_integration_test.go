@@ -59,11 +59,12 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 
 		// Настраиваем mock
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "active").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		mock.ExpectCommit()
 
@@ -89,7 +90,7 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 		svc := NewService(repo, validator)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("Existing Employee").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 		mock.ExpectRollback()
@@ -116,11 +117,12 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 		svc := NewService(repo, validator)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "active").
 			WillReturnError(errors.New("insert failed"))
 		mock.ExpectRollback()
 
@@ -146,11 +148,12 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 		svc := NewService(repo, validator)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "active").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 
@@ -200,7 +203,7 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 		svc := NewService(repo, validator)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnError(errors.New("database connection error"))
 		mock.ExpectRollback()
@@ -227,11 +230,12 @@ func TestEmployeeService_AddTransactional_WithSqlMock(t *testing.T) {
 		svc := NewService(repo, validator)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE name = \$1\)`).
+		mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM employee WHERE lower\(name\) = lower\(\$1\)\)`).
 			WithArgs("John Doe").
 			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "active").
 			WillReturnError(errors.New("insert failed"))
 		mock.ExpectRollback().WillReturnError(errors.New("rollback failed"))
 
@@ -289,11 +293,12 @@ func TestEmployeeService_NonTransactionalMethods_WithSqlMock(t *testing.T) {
 		validator := validator.New()
 		svc := NewService(repo, validator)
 
-		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
-			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		mock.ExpectQuery(`INSERT INTO employee \(name, created_at, updated_at, email, login, employee_number,\s+first_name, last_name, job_title, phone, hire_date, termination_date, status\)\s+VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10, \$11, \$12, \$13\) RETURNING id`).
+			WithArgs("John Doe", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "active").
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
-		response, err := svc.Add(context.Background(), "John Doe")
+		response, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "John Doe"})
 
 		a.NoError(err)
 		a.Equal(int64(1), response.Id)
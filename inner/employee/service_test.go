@@ -2,14 +2,17 @@ package employee
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"idm/inner/common"
 	"idm/inner/common/validator"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -25,6 +28,11 @@ func (m *MockRepo) FindById(ctx context.Context, id int64) (Entity, error) {
 	return args.Get(0).(Entity), args.Error(1)
 }
 
+func (m *MockRepo) FindByIdWithDeleted(ctx context.Context, id int64) (Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
 func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
 	args := m.Called(ctx, e)
 	return args.Error(0)
@@ -35,19 +43,49 @@ func (m *MockRepo) FindAll(ctx context.Context) ([]Entity, error) {
 	return args.Get(0).([]Entity), args.Error(1)
 }
 
+func (m *MockRepo) FindAllWithDeleted(ctx context.Context) ([]Entity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Restore(ctx context.Context, id int64, at time.Time) (Entity, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) ChangeStatus(ctx context.Context, e *Entity, from string) error {
+	args := m.Called(ctx, e, from)
+	return args.Error(0)
+}
+
 func (m *MockRepo) FindByIds(ctx context.Context, ids []int64) ([]Entity, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]Entity), args.Error(1)
 }
 
+func (m *MockRepo) Update(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
 func (m *MockRepo) DeleteById(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRepo) DeleteByIds(ctx context.Context, ids []int64) error {
+func (m *MockRepo) DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) DeleteByIds(ctx context.Context, ids []int64) ([]int64, error) {
 	args := m.Called(ctx, ids)
-	return args.Error(0)
+	return args.Get(0).([]int64), args.Error(1)
 }
 
 func (m *MockRepo) BeginTransaction(ctx context.Context) (Transaction, error) {
@@ -68,6 +106,21 @@ func (m *MockRepo) AddTx(ctx context.Context, tx Transaction, e *Entity) error {
 	return args.Error(0)
 }
 
+func (m *MockRepo) FindByNameForUpdateTx(ctx context.Context, tx Transaction, name string) (Entity, error) {
+	args := m.Called(ctx, tx, name)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindIdByLoginTx(ctx context.Context, tx Transaction, login string) (int64, error) {
+	args := m.Called(ctx, tx, login)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) UpdateTx(ctx context.Context, tx Transaction, e *Entity) error {
+	args := m.Called(ctx, tx, e)
+	return args.Error(0)
+}
+
 func (m *MockRepo) CommitTransaction(tx *sqlx.Tx) error {
 	args := m.Called(tx)
 	return args.Error(0)
@@ -79,8 +132,8 @@ func (m *MockRepo) RollbackTransaction(tx *sqlx.Tx) error {
 }
 
 // Обновлённая сигнатура FindPage для MockRepo
-func (m *MockRepo) FindPage(ctx context.Context, limit, offset int, textFilter string) ([]Entity, error) {
-	args := m.Called(ctx, limit, offset, textFilter)
+func (m *MockRepo) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	args := m.Called(ctx, req)
 	return args.Get(0).([]Entity), args.Error(1)
 }
 
@@ -150,6 +203,18 @@ func TestEmployeeService_FindById(t *testing.T) {
 		a.Equal(want.Error(), got.Error())
 		a.True(repo.AssertNumberOfCalls(t, "FindById", 1))
 	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(42)).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.FindById(context.Background(), 42)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+		a.Equal("employee with id 42 not found", err.Error())
+	})
 }
 
 func TestEmployeeService_Add(t *testing.T) {
@@ -167,7 +232,7 @@ func TestEmployeeService_Add(t *testing.T) {
 		})
 
 		// Вызываем тестируемый метод
-		got, err := svc.Add(context.Background(), "John Doe")
+		got, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "John Doe"})
 
 		// Проверяем результат
 		a.Nil(err)
@@ -182,7 +247,7 @@ func TestEmployeeService_Add(t *testing.T) {
 		svc := NewService(repo, v)
 
 		// Вызываем с пустым именем
-		response, err := svc.Add(context.Background(), "")
+		response, err := svc.Add(context.Background(), AddEmployeeRequest{Name: ""})
 
 		// Проверяем результат
 		a.Empty(response)
@@ -200,7 +265,7 @@ func TestEmployeeService_Add(t *testing.T) {
 		repo.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(repoErr)
 
 		// Вызываем тестируемый метод
-		response, got := svc.Add(context.Background(), "John Doe")
+		response, got := svc.Add(context.Background(), AddEmployeeRequest{Name: "John Doe"})
 
 		// Проверяем результат
 		a.Empty(response)
@@ -208,6 +273,60 @@ func TestEmployeeService_Add(t *testing.T) {
 		a.Contains(got.Error(), "error adding employee")
 		a.True(repo.AssertNumberOfCalls(t, "Add", 1))
 	})
+
+	t.Run("should return already exists on unique violation", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(&pq.Error{Code: "23505"})
+
+		_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "John Doe"})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+		a.Equal("employee with name 'John Doe' already exists", err.Error())
+	})
+
+	t.Run("should report taken login", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).
+			Return(&pq.Error{Code: "23505", Constraint: loginUniqueIndex})
+
+		_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "John Doe", Profile: Profile{Login: "jdoe"}})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+		a.Equal("employee with login 'jdoe' already exists", err.Error())
+	})
+
+	t.Run("should store profile with default status", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		hireDate := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(nil)
+
+		got, err := svc.Add(context.Background(), AddEmployeeRequest{
+			Name: "John Doe",
+			Profile: Profile{
+				Email:          "john.doe@example.com",
+				Login:          "jdoe",
+				EmployeeNumber: "E-001",
+				FirstName:      "John",
+				LastName:       "Doe",
+				JobTitle:       "Engineer",
+				Phone:          "+79991234567",
+			},
+			HireDate: &hireDate,
+		})
+
+		a.Nil(err)
+		a.Equal(StatusActive, got.Status)
+		a.Equal("john.doe@example.com", got.Email)
+		a.Equal("jdoe", got.Login)
+		a.Equal("E-001", got.EmployeeNumber)
+		a.Equal("Engineer", got.JobTitle)
+		a.Equal(&hireDate, got.HireDate)
+		a.Nil(got.TerminationDate)
+	})
+
 }
 
 func TestEmployeeService_FindAll(t *testing.T) {
@@ -285,6 +404,163 @@ func TestEmployeeService_DeleteById(t *testing.T) {
 		a.Contains(err.Error(), "invalid employee id")
 		a.True(repo.AssertNumberOfCalls(t, "DeleteById", 0)) // репозиторий не должен вызываться
 	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteById", mock.Anything, int64(42)).Return(sql.ErrNoRows)
+
+		err := svc.DeleteById(context.Background(), 42)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+		a.Equal("employee with id 42 not found", err.Error())
+	})
+}
+
+func TestEmployeeService_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should update employee successfully", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		createdAt := time.Now().Add(-time.Hour)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(nil).Run(func(args mock.Arguments) {
+			entity := args.Get(1).(*Entity)
+			entity.CreatedAt = createdAt
+		})
+
+		got, err := svc.Update(context.Background(), 1, UpdateEmployeeRequest{Name: "Jane Doe"}, 0)
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Id)
+		a.Equal("Jane Doe", got.Name)
+		a.Equal(createdAt, got.CreatedAt)
+		a.True(got.UpdatedAt.After(createdAt))
+	})
+
+	t.Run("should update profile and keep status", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Email == "jane@example.com" && e.JobTitle == "Lead"
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*Entity).Status = StatusSuspended
+		})
+
+		got, err := svc.Update(context.Background(), 1, UpdateEmployeeRequest{
+			Name:    "Jane Doe",
+			Profile: Profile{Email: "jane@example.com", JobTitle: "Lead"},
+		}, 0)
+
+		a.Nil(err)
+		a.Equal("jane@example.com", got.Email)
+		a.Equal("Lead", got.JobTitle)
+		a.Equal(StatusSuspended, got.Status)
+	})
+
+	t.Run("should keep termination date of terminated employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		terminationDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.HireDate == nil && e.TerminationDate == nil
+		})).Return(nil).Run(func(args mock.Arguments) {
+			entity := args.Get(1).(*Entity)
+			entity.Status, entity.TerminationDate = StatusTerminated, &terminationDate
+		})
+
+		got, err := svc.Update(context.Background(), 1, UpdateEmployeeRequest{Name: "Jane Doe"}, 0)
+
+		a.Nil(err)
+		a.Equal(StatusTerminated, got.Status)
+		a.Equal(&terminationDate, got.TerminationDate)
+	})
+
+	t.Run("should return not found error if employee does not exist", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(sql.ErrNoRows)
+
+		_, err := svc.Update(context.Background(), 99, UpdateEmployeeRequest{Name: "Jane Doe"}, 0)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.NotFoundError{}))
+		a.Contains(err.Error(), "employee with id 99 not found")
+	})
+
+	t.Run("should not reach repository with invalid name", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Update(context.Background(), 1, UpdateEmployeeRequest{Name: "J"}, 0)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "Update", 0))
+	})
+
+	t.Run("should return precondition failed error on version mismatch", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool { return e.Version == 2 })).Return(sql.ErrNoRows)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Name: "John Doe", Version: 3}, nil)
+
+		_, err := svc.Update(context.Background(), 1, UpdateEmployeeRequest{Name: "Jane Doe"}, 2)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.PreconditionFailedError{}))
+		a.Contains(err.Error(), "current version 3")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found error if versioned employee does not exist", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(sql.ErrNoRows)
+		repo.On("FindById", mock.Anything, int64(99)).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Update(context.Background(), 99, UpdateEmployeeRequest{Name: "Jane Doe"}, 2)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestEmployeeService_DeleteByIdIfMatch(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should delete employee with matching version", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteByIdAndVersion", mock.Anything, int64(1), int64(3)).Return(int64(1), nil)
+
+		err := svc.DeleteByIdIfMatch(context.Background(), 1, 3)
+
+		a.Nil(err)
+		repo.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return precondition failed error on version mismatch", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteByIdAndVersion", mock.Anything, int64(1), int64(2)).Return(int64(0), nil)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Version: 3}, nil)
+
+		err := svc.DeleteByIdIfMatch(context.Background(), 1, 2)
+
+		a.True(errors.As(err, &common.PreconditionFailedError{}))
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteByIdAndVersion", mock.Anything, int64(1), int64(2)).Return(int64(0), errors.New("db down"))
+
+		err := svc.DeleteByIdIfMatch(context.Background(), 1, 2)
+
+		a.True(errors.As(err, &common.RepositoryError{}))
+	})
 }
 
 // TestEmployeeService_FindPage_Validation проверяет валидацию параметров пагинации
@@ -339,6 +615,179 @@ func TestEmployeeService_FindPage_Validation(t *testing.T) {
 	})
 }
 
+// TestEmployeeService_FindPage_SortAndFilters проверяет сортировку и фильтры страницы сотрудников
+func TestEmployeeService_FindPage_SortAndFilters(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should pass sort and filters to repository", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		req := PageRequest{PageSize: 10, Sort: "created_at:desc", CreatedFrom: &from, CreatedTo: &to, Ids: []int64{1, 2}}
+
+		repo.On("FindPage", mock.Anything, req).Return([]Entity{{Id: 2, Name: "Bob"}}, nil)
+		repo.On("CountAll", mock.Anything, req).Return(int64(1), nil)
+
+		got, err := svc.FindPage(context.Background(), req)
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Total)
+		a.Equal("Bob", got.Result[0].Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject sort field outside whitelist", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindPage(context.Background(), PageRequest{PageSize: 10, Sort: "password:asc"})
+
+		var validationErr common.RequestValidationError
+		a.True(errors.As(err, &validationErr))
+		a.Contains(validationErr.Message, "invalid sort field 'password'")
+		a.True(repo.AssertNumberOfCalls(t, "FindPage", 0))
+	})
+
+	t.Run("should reject unknown sort direction", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindPage(context.Background(), PageRequest{PageSize: 10, Sort: "name:up"})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindPage", 0))
+	})
+
+	t.Run("should reject inverted date range", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		from := time.Now()
+		to := from.Add(-time.Hour)
+
+		_, err := svc.FindPage(context.Background(), PageRequest{PageSize: 10, UpdatedFrom: &from, UpdatedTo: &to})
+
+		var validationErr common.RequestValidationError
+		a.True(errors.As(err, &validationErr))
+		a.Equal("updatedFrom must not be after updatedTo", validationErr.Message)
+	})
+
+	t.Run("should reject non-positive ids", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindPage(context.Background(), PageRequest{PageSize: 10, Ids: []int64{1, 0}})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindPage", 0))
+	})
+}
+
+// TestEmployeeService_FindAfter проверяет обход сотрудников по курсору
+func TestEmployeeService_FindAfter(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return next cursor when more employees remain", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindAfter", mock.Anything, PageRequest{}, int64(10), 3).
+			Return([]Entity{{Id: 11}, {Id: 12}, {Id: 15}}, nil)
+
+		got, err := svc.FindAfter(context.Background(), CursorRequest{Limit: 2, After: common.EncodeCursor(10)})
+
+		a.Nil(err)
+		a.Len(got.Result, 2)
+		a.NotNil(got.NextCursor)
+		next, err := common.DecodeCursor(*got.NextCursor)
+		a.NoError(err)
+		a.Equal(int64(12), next)
+	})
+
+	t.Run("should return null cursor at the end of the list", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindAfter", mock.Anything, PageRequest{TextFilter: "john"}, int64(0), 101).
+			Return([]Entity{{Id: 1}}, nil)
+
+		got, err := svc.FindAfter(context.Background(), CursorRequest{Limit: 100, TextFilter: "john"})
+
+		a.Nil(err)
+		a.Len(got.Result, 1)
+		a.Nil(got.NextCursor)
+	})
+
+	t.Run("should reject malformed cursor", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindAfter(context.Background(), CursorRequest{Limit: 10, After: "garbage"})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindAfter", 0))
+	})
+
+	t.Run("should reject limit above maximum", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.FindAfter(context.Background(), CursorRequest{Limit: 1001})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindAfter", 0))
+	})
+}
+
+// TestEmployeeService_Search проверяет поиск сотрудников по неполному имени и с опечатками
+func TestEmployeeService_Search(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should search by prefixes of every word", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		found := SearchEntity{Entity: Entity{Id: 7, Name: "Ivan Petrov"}, Rank: 1.2, Highlight: "<mark>Ivan</mark> <mark>Petrov</mark>"}
+		repo.On("Search", mock.Anything, "ivan:* & pet:*", "Ivan  Pet!", 20).Return([]SearchEntity{found}, nil)
+
+		got, err := svc.Search(context.Background(), SearchRequest{Query: "Ivan  Pet!", Limit: 20})
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal("Ivan Petrov", got[0].Name)
+		a.Equal(1.2, got[0].Rank)
+		a.Equal(found.Highlight, got[0].Highlight)
+	})
+
+	t.Run("should drop tsquery operators from input", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Search", mock.Anything, "o:* & brien:*", "o'brien & !", 5).Return([]SearchEntity{}, nil)
+
+		_, err := svc.Search(context.Background(), SearchRequest{Query: "o'brien & !", Limit: 5})
+
+		a.Nil(err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject query without words", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Search(context.Background(), SearchRequest{Query: " :* & ", Limit: 20})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "Search", 0))
+	})
+
+	t.Run("should reject empty query", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Search(context.Background(), SearchRequest{Limit: 20})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "Search", 0))
+	})
+}
+
 // StubRepo - stub-объект репозитория (созданный вручную)
 type StubRepo struct {
 	findByIdFunc func(ctx context.Context, id int64) (Entity, error)
@@ -352,6 +801,10 @@ func (s *StubRepo) FindById(ctx context.Context, id int64) (Entity, error) {
 	return Entity{}, errors.New("not implemented")
 }
 
+func (s *StubRepo) FindByIdWithDeleted(_ context.Context, _ int64) (Entity, error) {
+	return Entity{}, errors.New("not implemented")
+}
+
 func (s *StubRepo) Add(ctx context.Context, e *Entity) error {
 	if s.addFunc != nil {
 		return s.addFunc(ctx, e)
@@ -363,18 +816,42 @@ func (s *StubRepo) FindAll(_ context.Context) ([]Entity, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *StubRepo) FindAllWithDeleted(_ context.Context) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) Restore(_ context.Context, _ int64, _ time.Time) (Entity, error) {
+	return Entity{}, errors.New("not implemented")
+}
+
+func (s *StubRepo) PurgeDeleted(_ context.Context, _ time.Time) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *StubRepo) ChangeStatus(_ context.Context, _ *Entity, _ string) error {
+	return errors.New("not implemented")
+}
+
 func (s *StubRepo) FindByIds(_ context.Context, _ []int64) ([]Entity, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (s *StubRepo) DeleteById(_ context.Context, _ int64) error {
+func (s *StubRepo) Update(_ context.Context, _ *Entity) error {
 	return errors.New("not implemented")
 }
 
-func (s *StubRepo) DeleteByIds(_ context.Context, _ []int64) error {
+func (s *StubRepo) DeleteById(_ context.Context, _ int64) error {
 	return errors.New("not implemented")
 }
 
+func (s *StubRepo) DeleteByIdAndVersion(_ context.Context, _, _ int64) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *StubRepo) DeleteByIds(_ context.Context, _ []int64) ([]int64, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (s *StubRepo) BeginTransaction(_ context.Context) (Transaction, error) {
 	return nil, errors.New("not implemented")
 }
@@ -383,22 +860,52 @@ func (s *StubRepo) FindByNameTx(_ context.Context, _ Transaction, _ string) (boo
 	return false, errors.New("not implemented")
 }
 
+func (s *StubRepo) FindByNameForUpdateTx(_ context.Context, _ Transaction, _ string) (Entity, error) {
+	return Entity{}, errors.New("not implemented")
+}
+
+func (s *StubRepo) FindIdByLoginTx(_ context.Context, _ Transaction, _ string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *StubRepo) UpdateTx(_ context.Context, _ Transaction, _ *Entity) error {
+	return errors.New("not implemented")
+}
+
 func (s *StubRepo) AddTx(_ context.Context, _ Transaction, _ *Entity) error {
 	return errors.New("not implemented")
 }
 
 // Обновлённая сигнатура FindPage для StubRepo (если есть)
-func (s *StubRepo) FindPage(_ context.Context, _, _ int, _ string) ([]Entity, error) {
+func (s *StubRepo) FindPage(_ context.Context, _ PageRequest) ([]Entity, error) {
 	return nil, nil
 }
 
 // Обновлённая сигнатура CountAll для StubRepo
-func (s *StubRepo) CountAll(_ context.Context, _ string) (int64, error) {
+func (s *StubRepo) CountAll(_ context.Context, _ PageRequest) (int64, error) {
 	return 0, errors.New("not implemented")
 }
 
-func (m *MockRepo) CountAll(ctx context.Context, textFilter string) (int64, error) {
-	args := m.Called(ctx, textFilter)
+func (s *StubRepo) FindAfter(_ context.Context, _ PageRequest, _ int64, _ int) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) Search(_ context.Context, _, _ string, _ int) ([]SearchEntity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockRepo) FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error) {
+	args := m.Called(ctx, filter, afterId, limit)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Search(ctx context.Context, tsQuery, text string, limit int) ([]SearchEntity, error) {
+	args := m.Called(ctx, tsQuery, text, limit)
+	return args.Get(0).([]SearchEntity), args.Error(1)
+}
+
+func (m *MockRepo) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	args := m.Called(ctx, req)
 
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -509,7 +1016,7 @@ func TestEmployeeService_DeleteByIds(t *testing.T) {
 		svc := NewService(repo, v)
 
 		ids := []int64{1, 2}
-		repo.On("DeleteByIds", mock.Anything, ids).Return(nil)
+		repo.On("DeleteByIds", mock.Anything, ids).Return(ids, nil)
 
 		// Вызываем тестируемый метод
 		err := svc.DeleteByIds(context.Background(), ids)
@@ -526,7 +1033,7 @@ func TestEmployeeService_DeleteByIds(t *testing.T) {
 
 		ids := []int64{1, 2}
 		repoErr := errors.New("database error")
-		repo.On("DeleteByIds", mock.Anything, ids).Return(repoErr)
+		repo.On("DeleteByIds", mock.Anything, ids).Return([]int64(nil), repoErr)
 
 		// Вызываем тестируемый метод
 		err := svc.DeleteByIds(context.Background(), ids)
@@ -536,6 +1043,21 @@ func TestEmployeeService_DeleteByIds(t *testing.T) {
 		a.Contains(err.Error(), "error deleting employees by ids")
 		a.True(repo.AssertNumberOfCalls(t, "DeleteByIds", 1))
 	})
+
+	t.Run("should return not found with missing ids", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		ids := []int64{1, 2, 3}
+		repo.On("DeleteByIds", mock.Anything, ids).Return([]int64{2}, nil)
+
+		err := svc.DeleteByIds(context.Background(), ids)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+		a.Contains(err.Error(), "[1 3]")
+		a.Contains(err.Error(), "no employees were deleted")
+	})
 }
 
 // MockTransaction - мок для sqlx.Tx
@@ -914,7 +1436,7 @@ func TestService_MethodsWithInvalidData(t *testing.T) {
 		v := validator.New()
 		svc := NewService(repo, v)
 
-		response, err := svc.Add(context.Background(), "")
+		response, err := svc.Add(context.Background(), AddEmployeeRequest{Name: ""})
 
 		a.Empty(response)
 		a.NotNil(err)
@@ -932,7 +1454,7 @@ func TestService_MethodsWithInvalidData(t *testing.T) {
 		svc := NewService(repo, v)
 
 		// Слишком короткое имя
-		response, err := svc.Add(context.Background(), "J")
+		response, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "J"})
 
 		a.Empty(response)
 		a.NotNil(err)
@@ -982,7 +1504,7 @@ func TestService_ValidationDoesNotReachDatabase(t *testing.T) {
 		{
 			name: "empty_name_add",
 			testFunc: func(repo *MockRepo, svc *Service) error {
-				_, err := svc.Add(context.Background(), "")
+				_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: ""})
 				return err
 			},
 			description: "Add with empty name should not reach database",
@@ -990,7 +1512,7 @@ func TestService_ValidationDoesNotReachDatabase(t *testing.T) {
 		{
 			name: "short_name_add",
 			testFunc: func(repo *MockRepo, svc *Service) error {
-				_, err := svc.Add(context.Background(), "J")
+				_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "J"})
 				return err
 			},
 			description: "Add with short name should not reach database",
@@ -1071,7 +1593,7 @@ func TestService_ComplexValidationScenarios(t *testing.T) {
 		// Тест с именем ровно 2 символа
 		validName := "Jo"
 		repo.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(nil).Once()
-		_, err := svc.Add(context.Background(), validName)
+		_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: validName})
 		require.NoError(t, err)
 		// Это должно пройти валидацию, но может упасть на уровне репозитория
 		// Главное - валидация должна пройти успешно
@@ -1080,7 +1602,7 @@ func TestService_ComplexValidationScenarios(t *testing.T) {
 		repo2 := new(MockRepo)
 		svc2 := NewService(repo2, v)
 
-		_, err = svc2.Add(context.Background(), "J")
+		_, err = svc2.Add(context.Background(), AddEmployeeRequest{Name: "J"})
 		a.NotNil(err)
 		validationErr, ok := err.(common.RequestValidationError)
 		a.True(ok)
@@ -1096,7 +1618,7 @@ func TestService_ComplexValidationScenarios(t *testing.T) {
 		repo3.On("Add", mock.Anything, mock.AnythingOfType("*employee.Entity")).Return(nil).Once()
 		svc3 := NewService(repo3, v)
 
-		_, err = svc3.Add(context.Background(), maxValidName)
+		_, err = svc3.Add(context.Background(), AddEmployeeRequest{Name: maxValidName})
 		require.NoError(t, err)
 
 		// Тест с именем 101 символ (invalid)
@@ -1105,7 +1627,7 @@ func TestService_ComplexValidationScenarios(t *testing.T) {
 		svc4 := NewService(repo4, v)
 
 		tooLongName := maxValidName + "a"
-		_, err = svc4.Add(context.Background(), tooLongName)
+		_, err = svc4.Add(context.Background(), AddEmployeeRequest{Name: tooLongName})
 		a.NotNil(err)
 		validationErr, ok = err.(common.RequestValidationError)
 		a.True(ok)
@@ -1127,7 +1649,7 @@ func TestBusinessLogicProtectionDetailed(t *testing.T) {
 		{
 			name: "whitespace_only_name",
 			testFunc: func(repo *MockRepo, svc *Service) error {
-				_, err := svc.Add(context.Background(), "   ")
+				_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "   "})
 				return err
 			},
 			description:  "Whitespace-only name validation",
@@ -1141,7 +1663,7 @@ func TestBusinessLogicProtectionDetailed(t *testing.T) {
 					entity.Id = 1
 					entity.Name = "Владимир"
 				})
-				_, err := svc.Add(context.Background(), "Владимир")
+				_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: "Владимир"})
 				return err
 			},
 			description:  "Unicode name should reach database",
@@ -1224,7 +1746,7 @@ func TestValidationErrorTypes(t *testing.T) {
 		{
 			name: "add_validation_error",
 			testFunc: func() error {
-				_, err := svc.Add(context.Background(), "")
+				_, err := svc.Add(context.Background(), AddEmployeeRequest{Name: ""})
 				return err
 			},
 		},
@@ -1266,3 +1788,305 @@ func TestValidationErrorTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestEmployeeService_Restore(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should restore deleted employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Restore", mock.Anything, int64(1), mock.AnythingOfType("time.Time")).Return(Entity{Id: 1, Name: "John Doe", Version: 3}, nil)
+
+		got, err := svc.Restore(context.Background(), 1)
+
+		a.Nil(err)
+		a.Equal(Response{Id: 1, Name: "John Doe", Version: 3}, got)
+	})
+
+	t.Run("should return not found when employee is not deleted", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Restore", mock.Anything, int64(1), mock.AnythingOfType("time.Time")).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Restore(context.Background(), 1)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should reject invalid id", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Restore(context.Background(), 0)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEmployeeService_PurgeDeleted(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should purge employees deleted before retention period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		expectedBefore := time.Now().AddDate(0, 0, -30)
+		repo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return before.Sub(expectedBefore).Abs() < time.Minute
+		})).Return(int64(3), nil)
+
+		got, err := svc.PurgeDeleted(context.Background(), PurgeDeletedRequest{OlderThanDays: 30})
+
+		a.Nil(err)
+		a.Equal(int64(3), got.Purged)
+	})
+
+	t.Run("should require retention period", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.PurgeDeleted(context.Background(), PurgeDeletedRequest{})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEmployeeService_Lifecycle(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should activate pre-hire employee and set hire date", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Name: "John Doe", Status: StatusPreHire}, nil)
+		repo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Status == StatusActive && e.HireDate != nil
+		}), StatusPreHire).Return(nil)
+
+		got, err := svc.Activate(context.Background(), 1)
+
+		a.Nil(err)
+		a.Equal(StatusActive, got.Status)
+		a.NotNil(got.HireDate)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should suspend active employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusActive}, nil)
+		repo.On("ChangeStatus", mock.Anything, mock.AnythingOfType("*employee.Entity"), StatusActive).Return(nil)
+
+		got, err := svc.Suspend(context.Background(), 1)
+
+		a.Nil(err)
+		a.Equal(StatusSuspended, got.Status)
+	})
+
+	t.Run("should terminate suspended employee on requested date", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		terminationDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusSuspended}, nil)
+		repo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.Status == StatusTerminated && e.TerminationDate.Equal(terminationDate)
+		}), StatusSuspended).Return(nil)
+
+		got, err := svc.Terminate(context.Background(), 1, TerminateRequest{TerminationDate: &terminationDate})
+
+		a.Nil(err)
+		a.Equal(StatusTerminated, got.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject termination before hire date", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		hireDate := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		terminationDate := hireDate.AddDate(0, 0, -1)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusActive, HireDate: &hireDate}, nil)
+
+		_, err := svc.Terminate(context.Background(), 1, TerminateRequest{TerminationDate: &terminationDate})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject transition from terminated", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusTerminated}, nil)
+
+		_, err := svc.Activate(context.Background(), 1)
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		repo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject suspending pre-hire employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusPreHire}, nil)
+
+		_, err := svc.Suspend(context.Background(), 1)
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should report concurrent status change", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Status: StatusActive}, nil)
+		repo.On("ChangeStatus", mock.Anything, mock.Anything, StatusActive).Return(sql.ErrNoRows)
+
+		_, err := svc.Terminate(context.Background(), 1, TerminateRequest{})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Terminate(context.Background(), 1, TerminateRequest{})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+// TestEmployeeService_Import проверяет импорт сотрудников с сопоставлением по имени
+func TestEmployeeService_Import(t *testing.T) {
+	a := assert.New(t)
+	rows := []ImportRow{
+		{Line: 2, Request: AddEmployeeRequest{Name: "Ivan Petrov", Profile: Profile{Login: "ipetrov"}}, Fields: []string{"name", "login"}},
+		{Line: 3, Request: AddEmployeeRequest{Name: "Maria Sidorova", Profile: Profile{JobTitle: "Accountant"}}, Fields: []string{"name", "job_title"}},
+	}
+
+	t.Run("should create new and update existing employees", func(t *testing.T) {
+		repo := new(MockRepo)
+		tx := new(MockTransaction)
+		svc := NewService(repo, validator.New())
+		repo.On("BeginTransaction", mock.Anything).Return(tx, nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, "Ivan Petrov").Return(Entity{}, sql.ErrNoRows)
+		repo.On("FindIdByLoginTx", mock.Anything, tx, "ipetrov").Return(int64(0), nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, "Maria Sidorova").
+			Return(Entity{Id: 5, Name: "maria sidorova", Status: StatusActive, Version: 2}, nil)
+		repo.On("AddTx", mock.Anything, tx, mock.AnythingOfType("*employee.Entity")).
+			Run(func(args mock.Arguments) { args.Get(2).(*Entity).Id = 9 }).Return(nil)
+		repo.On("UpdateTx", mock.Anything, tx, mock.MatchedBy(func(e *Entity) bool {
+			return e.Id == 5 && e.Name == "Maria Sidorova" && e.JobTitle == "Accountant" && e.Version == 2
+		})).Return(nil)
+		tx.On("Commit").Return(nil)
+
+		report, err := svc.Import(context.Background(), rows, false)
+
+		a.Nil(err)
+		a.True(report.Applied)
+		a.Equal(1, report.Created)
+		a.Equal(1, report.Updated)
+		a.Equal(ImportRowResult{Line: 2, Name: "Ivan Petrov", Action: ImportCreate, Id: 9}, report.Rows[0])
+		a.Equal(ImportRowResult{Line: 3, Name: "Maria Sidorova", Action: ImportUpdate, Id: 5}, report.Rows[1])
+		tx.AssertNotCalled(t, "Rollback")
+	})
+
+	t.Run("should keep fields missing from the file", func(t *testing.T) {
+		repo := new(MockRepo)
+		tx := new(MockTransaction)
+		svc := NewService(repo, validator.New())
+		parsed, err := parseCSV(strings.NewReader("name\nMaria Sidorova\n"))
+		a.NoError(err)
+		repo.On("BeginTransaction", mock.Anything).Return(tx, nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, "Maria Sidorova").
+			Return(Entity{Id: 5, Name: "Maria Sidorova", Email: "maria@example.com", Login: "msidorova", Version: 2}, nil)
+		repo.On("UpdateTx", mock.Anything, tx, mock.MatchedBy(func(e *Entity) bool {
+			return e.Id == 5 && e.Email == "maria@example.com" && e.Login == "msidorova"
+		})).Return(nil)
+		tx.On("Commit").Return(nil)
+
+		report, err := svc.Import(context.Background(), parsed, false)
+
+		a.Nil(err)
+		a.Equal(1, report.Updated)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should only report and rollback on dry run", func(t *testing.T) {
+		repo := new(MockRepo)
+		tx := new(MockTransaction)
+		svc := NewService(repo, validator.New())
+		repo.On("BeginTransaction", mock.Anything).Return(tx, nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, mock.Anything).Return(Entity{}, sql.ErrNoRows)
+		repo.On("FindIdByLoginTx", mock.Anything, tx, "ipetrov").Return(int64(0), nil)
+		tx.On("Rollback").Return(nil)
+
+		report, err := svc.Import(context.Background(), rows, true)
+
+		a.Nil(err)
+		a.True(report.DryRun)
+		a.False(report.Applied)
+		a.Equal(2, report.Created)
+		repo.AssertNotCalled(t, "AddTx", mock.Anything, mock.Anything, mock.Anything)
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("should reject whole file when any row fails", func(t *testing.T) {
+		repo := new(MockRepo)
+		tx := new(MockTransaction)
+		svc := NewService(repo, validator.New())
+		invalid := []ImportRow{
+			rows[0],
+			{Line: 3, Request: AddEmployeeRequest{Name: "X"}},
+			{Line: 4, Err: errors.New("invalid hire_date: expected YYYY-MM-DD")},
+			{Line: 5, Request: AddEmployeeRequest{Name: "IVAN PETROV"}},
+			{Line: 6, Request: AddEmployeeRequest{Name: "Deleted Person"}},
+		}
+		deletedAt := time.Now()
+		repo.On("BeginTransaction", mock.Anything).Return(tx, nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, "Ivan Petrov").Return(Entity{}, sql.ErrNoRows)
+		repo.On("FindIdByLoginTx", mock.Anything, tx, "ipetrov").Return(int64(3), nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, "Deleted Person").Return(Entity{Id: 4, DeletedAt: &deletedAt}, nil)
+		tx.On("Rollback").Return(nil)
+
+		report, err := svc.Import(context.Background(), invalid, false)
+
+		a.Nil(err)
+		a.False(report.Applied)
+		a.Equal(3, report.Conflicts)
+		a.Equal(2, report.Errors)
+		a.Equal(`login "ipetrov" is already taken by employee 3`, report.Rows[0].Error)
+		a.Equal(ImportError, report.Rows[1].Action)
+		a.Equal("invalid hire_date: expected YYYY-MM-DD", report.Rows[2].Error)
+		a.Equal(`name "IVAN PETROV" is already used on line 2`, report.Rows[3].Error)
+		a.Equal(ImportConflict, report.Rows[4].Action)
+		repo.AssertNotCalled(t, "AddTx", mock.Anything, mock.Anything, mock.Anything)
+		tx.AssertCalled(t, "Rollback")
+	})
+
+	t.Run("should rollback on write error", func(t *testing.T) {
+		repo := new(MockRepo)
+		tx := new(MockTransaction)
+		svc := NewService(repo, validator.New())
+		repo.On("BeginTransaction", mock.Anything).Return(tx, nil)
+		repo.On("FindByNameForUpdateTx", mock.Anything, tx, mock.Anything).Return(Entity{}, sql.ErrNoRows)
+		repo.On("FindIdByLoginTx", mock.Anything, tx, "ipetrov").Return(int64(0), nil)
+		repo.On("AddTx", mock.Anything, tx, mock.Anything).Return(errors.New("database insert error"))
+		tx.On("Rollback").Return(nil)
+
+		_, err := svc.Import(context.Background(), rows, false)
+
+		a.ErrorContains(err, "error importing employee from line 2")
+		tx.AssertCalled(t, "Rollback")
+		tx.AssertNotCalled(t, "Commit")
+	})
+
+	t.Run("should reject empty file", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Import(context.Background(), nil, false)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "BeginTransaction", mock.Anything)
+	})
+}
@@ -37,7 +37,7 @@ func setupTest(t *testing.T) (*fiber.App, *MockDatabase) {
 	logger := common.NewTestLogger()
 
 	// Используем конструктор веб-сервера из вашего кода
-	server := web.NewServer(logger)
+	server := web.NewServer(logger, web.AuthConfig{})
 
 	// Создаем мок базы данных
 	mockDB := new(MockDatabase)
@@ -156,7 +156,7 @@ func TestGetHealth(t *testing.T) {
 func TestGetHealthDetailed(t *testing.T) {
 	// Отдельный setup для детального health check
 	logger := common.NewTestLogger()
-	server := web.NewServer(logger)
+	server := web.NewServer(logger, web.AuthConfig{})
 	mockDB := new(MockDatabase)
 	cfg := common.Config{
 		DbDriverName: "postgres",
@@ -195,7 +195,7 @@ func TestGetHealthDetailed(t *testing.T) {
 		// Создаем новый мок для этого теста
 		mockDB2 := new(MockDatabase)
 		logger2 := common.NewTestLogger()
-		server2 := web.NewServer(logger2)
+		server2 := web.NewServer(logger2, web.AuthConfig{})
 		controller2 := NewController(server2, cfg, mockDB2, logger2)
 		controller2.RegisterRoutes()
 		server2.GroupInternal.Get("/health/detailed", controller2.GetHealthDetailed)
@@ -0,0 +1,393 @@
+package orgunit
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с подразделениями
+type Controller struct {
+	server         *web.Server
+	orgUnitService Svc
+	logger         *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с подразделениями
+type Svc interface {
+	Add(ctx context.Context, request AddOrgUnitRequest) (Response, error)                          // создание подразделения
+	FindById(ctx context.Context, id int64) (Response, error)                                      // подразделение по ID
+	FindAll(ctx context.Context) ([]Response, error)                                               // все подразделения списком
+	FindTree(ctx context.Context) ([]*TreeResponse, error)                                         // дерево подразделений
+	FindChildren(ctx context.Context, id int64) ([]Response, error)                                // вложенные подразделения
+	Update(ctx context.Context, id int64, request UpdateOrgUnitRequest) (Response, error)          // переименование и перемещение
+	DeleteById(ctx context.Context, id int64) error                                                // удаление пустого подразделения
+	FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberResponse, error)           // сотрудники подразделения
+	FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerResponse, error)             // цепочка руководителей
+	Place(ctx context.Context, employeeId int64, request PlacementRequest) (MemberResponse, error) // подразделение и руководитель сотрудника
+}
+
+// NewController создает новый экземпляр контроллера подразделений
+func NewController(server *web.Server, orgUnitService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:         server,
+		orgUnitService: orgUnitService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с подразделениями
+func (c *Controller) RegisterRoutes() {
+	api := c.server.GroupApiV1
+
+	// Справочник подразделений; /tree регистрируется раньше /:id
+	api.Post("/org-units", c.server.Require(web.OrgUnitWrite), c.CreateOrgUnit)
+	api.Get("/org-units", c.server.Require(web.OrgUnitRead), c.GetAllOrgUnits)
+	api.Get("/org-units/tree", c.server.Require(web.OrgUnitRead), c.GetOrgUnitTree)
+	api.Get("/org-units/:id", c.server.Require(web.OrgUnitRead), c.GetOrgUnit)
+	api.Put("/org-units/:id", c.server.Require(web.OrgUnitWrite), c.UpdateOrgUnit)
+	api.Delete("/org-units/:id", c.server.Require(web.OrgUnitDelete), c.DeleteOrgUnit)
+	api.Get("/org-units/:id/children", c.server.Require(web.OrgUnitRead), c.GetOrgUnitChildren)
+	api.Get("/org-units/:id/members", c.server.Require(web.OrgUnitRead), c.GetOrgUnitMembers)
+
+	// Подчинение сотрудников
+	api.Put("/employees/:id/placement", c.server.Require(web.OrgUnitWrite), c.PlaceEmployee)
+	api.Get("/employees/:id/managers", c.server.Require(web.OrgUnitRead), c.GetEmployeeManagers)
+}
+
+// CreateOrgUnit создает новое подразделение
+// @Summary Создать подразделение
+// @Description Создать подразделение; название уникально среди подразделений одного родителя
+// @Tags org-unit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body orgunit.AddOrgUnitRequest true "данные подразделения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /org-units [post]
+func (c *Controller) CreateOrgUnit(ctx *fiber.Ctx) error {
+	var req AddOrgUnitRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create org unit: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.orgUnitService.Add(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create org unit: failed to create org unit", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning created org unit")
+	}
+	return nil
+}
+
+// GetAllOrgUnits получает все подразделения
+// @Summary Получить все подразделения
+// @Description Получить плоский список всех подразделений
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /org-units [get]
+func (c *Controller) GetAllOrgUnits(ctx *fiber.Ctx) error {
+	resp, err := c.orgUnitService.FindAll(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get all org units: failed to find org units", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning org units")
+	}
+	return nil
+}
+
+// GetOrgUnitTree получает дерево подразделений
+// @Summary Получить дерево подразделений
+// @Description Получить корневые подразделения с вложенными подразделениями
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /org-units/tree [get]
+func (c *Controller) GetOrgUnitTree(ctx *fiber.Ctx) error {
+	resp, err := c.orgUnitService.FindTree(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get org unit tree: failed to build tree", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning org unit tree")
+	}
+	return nil
+}
+
+// GetOrgUnit получает подразделение по ID
+// @Summary Получить подразделение по ID
+// @Description Получить подразделение по его идентификатору
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подразделения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /org-units/{id} [get]
+func (c *Controller) GetOrgUnit(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid org unit id")
+	}
+
+	resp, err := c.orgUnitService.FindById(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get org unit: failed to find org unit", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning org unit")
+	}
+	return nil
+}
+
+// UpdateOrgUnit изменяет подразделение
+// @Summary Изменить подразделение
+// @Description Переименовать подразделение или переместить его к другому родителю; перемещение внутрь собственных потомков запрещено
+// @Tags org-unit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подразделения"
+// @Param request body orgunit.UpdateOrgUnitRequest true "данные подразделения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /org-units/{id} [put]
+func (c *Controller) UpdateOrgUnit(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid org unit id")
+	}
+
+	var req UpdateOrgUnitRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update org unit: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.orgUnitService.Update(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update org unit: failed to update org unit", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning updated org unit")
+	}
+	return nil
+}
+
+// DeleteOrgUnit удаляет подразделение
+// @Summary Удалить подразделение
+// @Description Удалить подразделение без вложенных подразделений и сотрудников
+// @Tags org-unit
+// @Security BearerAuth
+// @Param id path int true "ID подразделения"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /org-units/{id} [delete]
+func (c *Controller) DeleteOrgUnit(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid org unit id")
+	}
+
+	if err := c.orgUnitService.DeleteById(ctx.Context(), id); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "delete org unit: failed to delete org unit", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// GetOrgUnitChildren получает вложенные подразделения
+// @Summary Получить вложенные подразделения
+// @Description Получить подразделения, непосредственно вложенные в заданное
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подразделения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /org-units/{id}/children [get]
+func (c *Controller) GetOrgUnitChildren(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid org unit id")
+	}
+
+	resp, err := c.orgUnitService.FindChildren(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get org unit children: failed to find children", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning org unit children")
+	}
+	return nil
+}
+
+// GetOrgUnitMembers получает сотрудников подразделения
+// @Summary Получить сотрудников подразделения
+// @Description Получить сотрудников подразделения; с recursive=true также сотрудников всех вложенных подразделений
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID подразделения"
+// @Param recursive query bool false "включать вложенные подразделения"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /org-units/{id}/members [get]
+func (c *Controller) GetOrgUnitMembers(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid org unit id")
+	}
+
+	recursive, err := strconv.ParseBool(ctx.Query("recursive", "false"))
+	if err != nil {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid recursive flag")
+	}
+
+	resp, err := c.orgUnitService.FindMembers(ctx.Context(), id, recursive)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get org unit members: failed to find members", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning org unit members")
+	}
+	return nil
+}
+
+// PlaceEmployee назначает сотруднику подразделение и руководителя
+// @Summary Назначить подразделение и руководителя
+// @Description Заменить подразделение и руководителя сотрудника; пустое значение снимает привязку. Руководитель не может быть подчиненным сотрудника
+// @Tags org-unit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param request body orgunit.PlacementRequest true "подразделение и руководитель"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/placement [put]
+func (c *Controller) PlaceEmployee(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	var req PlacementRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "place employee: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.orgUnitService.Place(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "place employee: failed to place employee", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee placement")
+	}
+	return nil
+}
+
+// GetEmployeeManagers получает цепочку руководителей сотрудника
+// @Summary Получить руководителей сотрудника
+// @Description Получить цепочку руководителей от непосредственного (level 1) до верхнего
+// @Tags org-unit
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/managers [get]
+func (c *Controller) GetEmployeeManagers(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	resp, err := c.orgUnitService.FindManagerChain(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get employee managers: failed to find managers", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee managers")
+	}
+	return nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.AlreadyExistsError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
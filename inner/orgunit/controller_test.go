@@ -0,0 +1,222 @@
+package orgunit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrgUnitService - полный мок для интерфейса Svc
+type MockOrgUnitService struct {
+	mock.Mock
+}
+
+func (m *MockOrgUnitService) Add(ctx context.Context, request AddOrgUnitRequest) (Response, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockOrgUnitService) FindById(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockOrgUnitService) FindAll(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockOrgUnitService) FindTree(ctx context.Context) ([]*TreeResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*TreeResponse), args.Error(1)
+}
+
+func (m *MockOrgUnitService) FindChildren(ctx context.Context, id int64) ([]Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockOrgUnitService) Update(ctx context.Context, id int64, request UpdateOrgUnitRequest) (Response, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockOrgUnitService) DeleteById(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOrgUnitService) FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberResponse, error) {
+	args := m.Called(ctx, id, recursive)
+	return args.Get(0).([]MemberResponse), args.Error(1)
+}
+
+func (m *MockOrgUnitService) FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerResponse, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]ManagerResponse), args.Error(1)
+}
+
+func (m *MockOrgUnitService) Place(ctx context.Context, employeeId int64, request PlacementRequest) (MemberResponse, error) {
+	args := m.Called(ctx, employeeId, request)
+	return args.Get(0).(MemberResponse), args.Error(1)
+}
+
+func setupTest(t *testing.T) (*fiber.App, *MockOrgUnitService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockOrgUnitService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestCreateOrgUnit(t *testing.T) {
+	t.Run("should create org unit for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AddOrgUnitRequest{Name: "Бухгалтерия"}
+		svc.On("Add", mock.Anything, request).Return(Response{Id: 1, Name: "Бухгалтерия"}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/org-units", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/org-units", AddOrgUnitRequest{Name: "Бухгалтерия"}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestGetOrgUnitTree(t *testing.T) {
+	t.Run("should return tree for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		expected := []*TreeResponse{{
+			Response: Response{Id: 1, Name: "Головной офис"},
+			Children: []*TreeResponse{{Response: Response{Id: 2, Name: "Бухгалтерия"}, Children: []*TreeResponse{}}},
+		}}
+		svc.On("FindTree", mock.Anything).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/org-units/tree", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]*TreeResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "Бухгалтерия", result.Data[0].Children[0].Name)
+		svc.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateOrgUnit(t *testing.T) {
+	t.Run("should return 400 for move into descendant", func(t *testing.T) {
+		app, svc := setupTest(t)
+		parentId := int64(4)
+		request := UpdateOrgUnitRequest{Name: "Головной офис", ParentId: &parentId}
+		svc.On("Update", mock.Anything, int64(1), request).
+			Return(Response{}, common.RequestValidationError{Message: "org unit 1 cannot be moved into its descendant 4"})
+
+		resp, err := app.Test(createAuthRequest(t, "PUT", "/api/v1/org-units/1", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestDeleteOrgUnit(t *testing.T) {
+	t.Run("should return 404 for missing org unit", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("DeleteById", mock.Anything, int64(4)).Return(common.NotFoundError{Message: "org unit with id 4 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/org-units/4", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
+
+func TestGetOrgUnitMembers(t *testing.T) {
+	t.Run("should pass recursive flag", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindMembers", mock.Anything, int64(1), true).Return([]MemberResponse{{Id: 5, Name: "Иванов"}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/org-units/1/members?recursive=true", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for invalid recursive flag", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/org-units/1/members?recursive=maybe", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestPlaceEmployee(t *testing.T) {
+	t.Run("should place employee for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		managerId := int64(2)
+		request := PlacementRequest{ManagerId: &managerId}
+		svc.On("Place", mock.Anything, int64(5), request).Return(MemberResponse{Id: 5, ManagerId: &managerId}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "PUT", "/api/v1/employees/5/placement", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return manager chain for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		expected := []ManagerResponse{{MemberResponse: MemberResponse{Id: 2, Name: "Петров"}, Level: 1}}
+		svc.On("FindManagerChain", mock.Anything, int64(5)).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/5/managers", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]ManagerResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, expected, result.Data)
+	})
+}
@@ -0,0 +1,82 @@
+package orgunit
+
+import "time"
+
+// Entity представляет подразделение в базе данных
+type Entity struct {
+	Id        int64     `db:"id"`
+	Name      string    `db:"name"`
+	ParentId  *int64    `db:"parent_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// toResponse преобразует Entity в Response
+func (e *Entity) toResponse() Response {
+	return Response{
+		Id:        e.Id,
+		Name:      e.Name,
+		ParentId:  e.ParentId,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// MemberEntity представляет сотрудника в составе подразделения
+type MemberEntity struct {
+	Id        int64  `db:"id"`
+	Name      string `db:"name"`
+	Email     string `db:"email"`
+	JobTitle  string `db:"job_title"`
+	OrgUnitId *int64 `db:"org_unit_id"`
+	ManagerId *int64 `db:"manager_id"`
+}
+
+// toResponse преобразует MemberEntity в MemberResponse
+func (e *MemberEntity) toResponse() MemberResponse {
+	return MemberResponse{
+		Id:        e.Id,
+		Name:      e.Name,
+		Email:     e.Email,
+		JobTitle:  e.JobTitle,
+		OrgUnitId: e.OrgUnitId,
+		ManagerId: e.ManagerId,
+	}
+}
+
+// ManagerEntity представляет руководителя в цепочке подчинения сотрудника
+type ManagerEntity struct {
+	MemberEntity
+	Level int `db:"level"`
+}
+
+// Response представляет ответ API для подразделения
+type Response struct {
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ParentId  *int64    `json:"parent_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TreeResponse представляет подразделение вместе со всеми вложенными подразделениями
+type TreeResponse struct {
+	Response
+	Children []*TreeResponse `json:"children"`
+}
+
+// MemberResponse представляет сотрудника подразделения
+type MemberResponse struct {
+	Id        int64  `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	JobTitle  string `json:"job_title"`
+	OrgUnitId *int64 `json:"org_unit_id,omitempty"`
+	ManagerId *int64 `json:"manager_id,omitempty"`
+}
+
+// ManagerResponse представляет руководителя сотрудника; Level 1 - непосредственный руководитель
+type ManagerResponse struct {
+	MemberResponse
+	Level int `json:"level"`
+}
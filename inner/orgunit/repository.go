@@ -0,0 +1,203 @@
+package orgunit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository представляет репозиторий для работы с подразделениями
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// memberColumns - поля сотрудника, возвращаемые в составе подразделения и цепочке руководителей
+const memberColumns = "e.id, e.name, e.email, e.job_title, e.org_unit_id, e.manager_id"
+
+// Add сохраняет новое подразделение
+func (r *Repository) Add(ctx context.Context, e *Entity) error {
+	query := `INSERT INTO org_unit (name, parent_id, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.ParentId, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+}
+
+// FindById возвращает подразделение по ID
+func (r *Repository) FindById(ctx context.Context, id int64) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT * FROM org_unit WHERE id = $1", id)
+	return res, err
+}
+
+// FindAll возвращает все подразделения
+func (r *Repository) FindAll(ctx context.Context) (res []Entity, err error) {
+	err = r.db.SelectContext(ctx, &res, "SELECT * FROM org_unit ORDER BY name, id")
+	return res, err
+}
+
+// FindChildren возвращает непосредственно вложенные подразделения
+func (r *Repository) FindChildren(ctx context.Context, id int64) (res []Entity, err error) {
+	err = r.db.SelectContext(ctx, &res, "SELECT * FROM org_unit WHERE parent_id = $1 ORDER BY name, id", id)
+	return res, err
+}
+
+// ancestorIdsQuery выбирает ID всех вышестоящих подразделений $1, начиная с непосредственного родителя.
+// Путь обхода запоминается, чтобы запрос завершался даже на некорректных данных с циклом
+const ancestorIdsQuery = `WITH RECURSIVE ancestor AS (
+		SELECT parent_id AS id, 1 AS depth, ARRAY[id, parent_id] AS path
+		FROM org_unit WHERE id = $1 AND parent_id IS NOT NULL
+		UNION ALL
+		SELECT u.parent_id, a.depth + 1, a.path || u.parent_id
+		FROM org_unit u JOIN ancestor a ON u.id = a.id
+		WHERE u.parent_id IS NOT NULL AND NOT u.parent_id = ANY(a.path)
+	)
+	SELECT id FROM ancestor ORDER BY depth`
+
+// Ключи транзакционных блокировок (pg_advisory_xact_lock), под которыми проверяются и изменяются
+// дерево подразделений и подчинение сотрудников
+const (
+	orgUnitTreeLock = "org_unit.parent_id"
+	managerTreeLock = "employee.manager_id"
+)
+
+// ParentCheck проверяет перемещение подразделения по ID вышестоящих подразделений нового родителя;
+// ошибка проверки отменяет перемещение
+type ParentCheck func(ancestorIds []int64) error
+
+// Update переименовывает и перемещает подразделение. Возвращает sql.ErrNoRows, если подразделение не найдено.
+// Если у подразделения задан родитель, check вызывается в одной транзакции с изменением под блокировкой дерева
+// подразделений, поэтому параллельные перемещения не могут вместе замкнуть цикл
+func (r *Repository) Update(ctx context.Context, e *Entity, check ParentCheck) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", orgUnitTreeLock); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if e.ParentId != nil {
+		var ancestorIds []int64
+		if err := tx.SelectContext(ctx, &ancestorIds, ancestorIdsQuery, *e.ParentId); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		if err := check(ancestorIds); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	query := `UPDATE org_unit SET name = $1, parent_id = $2, updated_at = $3 WHERE id = $4 RETURNING created_at`
+	if err := tx.QueryRowContext(ctx, query, e.Name, e.ParentId, e.UpdatedAt, e.Id).Scan(&e.CreatedAt); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// HasDependents проверяет, есть ли у подразделения вложенные подразделения или сотрудники (включая удаленных)
+func (r *Repository) HasDependents(ctx context.Context, id int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM org_unit WHERE parent_id = $1)
+		OR EXISTS(SELECT 1 FROM employee WHERE org_unit_id = $1)`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, query, id)
+	return exists, err
+}
+
+// DeleteById удаляет подразделение, возвращает количество удаленных строк
+func (r *Repository) DeleteById(ctx context.Context, id int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM org_unit WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FindMembers возвращает неудаленных сотрудников подразделения.
+// Если recursive, в результат входят и сотрудники всех вложенных подразделений
+func (r *Repository) FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberEntity, error) {
+	query := `SELECT ` + memberColumns + ` FROM employee e
+		WHERE e.org_unit_id = $1 AND e.deleted_at IS NULL
+		ORDER BY e.name, e.id`
+	if recursive {
+		query = `WITH RECURSIVE unit AS (
+				SELECT id FROM org_unit WHERE id = $1
+				UNION
+				SELECT u.id FROM org_unit u JOIN unit ON u.parent_id = unit.id
+			)
+			SELECT ` + memberColumns + ` FROM employee e
+			JOIN unit ON e.org_unit_id = unit.id
+			WHERE e.deleted_at IS NULL
+			ORDER BY e.name, e.id`
+	}
+	var res []MemberEntity
+	err := r.db.SelectContext(ctx, &res, query, id)
+	return res, err
+}
+
+// FindEmployee возвращает неудаленного сотрудника по ID
+func (r *Repository) FindEmployee(ctx context.Context, id int64) (res MemberEntity, err error) {
+	query := `SELECT ` + memberColumns + ` FROM employee e WHERE e.id = $1 AND e.deleted_at IS NULL`
+	err = r.db.GetContext(ctx, &res, query, id)
+	return res, err
+}
+
+// managerChainQuery выбирает руководителей сотрудника $1 снизу вверх: непосредственного руководителя,
+// его руководителя и так далее. Цепочка обрывается на удаленном сотруднике
+const managerChainQuery = `WITH RECURSIVE chain AS (
+		SELECT manager_id AS id, 1 AS level, ARRAY[id, manager_id] AS path
+		FROM employee WHERE id = $1 AND manager_id IS NOT NULL
+		UNION ALL
+		SELECT e.manager_id, c.level + 1, c.path || e.manager_id
+		FROM employee e JOIN chain c ON e.id = c.id
+		WHERE e.manager_id IS NOT NULL AND e.deleted_at IS NULL AND NOT e.manager_id = ANY(c.path)
+	)
+	SELECT ` + memberColumns + `, c.level
+	FROM chain c JOIN employee e ON e.id = c.id AND e.deleted_at IS NULL
+	ORDER BY c.level`
+
+// FindManagerChain возвращает руководителей сотрудника снизу вверх: непосредственного руководителя,
+// его руководителя и так далее. Цепочка обрывается на удаленном сотруднике
+func (r *Repository) FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerEntity, error) {
+	var res []ManagerEntity
+	err := r.db.SelectContext(ctx, &res, managerChainQuery, employeeId)
+	return res, err
+}
+
+// ManagerCheck проверяет нового руководителя сотрудника по цепочке руководителей самого руководителя;
+// ошибка проверки отменяет назначение
+type ManagerCheck func(chain []ManagerEntity) error
+
+// SetPlacement назначает сотруднику подразделение и руководителя и увеличивает версию записи сотрудника.
+// Возвращает sql.ErrNoRows, если сотрудник не найден или удален.
+// Если руководитель задан, check вызывается в одной транзакции с назначением под блокировкой подчинения,
+// поэтому параллельные назначения руководителей не могут вместе замкнуть цикл
+func (r *Repository) SetPlacement(ctx context.Context, employeeId int64, orgUnitId, managerId *int64, at time.Time, check ManagerCheck) (res MemberEntity, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", managerTreeLock); err != nil {
+		return res, errors.Join(err, tx.Rollback())
+	}
+	if managerId != nil {
+		var chain []ManagerEntity
+		if err := tx.SelectContext(ctx, &chain, managerChainQuery, *managerId); err != nil {
+			return res, errors.Join(err, tx.Rollback())
+		}
+		if err := check(chain); err != nil {
+			return res, errors.Join(err, tx.Rollback())
+		}
+	}
+
+	query := `UPDATE employee e SET org_unit_id = $2, manager_id = $3, updated_at = $4, version = version + 1
+		WHERE e.id = $1 AND e.deleted_at IS NULL
+		RETURNING ` + memberColumns
+	if err := tx.GetContext(ctx, &res, query, employeeId, orgUnitId, managerId, at); err != nil {
+		return res, errors.Join(err, tx.Rollback())
+	}
+	return res, tx.Commit()
+}
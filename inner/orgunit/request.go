@@ -0,0 +1,20 @@
+package orgunit
+
+// AddOrgUnitRequest используется для создания подразделения; без parent_id подразделение становится корневым
+type AddOrgUnitRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	ParentId *int64 `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+// UpdateOrgUnitRequest используется для переименования и перемещения подразделения
+type UpdateOrgUnitRequest struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	ParentId *int64 `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+// PlacementRequest используется для назначения сотруднику подразделения и руководителя.
+// Пустое значение поля снимает соответствующую привязку
+type PlacementRequest struct {
+	OrgUnitId *int64 `json:"org_unit_id" validate:"omitempty,gt=0"`
+	ManagerId *int64 `json:"manager_id" validate:"omitempty,gt=0"`
+}
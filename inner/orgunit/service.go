@@ -0,0 +1,371 @@
+package orgunit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"slices"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику подразделений
+type Service struct {
+	repo      Repo
+	validator Validator
+}
+
+// Repo интерфейс репозитория для подразделений
+type Repo interface {
+	Add(ctx context.Context, e *Entity) error
+	FindById(ctx context.Context, id int64) (Entity, error)
+	FindAll(ctx context.Context) ([]Entity, error)
+	FindChildren(ctx context.Context, id int64) ([]Entity, error)
+	Update(ctx context.Context, e *Entity, check ParentCheck) error
+	HasDependents(ctx context.Context, id int64) (bool, error)
+	DeleteById(ctx context.Context, id int64) (int64, error)
+	FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberEntity, error)
+	FindEmployee(ctx context.Context, id int64) (MemberEntity, error)
+	FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerEntity, error)
+	SetPlacement(ctx context.Context, employeeId int64, orgUnitId, managerId *int64, at time.Time, check ManagerCheck) (MemberEntity, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	if err := svc.validator.ValidateWithCustomMessages(request); err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Add создает подразделение
+func (svc *Service) Add(ctx context.Context, request AddOrgUnitRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if request.ParentId != nil {
+		if err := svc.checkOrgUnit(ctx, *request.ParentId); err != nil {
+			return Response{}, err
+		}
+	}
+
+	now := time.Now()
+	entity := &Entity{
+		Name:      request.Name,
+		ParentId:  request.ParentId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := svc.repo.Add(ctx, entity)
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: "error adding org unit", Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindById возвращает подразделение по ID
+func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid org unit id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("org unit with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error finding org unit with id %d", id), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindAll возвращает все подразделения списком
+func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding all org units", Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// FindTree возвращает дерево подразделений: список корневых подразделений с вложенными
+func (svc *Service) FindTree(ctx context.Context) ([]*TreeResponse, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding all org units", Err: err}
+	}
+	return buildTree(entities), nil
+}
+
+// FindChildren возвращает непосредственно вложенные подразделения
+func (svc *Service) FindChildren(ctx context.Context, id int64) ([]Response, error) {
+	if err := svc.checkOrgUnit(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindChildren(ctx, id)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding children of org unit %d", id), Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// Update переименовывает и перемещает подразделение.
+// Дерево должно оставаться деревом: подразделение нельзя переместить внутрь самого себя или своих потомков
+func (svc *Service) Update(ctx context.Context, id int64, request UpdateOrgUnitRequest) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid org unit id: %d", id)}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkOrgUnit(ctx, id); err != nil {
+		return Response{}, err
+	}
+	if request.ParentId != nil {
+		if err := svc.checkNewParent(ctx, id, *request.ParentId); err != nil {
+			return Response{}, err
+		}
+	}
+
+	entity := &Entity{
+		Id:        id,
+		Name:      request.Name,
+		ParentId:  request.ParentId,
+		UpdatedAt: time.Now(),
+	}
+	// проверка выполняется репозиторием в транзакции изменения, ее ошибка возвращается как есть
+	var rejected error
+	check := func(ancestorIds []int64) error {
+		rejected = checkNotDescendant(id, *request.ParentId, ancestorIds)
+		return rejected
+	}
+	err := svc.repo.Update(ctx, entity, check)
+	if rejected != nil {
+		return Response{}, rejected
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("org unit with id %d not found", id)}
+	}
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error updating org unit with id %d", id), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// DeleteById удаляет пустое подразделение: без вложенных подразделений и сотрудников
+func (svc *Service) DeleteById(ctx context.Context, id int64) error {
+	if err := svc.checkOrgUnit(ctx, id); err != nil {
+		return err
+	}
+
+	hasDependents, err := svc.repo.HasDependents(ctx, id)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error checking contents of org unit %d", id), Err: err}
+	}
+	if hasDependents {
+		return common.RequestValidationError{Message: fmt.Sprintf("org unit %d has child units or employees and cannot be deleted", id)}
+	}
+
+	deleted, err := svc.repo.DeleteById(ctx, id)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error deleting org unit with id %d", id), Err: err}
+	}
+	if deleted == 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("org unit with id %d not found", id)}
+	}
+	return nil
+}
+
+// FindMembers возвращает сотрудников подразделения; если recursive, то и всех вложенных подразделений
+func (svc *Service) FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberResponse, error) {
+	if err := svc.checkOrgUnit(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindMembers(ctx, id, recursive)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding members of org unit %d", id), Err: err}
+	}
+
+	responses := make([]MemberResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// FindManagerChain возвращает цепочку руководителей сотрудника от непосредственного до верхнего
+func (svc *Service) FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerResponse, error) {
+	if _, err := svc.findEmployee(ctx, employeeId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindManagerChain(ctx, employeeId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding managers of employee %d", employeeId), Err: err}
+	}
+
+	responses := make([]ManagerResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = ManagerResponse{MemberResponse: entity.toResponse(), Level: entity.Level}
+	}
+	return responses, nil
+}
+
+// Place назначает сотруднику подразделение и руководителя.
+// Руководитель не может быть подчиненным сотрудника (прямо или через цепочку), иначе подчинение замкнется в цикл
+func (svc *Service) Place(ctx context.Context, employeeId int64, request PlacementRequest) (MemberResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return MemberResponse{}, err
+	}
+	if _, err := svc.findEmployee(ctx, employeeId); err != nil {
+		return MemberResponse{}, err
+	}
+	if request.OrgUnitId != nil {
+		if err := svc.checkOrgUnit(ctx, *request.OrgUnitId); err != nil {
+			return MemberResponse{}, err
+		}
+	}
+	if request.ManagerId != nil {
+		if err := svc.checkNewManager(ctx, employeeId, *request.ManagerId); err != nil {
+			return MemberResponse{}, err
+		}
+	}
+
+	// проверка выполняется репозиторием в транзакции назначения, ее ошибка возвращается как есть
+	var rejected error
+	check := func(chain []ManagerEntity) error {
+		rejected = checkNotSubordinate(employeeId, *request.ManagerId, chain)
+		return rejected
+	}
+	entity, err := svc.repo.SetPlacement(ctx, employeeId, request.OrgUnitId, request.ManagerId, time.Now(), check)
+	if rejected != nil {
+		return MemberResponse{}, rejected
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return MemberResponse{}, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", employeeId)}
+	}
+	if err != nil {
+		return MemberResponse{}, common.RepositoryError{Message: fmt.Sprintf("error placing employee %d", employeeId), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// checkOrgUnit проверяет корректность ID и наличие подразделения
+func (svc *Service) checkOrgUnit(ctx context.Context, id int64) error {
+	_, err := svc.FindById(ctx, id)
+	return err
+}
+
+// checkNewParent проверяет, что родитель parentId подразделения id существует и не совпадает с ним самим.
+// То, что parentId не вложен в id, проверяется при изменении (checkNotDescendant)
+func (svc *Service) checkNewParent(ctx context.Context, id, parentId int64) error {
+	if parentId == id {
+		return common.RequestValidationError{Message: fmt.Sprintf("org unit %d cannot be its own parent", id)}
+	}
+	return svc.checkOrgUnit(ctx, parentId)
+}
+
+// checkNotDescendant проверяет, что подразделение id не входит в вышестоящие ancestorIds нового родителя parentId
+func checkNotDescendant(id, parentId int64, ancestorIds []int64) error {
+	if slices.Contains(ancestorIds, id) {
+		return common.RequestValidationError{
+			Message: fmt.Sprintf("org unit %d cannot be moved into its descendant %d", id, parentId),
+		}
+	}
+	return nil
+}
+
+// checkNewManager проверяет, что руководитель managerId существует и не совпадает с сотрудником employeeId.
+// То, что managerId не подчинен сотруднику, проверяется при назначении (checkNotSubordinate)
+func (svc *Service) checkNewManager(ctx context.Context, employeeId, managerId int64) error {
+	if managerId == employeeId {
+		return common.RequestValidationError{Message: fmt.Sprintf("employee %d cannot be their own manager", employeeId)}
+	}
+	_, err := svc.findEmployee(ctx, managerId)
+	return err
+}
+
+// checkNotSubordinate проверяет, что сотрудник employeeId не входит в цепочку руководителей chain нового руководителя managerId
+func checkNotSubordinate(employeeId, managerId int64, chain []ManagerEntity) error {
+	for _, manager := range chain {
+		if manager.Id == employeeId {
+			return common.RequestValidationError{
+				Message: fmt.Sprintf("employee %d cannot be managed by their subordinate %d", employeeId, managerId),
+			}
+		}
+	}
+	return nil
+}
+
+// findEmployee проверяет корректность ID и возвращает неудаленного сотрудника
+func (svc *Service) findEmployee(ctx context.Context, id int64) (MemberEntity, error) {
+	if id <= 0 {
+		return MemberEntity{}, common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindEmployee(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return MemberEntity{}, common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", id)}
+	}
+	if err != nil {
+		return MemberEntity{}, common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", id), Err: err}
+	}
+	return entity, nil
+}
+
+// nameTakenError возвращает ошибку о том, что имя уже занято подразделением того же родителя
+func nameTakenError(name string) error {
+	return common.AlreadyExistsError{Message: fmt.Sprintf("org unit with name '%s' already exists in the same parent unit", name)}
+}
+
+// buildTree собирает дерево из плоского списка подразделений с сохранением порядка списка.
+// Подразделение, родитель которого отсутствует в списке, считается корневым
+func buildTree(entities []Entity) []*TreeResponse {
+	nodes := make(map[int64]*TreeResponse, len(entities))
+	for _, entity := range entities {
+		nodes[entity.Id] = &TreeResponse{Response: entity.toResponse(), Children: []*TreeResponse{}}
+	}
+
+	roots := make([]*TreeResponse, 0)
+	for _, entity := range entities {
+		node := nodes[entity.Id]
+		if entity.ParentId != nil {
+			if parent, ok := nodes[*entity.ParentId]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+// toResponses преобразует список Entity в список Response
+func toResponses(entities []Entity) []Response {
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses
+}
@@ -0,0 +1,302 @@
+package orgunit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория подразделений
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindById(ctx context.Context, id int64) (Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindAll(ctx context.Context) ([]Entity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindChildren(ctx context.Context, id int64) ([]Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+// Update возвращает ошибку из ожидания, а без нее - результат проверки check
+// на вышестоящих подразделениях нового родителя из ожидания
+func (m *MockRepo) Update(ctx context.Context, e *Entity, check ParentCheck) error {
+	args := m.Called(ctx, e)
+	if err := args.Error(1); err != nil || e.ParentId == nil {
+		return err
+	}
+	return check(args.Get(0).([]int64))
+}
+
+func (m *MockRepo) HasDependents(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) DeleteById(ctx context.Context, id int64) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) FindMembers(ctx context.Context, id int64, recursive bool) ([]MemberEntity, error) {
+	args := m.Called(ctx, id, recursive)
+	return args.Get(0).([]MemberEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindEmployee(ctx context.Context, id int64) (MemberEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(MemberEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindManagerChain(ctx context.Context, employeeId int64) ([]ManagerEntity, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]ManagerEntity), args.Error(1)
+}
+
+// SetPlacement возвращает ошибку из ожидания, а без нее - результат проверки check
+// на цепочке руководителей нового руководителя из ожидания
+func (m *MockRepo) SetPlacement(ctx context.Context, employeeId int64, orgUnitId, managerId *int64, at time.Time, check ManagerCheck) (MemberEntity, error) {
+	args := m.Called(ctx, employeeId, orgUnitId, managerId, at)
+	if err := args.Error(2); err != nil {
+		return MemberEntity{}, err
+	}
+	if managerId != nil {
+		if err := check(args.Get(1).([]ManagerEntity)); err != nil {
+			return MemberEntity{}, err
+		}
+	}
+	return args.Get(0).(MemberEntity), nil
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+func TestOrgUnitService_Add(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should add child unit", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Name: "Головной офис"}, nil)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*orgunit.Entity")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*Entity).Id = 2
+		})
+
+		got, err := svc.Add(context.Background(), AddOrgUnitRequest{Name: "Бухгалтерия", ParentId: ptr(1)})
+
+		a.Nil(err)
+		a.Equal(int64(2), got.Id)
+		a.Equal(int64(1), *got.ParentId)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing parent", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(9)).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Add(context.Background(), AddOrgUnitRequest{Name: "Бухгалтерия", ParentId: ptr(9)})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return already exists for duplicate name under parent", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("Add", mock.Anything, mock.Anything).Return(&pq.Error{Code: "23505"})
+
+		_, err := svc.Add(context.Background(), AddOrgUnitRequest{Name: "Бухгалтерия"})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+	})
+}
+
+func TestOrgUnitService_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should move unit under another parent", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(2)).Return(Entity{Id: 2, Name: "Бухгалтерия"}, nil)
+		repo.On("FindById", mock.Anything, int64(3)).Return(Entity{Id: 3, Name: "Финансы"}, nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*orgunit.Entity")).Return([]int64{1}, nil)
+
+		got, err := svc.Update(context.Background(), 2, UpdateOrgUnitRequest{Name: "Бухгалтерия", ParentId: ptr(3)})
+
+		a.Nil(err)
+		a.Equal(int64(3), *got.ParentId)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject unit as its own parent", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(2)).Return(Entity{Id: 2, Name: "Бухгалтерия"}, nil)
+
+		_, err := svc.Update(context.Background(), 2, UpdateOrgUnitRequest{Name: "Бухгалтерия", ParentId: ptr(2)})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject move into descendant", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Name: "Головной офис"}, nil)
+		repo.On("FindById", mock.Anything, int64(4)).Return(Entity{Id: 4, Name: "Расчетная группа"}, nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*orgunit.Entity")).Return([]int64{2, 1}, nil)
+
+		_, err := svc.Update(context.Background(), 1, UpdateOrgUnitRequest{Name: "Головной офис", ParentId: ptr(4)})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Contains(err.Error(), "descendant")
+	})
+}
+
+func TestOrgUnitService_DeleteById(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should reject unit with children or members", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1}, nil)
+		repo.On("HasDependents", mock.Anything, int64(1)).Return(true, nil)
+
+		err := svc.DeleteById(context.Background(), 1)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should delete empty unit", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1}, nil)
+		repo.On("HasDependents", mock.Anything, int64(1)).Return(false, nil)
+		repo.On("DeleteById", mock.Anything, int64(1)).Return(int64(1), nil)
+
+		a.Nil(svc.DeleteById(context.Background(), 1))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestOrgUnitService_FindTree(t *testing.T) {
+	a := assert.New(t)
+	repo := new(MockRepo)
+	svc := NewService(repo, validator.New())
+	repo.On("FindAll", mock.Anything).Return([]Entity{
+		{Id: 1, Name: "Головной офис"},
+		{Id: 2, Name: "Бухгалтерия", ParentId: ptr(1)},
+		{Id: 3, Name: "Склад"},
+		{Id: 4, Name: "Расчетная группа", ParentId: ptr(2)},
+	}, nil)
+
+	tree, err := svc.FindTree(context.Background())
+
+	a.Nil(err)
+	a.Len(tree, 2)
+	a.Equal("Головной офис", tree[0].Name)
+	a.Len(tree[0].Children, 1)
+	a.Equal("Бухгалтерия", tree[0].Children[0].Name)
+	a.Equal("Расчетная группа", tree[0].Children[0].Children[0].Name)
+	a.Empty(tree[1].Children)
+}
+
+func TestOrgUnitService_Place(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should place employee in unit under manager", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindEmployee", mock.Anything, int64(5)).Return(MemberEntity{Id: 5}, nil)
+		repo.On("FindEmployee", mock.Anything, int64(2)).Return(MemberEntity{Id: 2}, nil)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1}, nil)
+		repo.On("SetPlacement", mock.Anything, int64(5), ptr(1), ptr(2), mock.AnythingOfType("time.Time")).
+			Return(MemberEntity{Id: 5, OrgUnitId: ptr(1), ManagerId: ptr(2)}, []ManagerEntity{{MemberEntity: MemberEntity{Id: 1}, Level: 1}}, nil)
+
+		got, err := svc.Place(context.Background(), 5, PlacementRequest{OrgUnitId: ptr(1), ManagerId: ptr(2)})
+
+		a.Nil(err)
+		a.Equal(int64(2), *got.ManagerId)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject employee as own manager", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindEmployee", mock.Anything, int64(5)).Return(MemberEntity{Id: 5}, nil)
+
+		_, err := svc.Place(context.Background(), 5, PlacementRequest{ManagerId: ptr(5)})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+	})
+
+	t.Run("should reject subordinate as manager", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindEmployee", mock.Anything, int64(1)).Return(MemberEntity{Id: 1}, nil)
+		repo.On("FindEmployee", mock.Anything, int64(7)).Return(MemberEntity{Id: 7}, nil)
+		// 7 подчиняется 5, а 5 подчиняется 1
+		repo.On("SetPlacement", mock.Anything, int64(1), (*int64)(nil), ptr(7), mock.AnythingOfType("time.Time")).
+			Return(MemberEntity{}, []ManagerEntity{
+				{MemberEntity: MemberEntity{Id: 5}, Level: 1},
+				{MemberEntity: MemberEntity{Id: 1}, Level: 2},
+			}, nil)
+
+		_, err := svc.Place(context.Background(), 1, PlacementRequest{ManagerId: ptr(7)})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Contains(err.Error(), "subordinate")
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindEmployee", mock.Anything, int64(5)).Return(MemberEntity{}, sql.ErrNoRows)
+
+		_, err := svc.Place(context.Background(), 5, PlacementRequest{})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestOrgUnitService_FindManagerChain(t *testing.T) {
+	a := assert.New(t)
+	repo := new(MockRepo)
+	svc := NewService(repo, validator.New())
+	repo.On("FindEmployee", mock.Anything, int64(7)).Return(MemberEntity{Id: 7}, nil)
+	repo.On("FindManagerChain", mock.Anything, int64(7)).Return([]ManagerEntity{
+		{MemberEntity: MemberEntity{Id: 5, Name: "Руководитель группы"}, Level: 1},
+		{MemberEntity: MemberEntity{Id: 1, Name: "Директор"}, Level: 2},
+	}, nil)
+
+	got, err := svc.FindManagerChain(context.Background(), 7)
+
+	a.Nil(err)
+	a.Len(got, 2)
+	a.Equal("Руководитель группы", got[0].Name)
+	a.Equal(2, got[1].Level)
+}
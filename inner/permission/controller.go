@@ -0,0 +1,400 @@
+package permission
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с правами доступа
+type Controller struct {
+	server            *web.Server
+	permissionService Svc
+	logger            *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с правами доступа
+type Svc interface {
+	Add(ctx context.Context, request AddPermissionRequest) (Response, error)                            // создание права
+	FindById(ctx context.Context, id int64) (Response, error)                                           // право по ID
+	FindAll(ctx context.Context) ([]Response, error)                                                    // все права
+	Update(ctx context.Context, id int64, request UpdatePermissionRequest) (Response, error)            // изменение права
+	DeleteById(ctx context.Context, id int64) error                                                     // удаление права
+	FindByRole(ctx context.Context, roleId int64) ([]Response, error)                                   // права роли
+	GrantToRole(ctx context.Context, roleId int64, request GrantPermissionsRequest) ([]Response, error) // выдача прав роли
+	RevokeFromRole(ctx context.Context, roleId int64, request RevokePermissionsRequest) error           // отзыв прав у роли
+	FindByEmployee(ctx context.Context, employeeId int64) ([]EffectiveResponse, error)                  // действующие права сотрудника
+	Check(ctx context.Context, employeeId int64, request CheckPermissionRequest) (CheckResponse, error) // проверка права сотрудника
+}
+
+// NewController создает новый экземпляр контроллера прав доступа
+func NewController(server *web.Server, permissionService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:            server,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с правами доступа
+func (c *Controller) RegisterRoutes() {
+	api := c.server.GroupApiV1
+
+	// Справочник прав
+	api.Post("/permissions", c.server.Require(web.PermissionWrite), c.CreatePermission)
+	api.Get("/permissions", c.server.Require(web.PermissionRead), c.GetAllPermissions)
+	api.Get("/permissions/:id", c.server.Require(web.PermissionRead), c.GetPermission)
+	api.Put("/permissions/:id", c.server.Require(web.PermissionWrite), c.UpdatePermission)
+	api.Delete("/permissions/:id", c.server.Require(web.PermissionDelete), c.DeletePermission)
+
+	// Права ролей
+	api.Get("/roles/:id/permissions", c.server.Require(web.PermissionRead), c.GetRolePermissions)
+	api.Post("/roles/:id/permissions", c.server.Require(web.PermissionWrite), c.GrantRolePermissions)
+	api.Delete("/roles/:id/permissions", c.server.Require(web.PermissionWrite), c.RevokeRolePermissions)
+
+	// Действующие права сотрудников
+	api.Get("/employees/:id/permissions/check", c.server.Require(web.PermissionRead), c.CheckEmployeePermission)
+	api.Get("/employees/:id/permissions", c.server.Require(web.PermissionRead), c.GetEmployeePermissions)
+}
+
+// CreatePermission создает новое право доступа
+// @Summary Создать право доступа
+// @Description Создать право доступа с уникальным названием
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body permission.AddPermissionRequest true "данные права"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /permissions [post]
+func (c *Controller) CreatePermission(ctx *fiber.Ctx) error {
+	var req AddPermissionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create permission: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.permissionService.Add(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create permission: failed to create permission", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning created permission")
+	}
+	return nil
+}
+
+// GetAllPermissions получает все права доступа
+// @Summary Получить все права доступа
+// @Description Получить список всех прав доступа
+// @Tags permission
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /permissions [get]
+func (c *Controller) GetAllPermissions(ctx *fiber.Ctx) error {
+	resp, err := c.permissionService.FindAll(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get all permissions: failed to find permissions", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning permissions")
+	}
+	return nil
+}
+
+// GetPermission получает право доступа по ID
+// @Summary Получить право доступа по ID
+// @Description Получить право доступа по его идентификатору
+// @Tags permission
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID права"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /permissions/{id} [get]
+func (c *Controller) GetPermission(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid permission id")
+	}
+
+	resp, err := c.permissionService.FindById(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get permission: failed to find permission", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning permission")
+	}
+	return nil
+}
+
+// UpdatePermission изменяет право доступа
+// @Summary Изменить право доступа
+// @Description Изменить название и описание права доступа
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID права"
+// @Param request body permission.UpdatePermissionRequest true "данные права"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /permissions/{id} [put]
+func (c *Controller) UpdatePermission(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid permission id")
+	}
+
+	var req UpdatePermissionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update permission: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.permissionService.Update(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update permission: failed to update permission", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning updated permission")
+	}
+	return nil
+}
+
+// DeletePermission удаляет право доступа
+// @Summary Удалить право доступа
+// @Description Удалить право доступа и все его выдачи ролям
+// @Tags permission
+// @Security BearerAuth
+// @Param id path int true "ID права"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /permissions/{id} [delete]
+func (c *Controller) DeletePermission(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid permission id")
+	}
+
+	if err := c.permissionService.DeleteById(ctx.Context(), id); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "delete permission: failed to delete permission", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// GetRolePermissions получает права роли
+// @Summary Получить права роли
+// @Description Получить список прав, выданных роли
+// @Tags permission
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID роли"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/permissions [get]
+func (c *Controller) GetRolePermissions(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	resp, err := c.permissionService.FindByRole(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get role permissions: failed to find permissions", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning role permissions")
+	}
+	return nil
+}
+
+// GrantRolePermissions выдает роли права
+// @Summary Выдать права роли
+// @Description Выдать роли права по списку идентификаторов
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID роли"
+// @Param request body permission.GrantPermissionsRequest true "список ID прав"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/permissions [post]
+func (c *Controller) GrantRolePermissions(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	var req GrantPermissionsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "grant permissions: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.permissionService.GrantToRole(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "grant permissions: failed to grant permissions", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning role permissions")
+	}
+	return nil
+}
+
+// RevokeRolePermissions отзывает права у роли
+// @Summary Отозвать права у роли
+// @Description Отозвать у роли права по списку идентификаторов
+// @Tags permission
+// @Accept json
+// @Security BearerAuth
+// @Param id path int true "ID роли"
+// @Param request body permission.RevokePermissionsRequest true "список ID прав"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/permissions [delete]
+func (c *Controller) RevokeRolePermissions(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	var req RevokePermissionsRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "revoke permissions: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := c.permissionService.RevokeFromRole(ctx.Context(), id, req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "revoke permissions: failed to revoke permissions", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// GetEmployeePermissions получает действующие права сотрудника
+// @Summary Получить права сотрудника
+// @Description Получить права, которые сотрудник получает через назначенные ему роли и их родительские роли
+// @Tags permission
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/permissions [get]
+func (c *Controller) GetEmployeePermissions(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	resp, err := c.permissionService.FindByEmployee(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get employee permissions: failed to find permissions", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning employee permissions")
+	}
+	return nil
+}
+
+// CheckEmployeePermission проверяет наличие права у сотрудника
+// @Summary Проверить право сотрудника
+// @Description Проверить, получает ли сотрудник право с заданным названием через свои роли
+// @Tags permission
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID сотрудника"
+// @Param permission query string true "название права"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /employees/{id}/permissions/check [get]
+func (c *Controller) CheckEmployeePermission(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid employee id")
+	}
+
+	resp, err := c.permissionService.Check(ctx.Context(), id, CheckPermissionRequest{Permission: ctx.Query("permission")})
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "check employee permission: failed to check permission", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning permission check")
+	}
+	return nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.AlreadyExistsError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package permission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPermissionService - полный мок для интерфейса Svc
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Add(ctx context.Context, request AddPermissionRequest) (Response, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockPermissionService) FindById(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockPermissionService) FindAll(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockPermissionService) Update(ctx context.Context, id int64, request UpdatePermissionRequest) (Response, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockPermissionService) DeleteById(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPermissionService) FindByRole(ctx context.Context, roleId int64) ([]Response, error) {
+	args := m.Called(ctx, roleId)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockPermissionService) GrantToRole(ctx context.Context, roleId int64, request GrantPermissionsRequest) ([]Response, error) {
+	args := m.Called(ctx, roleId, request)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockPermissionService) RevokeFromRole(ctx context.Context, roleId int64, request RevokePermissionsRequest) error {
+	args := m.Called(ctx, roleId, request)
+	return args.Error(0)
+}
+
+func (m *MockPermissionService) FindByEmployee(ctx context.Context, employeeId int64) ([]EffectiveResponse, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]EffectiveResponse), args.Error(1)
+}
+
+func (m *MockPermissionService) Check(ctx context.Context, employeeId int64, request CheckPermissionRequest) (CheckResponse, error) {
+	args := m.Called(ctx, employeeId, request)
+	return args.Get(0).(CheckResponse), args.Error(1)
+}
+
+// setupTest инициализирует тестовое окружение
+func setupTest(t *testing.T) (*fiber.App, *MockPermissionService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockPermissionService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestCreatePermission(t *testing.T) {
+	t.Run("should create permission for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AddPermissionRequest{Name: "payroll:read"}
+		svc.On("Add", mock.Anything, request).Return(Response{Id: 1, Name: "payroll:read"}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/permissions", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 409 for duplicate name", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AddPermissionRequest{Name: "payroll:read"}
+		svc.On("Add", mock.Anything, request).Return(Response{}, common.AlreadyExistsError{Message: "permission with name 'payroll:read' already exists"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/permissions", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/permissions", AddPermissionRequest{Name: "payroll:read"}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestDeletePermission(t *testing.T) {
+	t.Run("should return 404 for missing permission", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("DeleteById", mock.Anything, int64(4)).Return(common.NotFoundError{Message: "permission with id 4 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/permissions/4", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
+
+func TestGrantRolePermissions(t *testing.T) {
+	t.Run("should grant permissions to role", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := GrantPermissionsRequest{PermissionIds: []int64{1, 2}}
+		expected := []Response{{Id: 1, Name: "a:read"}, {Id: 2, Name: "b:read"}}
+		svc.On("GrantToRole", mock.Anything, int64(3), request).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/3/permissions", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]Response]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, expected, result.Data)
+	})
+
+	t.Run("should revoke permissions from role", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := RevokePermissionsRequest{PermissionIds: []int64{1}}
+		svc.On("RevokeFromRole", mock.Anything, int64(3), request).Return(nil)
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/roles/3/permissions", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 204, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+}
+
+func TestGetEmployeePermissions(t *testing.T) {
+	t.Run("should return effective permissions for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+		expected := []EffectiveResponse{{Id: 1, Name: "payroll:read", Roles: []string{"accountant"}}}
+		svc.On("FindByEmployee", mock.Anything, int64(7)).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/7/permissions", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]EffectiveResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, expected, result.Data)
+	})
+
+	t.Run("should check single permission", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Check", mock.Anything, int64(7), CheckPermissionRequest{Permission: "payroll:read"}).
+			Return(CheckResponse{EmployeeId: 7, Permission: "payroll:read", Allowed: true}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/7/permissions/check?permission=payroll:read", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[CheckResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.True(t, result.Data.Allowed)
+	})
+
+	t.Run("should return 400 for invalid employee id", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/employees/abc/permissions", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
@@ -0,0 +1,69 @@
+package permission
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Entity представляет право доступа в базе данных
+type Entity struct {
+	Id          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// toResponse преобразует Entity в Response
+func (e *Entity) toResponse() Response {
+	return Response{
+		Id:          e.Id,
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+// EffectiveEntity представляет право сотрудника вместе с ролями, через которые оно получено
+type EffectiveEntity struct {
+	Id          int64          `db:"id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Roles       pq.StringArray `db:"roles"`
+}
+
+// toResponse преобразует EffectiveEntity в EffectiveResponse
+func (e *EffectiveEntity) toResponse() EffectiveResponse {
+	return EffectiveResponse{
+		Id:          e.Id,
+		Name:        e.Name,
+		Description: e.Description,
+		Roles:       e.Roles,
+	}
+}
+
+// Response представляет ответ API для права доступа
+type Response struct {
+	Id          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EffectiveResponse представляет действующее право сотрудника
+type EffectiveResponse struct {
+	Id          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Roles       []string `json:"roles"`
+}
+
+// CheckResponse представляет результат проверки права сотрудника
+type CheckResponse struct {
+	EmployeeId int64  `json:"employee_id"`
+	Permission string `json:"permission"`
+	Allowed    bool   `json:"allowed"`
+}
@@ -0,0 +1,151 @@
+package permission
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Repository представляет репозиторий для работы с правами доступа
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// Add сохраняет новое право доступа
+func (r *Repository) Add(ctx context.Context, e *Entity) error {
+	query := `INSERT INTO permission (name, description, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.Description, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+}
+
+// FindById возвращает право доступа по ID
+func (r *Repository) FindById(ctx context.Context, id int64) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT * FROM permission WHERE id = $1", id)
+	return res, err
+}
+
+// FindByName возвращает право доступа по названию
+func (r *Repository) FindByName(ctx context.Context, name string) (res Entity, err error) {
+	err = r.db.GetContext(ctx, &res, "SELECT * FROM permission WHERE name = $1", name)
+	return res, err
+}
+
+// FindAll возвращает все права доступа
+func (r *Repository) FindAll(ctx context.Context) (res []Entity, err error) {
+	err = r.db.SelectContext(ctx, &res, "SELECT * FROM permission ORDER BY name")
+	return res, err
+}
+
+// Update изменяет право доступа. Возвращает sql.ErrNoRows, если право не найдено
+func (r *Repository) Update(ctx context.Context, e *Entity) error {
+	query := `UPDATE permission SET name = $1, description = $2, updated_at = $3 WHERE id = $4 RETURNING created_at`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.Description, e.UpdatedAt, e.Id).Scan(&e.CreatedAt)
+}
+
+// DeleteById удаляет право доступа вместе с его выдачами ролям, возвращает количество удаленных строк
+func (r *Repository) DeleteById(ctx context.Context, id int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM permission WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FindExistingIds возвращает те ID из списка, для которых права существуют
+func (r *Repository) FindExistingIds(ctx context.Context, ids []int64) ([]int64, error) {
+	var res []int64
+	err := r.db.SelectContext(ctx, &res, "SELECT id FROM permission WHERE id = ANY($1)", pq.Array(ids))
+	return res, err
+}
+
+// RoleExists проверяет наличие неудаленной роли с заданным ID
+func (r *Repository) RoleExists(ctx context.Context, roleId int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM role WHERE id = $1 AND deleted_at IS NULL)", roleId)
+	return exists, err
+}
+
+// EmployeeExists проверяет наличие неудаленного сотрудника с заданным ID
+func (r *Repository) EmployeeExists(ctx context.Context, employeeId int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM employee WHERE id = $1 AND deleted_at IS NULL)", employeeId)
+	return exists, err
+}
+
+// FindByRoleId возвращает права, выданные роли
+func (r *Repository) FindByRoleId(ctx context.Context, roleId int64) ([]Entity, error) {
+	query := `SELECT p.*
+		FROM role_permission rp
+		JOIN permission p ON p.id = rp.permission_id
+		WHERE rp.role_id = $1
+		ORDER BY p.name`
+	var res []Entity
+	err := r.db.SelectContext(ctx, &res, query, roleId)
+	return res, err
+}
+
+// Grant выдает роли права; уже выданные права не изменяются
+func (r *Repository) Grant(ctx context.Context, roleId int64, permissionIds []int64, at time.Time) error {
+	query := `INSERT INTO role_permission (role_id, permission_id, created_at)
+		SELECT $1, permission_id, $3 FROM unnest($2::bigint[]) AS permission_id
+		ON CONFLICT (role_id, permission_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, roleId, pq.Array(permissionIds), at)
+	return err
+}
+
+// Revoke отзывает права у роли
+func (r *Repository) Revoke(ctx context.Context, roleId int64, permissionIds []int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM role_permission WHERE role_id = $1 AND permission_id = ANY($2)", roleId, pq.Array(permissionIds))
+	return err
+}
+
+// effectiveRolesCTE - рекурсивный подзапрос ролей сотрудника $1: назначенные роли и все их предки по иерархии.
+// Удаленные роли и назначения вне срока действия не действуют и не передают наследование родителей
+const effectiveRolesCTE = `WITH RECURSIVE effective_role AS (
+		SELECT er.role_id
+		FROM employee_role er JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+		WHERE er.employee_id = $1
+			AND (er.valid_from IS NULL OR er.valid_from <= now()) AND (er.valid_until IS NULL OR er.valid_until > now())
+		UNION
+		SELECT rp.parent_id
+		FROM role_parent rp
+		JOIN effective_role er ON rp.role_id = er.role_id
+		JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+	)`
+
+// FindByEmployeeId возвращает действующие права сотрудника, полученные через назначенные ему роли
+// и роли, от которых они наследуются
+func (r *Repository) FindByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveEntity, error) {
+	query := effectiveRolesCTE + `
+		SELECT p.id, p.name, p.description, array_agg(DISTINCT r.name ORDER BY r.name) AS roles
+		FROM effective_role er
+		JOIN role r ON r.id = er.role_id
+		JOIN role_permission rp ON rp.role_id = er.role_id
+		JOIN permission p ON p.id = rp.permission_id
+		GROUP BY p.id, p.name, p.description
+		ORDER BY p.name`
+	var res []EffectiveEntity
+	err := r.db.SelectContext(ctx, &res, query, employeeId)
+	return res, err
+}
+
+// HasPermission проверяет, получено ли сотрудником право с заданным названием хотя бы через одну роль
+// с учетом иерархии ролей
+func (r *Repository) HasPermission(ctx context.Context, employeeId int64, name string) (bool, error) {
+	query := effectiveRolesCTE + `
+		SELECT EXISTS(
+			SELECT 1
+			FROM effective_role er
+			JOIN role_permission rp ON rp.role_id = er.role_id
+			JOIN permission p ON p.id = rp.permission_id
+			WHERE p.name = $2)`
+	var allowed bool
+	err := r.db.GetContext(ctx, &allowed, query, employeeId, name)
+	return allowed, err
+}
@@ -0,0 +1,28 @@
+package permission
+
+// AddPermissionRequest используется для создания права доступа
+type AddPermissionRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// UpdatePermissionRequest используется для изменения права доступа
+type UpdatePermissionRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// GrantPermissionsRequest используется для выдачи прав роли
+type GrantPermissionsRequest struct {
+	PermissionIds []int64 `json:"permission_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// RevokePermissionsRequest используется для отзыва прав у роли
+type RevokePermissionsRequest struct {
+	PermissionIds []int64 `json:"permission_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// CheckPermissionRequest используется для проверки права сотрудника
+type CheckPermissionRequest struct {
+	Permission string `json:"permission" validate:"required,min=2,max=100"`
+}
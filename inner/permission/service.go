@@ -0,0 +1,312 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"slices"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику прав доступа
+type Service struct {
+	repo      Repo
+	validator Validator
+}
+
+// Repo интерфейс репозитория для прав доступа
+type Repo interface {
+	Add(ctx context.Context, e *Entity) error
+	FindById(ctx context.Context, id int64) (Entity, error)
+	FindByName(ctx context.Context, name string) (Entity, error)
+	FindAll(ctx context.Context) ([]Entity, error)
+	Update(ctx context.Context, e *Entity) error
+	DeleteById(ctx context.Context, id int64) (int64, error)
+	FindExistingIds(ctx context.Context, ids []int64) ([]int64, error)
+	RoleExists(ctx context.Context, roleId int64) (bool, error)
+	EmployeeExists(ctx context.Context, employeeId int64) (bool, error)
+	FindByRoleId(ctx context.Context, roleId int64) ([]Entity, error)
+	Grant(ctx context.Context, roleId int64, permissionIds []int64, at time.Time) error
+	Revoke(ctx context.Context, roleId int64, permissionIds []int64) error
+	FindByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveEntity, error)
+	HasPermission(ctx context.Context, employeeId int64, name string) (bool, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	err := svc.validator.ValidateWithCustomMessages(request)
+	if err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Add создает новое право доступа с уникальным названием
+func (svc *Service) Add(ctx context.Context, request AddPermissionRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkNameIsFree(ctx, request.Name, 0); err != nil {
+		return Response{}, err
+	}
+
+	now := time.Now()
+	entity := &Entity{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err := svc.repo.Add(ctx, entity)
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: "error adding permission", Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindById возвращает право доступа по ID
+func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid permission id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("permission with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error finding permission with id %d", id), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindAll возвращает все права доступа
+func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding all permissions", Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// Update изменяет название и описание права доступа
+func (svc *Service) Update(ctx context.Context, id int64, request UpdatePermissionRequest) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid permission id: %d", id)}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkNameIsFree(ctx, request.Name, id); err != nil {
+		return Response{}, err
+	}
+
+	entity := &Entity{
+		Id:          id,
+		Name:        request.Name,
+		Description: request.Description,
+		UpdatedAt:   time.Now(),
+	}
+	err := svc.repo.Update(ctx, entity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("permission with id %d not found", id)}
+	}
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error updating permission with id %d", id), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// DeleteById удаляет право доступа; выдачи этого права ролям удаляются вместе с ним
+func (svc *Service) DeleteById(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid permission id: %d", id)}
+	}
+
+	deleted, err := svc.repo.DeleteById(ctx, id)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error deleting permission with id %d", id), Err: err}
+	}
+	if deleted == 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("permission with id %d not found", id)}
+	}
+	return nil
+}
+
+// FindByRole возвращает права, выданные роли
+func (svc *Service) FindByRole(ctx context.Context, roleId int64) ([]Response, error) {
+	if err := svc.checkRole(ctx, roleId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindByRoleId(ctx, roleId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding permissions of role %d", roleId), Err: err}
+	}
+	return toResponses(entities), nil
+}
+
+// GrantToRole выдает роли права и возвращает ее актуальный список прав
+func (svc *Service) GrantToRole(ctx context.Context, roleId int64, request GrantPermissionsRequest) ([]Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return nil, err
+	}
+	if err := svc.checkRole(ctx, roleId); err != nil {
+		return nil, err
+	}
+
+	existing, err := svc.repo.FindExistingIds(ctx, request.PermissionIds)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding permissions by ids", Err: err}
+	}
+	if missing := missingIds(request.PermissionIds, existing); len(missing) > 0 {
+		return nil, common.NotFoundError{Message: fmt.Sprintf("permissions not found: %v", missing)}
+	}
+
+	if err := svc.repo.Grant(ctx, roleId, request.PermissionIds, time.Now()); err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error granting permissions to role %d", roleId), Err: err}
+	}
+
+	return svc.FindByRole(ctx, roleId)
+}
+
+// RevokeFromRole отзывает права у роли
+func (svc *Service) RevokeFromRole(ctx context.Context, roleId int64, request RevokePermissionsRequest) error {
+	if err := svc.ValidateRequest(request); err != nil {
+		return err
+	}
+	if err := svc.checkRole(ctx, roleId); err != nil {
+		return err
+	}
+
+	if err := svc.repo.Revoke(ctx, roleId, request.PermissionIds); err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error revoking permissions from role %d", roleId), Err: err}
+	}
+	return nil
+}
+
+// FindByEmployee возвращает действующие права сотрудника, полученные через его роли (включая унаследованные)
+func (svc *Service) FindByEmployee(ctx context.Context, employeeId int64) ([]EffectiveResponse, error) {
+	if err := svc.checkEmployee(ctx, employeeId); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindByEmployeeId(ctx, employeeId)
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error finding permissions of employee %d", employeeId), Err: err}
+	}
+
+	responses := make([]EffectiveResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// Check проверяет, есть ли у сотрудника право с заданным названием
+func (svc *Service) Check(ctx context.Context, employeeId int64, request CheckPermissionRequest) (CheckResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return CheckResponse{}, err
+	}
+	if err := svc.checkEmployee(ctx, employeeId); err != nil {
+		return CheckResponse{}, err
+	}
+
+	allowed, err := svc.repo.HasPermission(ctx, employeeId, request.Permission)
+	if err != nil {
+		return CheckResponse{}, common.RepositoryError{Message: fmt.Sprintf("error checking permission of employee %d", employeeId), Err: err}
+	}
+	return CheckResponse{EmployeeId: employeeId, Permission: request.Permission, Allowed: allowed}, nil
+}
+
+// checkNameIsFree проверяет, что название не занято другим правом (кроме права с ID exceptId)
+func (svc *Service) checkNameIsFree(ctx context.Context, name string, exceptId int64) error {
+	existing, err := svc.repo.FindByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding permission with name '%s'", name), Err: err}
+	}
+	if existing.Id != exceptId {
+		return nameTakenError(name)
+	}
+	return nil
+}
+
+// nameTakenError возвращает ошибку о том, что название уже занято другим правом
+func nameTakenError(name string) error {
+	return common.AlreadyExistsError{Message: fmt.Sprintf("permission with name '%s' already exists", name)}
+}
+
+// checkRole проверяет корректность ID и наличие роли
+func (svc *Service) checkRole(ctx context.Context, roleId int64) error {
+	if roleId <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid role id: %d", roleId)}
+	}
+
+	exists, err := svc.repo.RoleExists(ctx, roleId)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding role with id %d", roleId), Err: err}
+	}
+	if !exists {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", roleId)}
+	}
+	return nil
+}
+
+// checkEmployee проверяет корректность ID и наличие сотрудника
+func (svc *Service) checkEmployee(ctx context.Context, employeeId int64) error {
+	if employeeId <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid employee id: %d", employeeId)}
+	}
+
+	exists, err := svc.repo.EmployeeExists(ctx, employeeId)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error finding employee with id %d", employeeId), Err: err}
+	}
+	if !exists {
+		return common.NotFoundError{Message: fmt.Sprintf("employee with id %d not found", employeeId)}
+	}
+	return nil
+}
+
+// toResponses преобразует список Entity в список Response
+func toResponses(entities []Entity) []Response {
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses
+}
+
+// missingIds возвращает ID из requested, которых нет в found
+func missingIds(requested, found []int64) []int64 {
+	var missing []int64
+	for _, id := range requested {
+		if !slices.Contains(found, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
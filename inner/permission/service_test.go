@@ -0,0 +1,279 @@
+package permission
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория прав доступа
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Add(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindById(ctx context.Context, id int64) (Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindByName(ctx context.Context, name string) (Entity, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindAll(ctx context.Context) ([]Entity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Update(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockRepo) DeleteById(ctx context.Context, id int64) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) FindExistingIds(ctx context.Context, ids []int64) ([]int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepo) RoleExists(ctx context.Context, roleId int64) (bool, error) {
+	args := m.Called(ctx, roleId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) EmployeeExists(ctx context.Context, employeeId int64) (bool, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) FindByRoleId(ctx context.Context, roleId int64) ([]Entity, error) {
+	args := m.Called(ctx, roleId)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Grant(ctx context.Context, roleId int64, permissionIds []int64, at time.Time) error {
+	args := m.Called(ctx, roleId, permissionIds, at)
+	return args.Error(0)
+}
+
+func (m *MockRepo) Revoke(ctx context.Context, roleId int64, permissionIds []int64) error {
+	args := m.Called(ctx, roleId, permissionIds)
+	return args.Error(0)
+}
+
+func (m *MockRepo) FindByEmployeeId(ctx context.Context, employeeId int64) ([]EffectiveEntity, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]EffectiveEntity), args.Error(1)
+}
+
+func (m *MockRepo) HasPermission(ctx context.Context, employeeId int64, name string) (bool, error) {
+	args := m.Called(ctx, employeeId, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestPermissionService_Add(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should add permission with free name", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindByName", mock.Anything, "payroll:read").Return(Entity{}, sql.ErrNoRows)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*permission.Entity")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*Entity).Id = 5
+		})
+
+		got, err := svc.Add(context.Background(), AddPermissionRequest{Name: "payroll:read", Description: "просмотр ведомостей"})
+
+		a.Nil(err)
+		a.Equal(int64(5), got.Id)
+		a.Equal("payroll:read", got.Name)
+		a.Equal("просмотр ведомостей", got.Description)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject duplicate name", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindByName", mock.Anything, "payroll:read").Return(Entity{Id: 1, Name: "payroll:read"}, nil)
+
+		_, err := svc.Add(context.Background(), AddPermissionRequest{Name: "payroll:read"})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+		repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject name taken by a concurrent request", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindByName", mock.Anything, "payroll:read").Return(Entity{}, sql.ErrNoRows)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*permission.Entity")).Return(&pq.Error{Code: "23505"})
+
+		_, err := svc.Add(context.Background(), AddPermissionRequest{Name: "payroll:read"})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+	})
+
+	t.Run("should reject invalid request", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Add(context.Background(), AddPermissionRequest{Name: ""})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
+
+func TestPermissionService_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should allow keeping own name", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindByName", mock.Anything, "payroll:read").Return(Entity{Id: 3, Name: "payroll:read"}, nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*permission.Entity")).Return(nil)
+
+		got, err := svc.Update(context.Background(), 3, UpdatePermissionRequest{Name: "payroll:read", Description: "новое описание"})
+
+		a.Nil(err)
+		a.Equal("новое описание", got.Description)
+	})
+
+	t.Run("should return not found for missing permission", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindByName", mock.Anything, "payroll:read").Return(Entity{}, sql.ErrNoRows)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*permission.Entity")).Return(sql.ErrNoRows)
+
+		_, err := svc.Update(context.Background(), 3, UpdatePermissionRequest{Name: "payroll:read"})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestPermissionService_DeleteById(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return not found when nothing deleted", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteById", mock.Anything, int64(9)).Return(int64(0), nil)
+
+		err := svc.DeleteById(context.Background(), 9)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestPermissionService_GrantToRole(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should grant permissions and return role permissions", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("RoleExists", mock.Anything, int64(2)).Return(true, nil)
+		repo.On("FindExistingIds", mock.Anything, []int64{1, 3}).Return([]int64{1, 3}, nil)
+		repo.On("Grant", mock.Anything, int64(2), []int64{1, 3}, mock.AnythingOfType("time.Time")).Return(nil)
+		repo.On("FindByRoleId", mock.Anything, int64(2)).Return([]Entity{{Id: 1, Name: "a:read"}, {Id: 3, Name: "b:read"}}, nil)
+
+		got, err := svc.GrantToRole(context.Background(), 2, GrantPermissionsRequest{PermissionIds: []int64{1, 3}})
+
+		a.Nil(err)
+		a.Len(got, 2)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should report missing permissions", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("RoleExists", mock.Anything, int64(2)).Return(true, nil)
+		repo.On("FindExistingIds", mock.Anything, []int64{1, 3}).Return([]int64{1}, nil)
+
+		_, err := svc.GrantToRole(context.Background(), 2, GrantPermissionsRequest{PermissionIds: []int64{1, 3}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		a.Contains(err.Error(), "[3]")
+		repo.AssertNotCalled(t, "Grant", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("RoleExists", mock.Anything, int64(2)).Return(false, nil)
+
+		_, err := svc.GrantToRole(context.Background(), 2, GrantPermissionsRequest{PermissionIds: []int64{1}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestPermissionService_FindByEmployee(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return effective permissions with granting roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("EmployeeExists", mock.Anything, int64(7)).Return(true, nil)
+		repo.On("FindByEmployeeId", mock.Anything, int64(7)).
+			Return([]EffectiveEntity{{Id: 1, Name: "payroll:read", Roles: []string{"accountant", "hr"}}}, nil)
+
+		got, err := svc.FindByEmployee(context.Background(), 7)
+
+		a.Nil(err)
+		a.Equal([]EffectiveResponse{{Id: 1, Name: "payroll:read", Roles: []string{"accountant", "hr"}}}, got)
+	})
+
+	t.Run("should return not found for missing employee", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("EmployeeExists", mock.Anything, int64(7)).Return(false, nil)
+
+		_, err := svc.FindByEmployee(context.Background(), 7)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestPermissionService_Check(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should report whether employee has permission", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("EmployeeExists", mock.Anything, int64(7)).Return(true, nil)
+		repo.On("HasPermission", mock.Anything, int64(7), "payroll:read").Return(true, nil)
+
+		got, err := svc.Check(context.Background(), 7, CheckPermissionRequest{Permission: "payroll:read"})
+
+		a.Nil(err)
+		a.Equal(CheckResponse{EmployeeId: 7, Permission: "payroll:read", Allowed: true}, got)
+	})
+
+	t.Run("should require permission name", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Check(context.Background(), 7, CheckPermissionRequest{})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+}
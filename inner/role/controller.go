@@ -19,12 +19,24 @@ type Controller struct {
 // интерфейс сервиса role.Service
 type Svc interface {
 	FindById(ctx context.Context, id int64) (Response, error)
-	Add(ctx context.Context, name string) (Response, error)
+	Add(ctx context.Context, request AddRoleRequest) (Response, error)
 	FindAll(ctx context.Context) ([]Response, error)
 	FindByIds(ctx context.Context, ids []int64) ([]Response, error)
+	Update(ctx context.Context, id int64, request UpdateRoleRequest, version int64) (Response, error)
 	DeleteById(ctx context.Context, id int64) error
+	DeleteByIdIfMatch(ctx context.Context, id, version int64) error
 	DeleteByIds(ctx context.Context, ids []int64) error
 	ValidateRequest(request any) error
+	FindParents(ctx context.Context, id int64) ([]Response, error)
+	FindChildren(ctx context.Context, id int64) ([]Response, error)
+	AddParents(ctx context.Context, id int64, request AddParentsRequest) ([]Response, error)
+	RemoveParents(ctx context.Context, id int64, request RemoveParentsRequest) error
+	FindAllWithDeleted(ctx context.Context) ([]Response, error)
+	FindByFilter(ctx context.Context, request FindAllRequest) ([]Response, error)
+	FindPage(ctx context.Context, request PageRequest) (PageResponse, error)
+	FindAfter(ctx context.Context, request CursorRequest) (CursorResponse, error)
+	Restore(ctx context.Context, id int64) (Response, error)
+	PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error)
 }
 
 func NewController(server *web.Server, roleService Svc, logger *common.Logger) *Controller {
@@ -37,27 +49,37 @@ func NewController(server *web.Server, roleService Svc, logger *common.Logger) *
 
 // функция для регистрации маршрутов
 func (c *Controller) RegisterRoutes() {
-	// Маршруты для администраторов (создание, изменение, удаление)
-	c.server.GroupApiV1.Post("/roles", web.RequireRoles(web.IdmAdmin), c.CreateRole)
-	c.server.GroupApiV1.Delete("/roles/:id", web.RequireRoles(web.IdmAdmin), c.DeleteRole)
-	c.server.GroupApiV1.Delete("/roles", web.RequireRoles(web.IdmAdmin), c.DeleteRolesByIds)
-
-	// Маршруты для администраторов и пользователей (чтение)
-	c.server.GroupApiV1.Get("/roles/:id", web.RequireRoles(web.IdmAdmin, web.IdmUser), c.GetRole)
-	c.server.GroupApiV1.Get("/roles", web.RequireRoles(web.IdmAdmin, web.IdmUser), c.GetAllRoles)
-	c.server.GroupApiV1.Post("/roles/by-ids", web.RequireRoles(web.IdmAdmin, web.IdmUser), c.GetRolesByIds)
+	// Маршруты изменения ролей (по умолчанию политика разрешает их только администраторам)
+	c.server.GroupApiV1.Post("/roles", c.server.Require(web.RoleWrite), c.CreateRole)
+	c.server.GroupApiV1.Put("/roles/:id", c.server.Require(web.RoleWrite), c.UpdateRole)
+	c.server.GroupApiV1.Delete("/roles/:id", c.server.Require(web.RoleDelete), c.DeleteRole)
+	c.server.GroupApiV1.Delete("/roles", c.server.Require(web.RoleDelete), c.DeleteRolesByIds)
+	c.server.GroupApiV1.Post("/roles/:id/parents", c.server.Require(web.RoleWrite), c.AddRoleParents)
+	c.server.GroupApiV1.Delete("/roles/:id/parents", c.server.Require(web.RoleWrite), c.RemoveRoleParents)
+	c.server.GroupApiV1.Post("/roles/:id/restore", c.server.Require(web.RoleRestore), c.RestoreRole)
+	c.server.GroupApiV1.Post("/roles/purge", c.server.Require(web.RoleDelete), c.PurgeDeletedRoles)
+
+	// Маршруты чтения (по умолчанию доступны администраторам и пользователям)
+	c.server.GroupApiV1.Get("/roles/page", c.server.Require(web.RoleRead), c.GetRolesPage)
+	c.server.GroupApiV1.Get("/roles/:id", c.server.Require(web.RoleRead), c.GetRole)
+	c.server.GroupApiV1.Get("/roles", c.server.Require(web.RoleRead), c.GetAllRoles)
+	c.server.GroupApiV1.Post("/roles/by-ids", c.server.Require(web.RoleRead), c.GetRolesByIds)
+	c.server.GroupApiV1.Get("/roles/:id/parents", c.server.Require(web.RoleRead), c.GetRoleParents)
+	c.server.GroupApiV1.Get("/roles/:id/children", c.server.Require(web.RoleRead), c.GetRoleChildren)
 }
 
 // функция-хендлер, которая будет вызываться при POST запросе по маршруту "/api/v1/roles"
 // CreateRole создаёт новую роль
 // @Summary Создать новую роль
-// @Description Создать новую роль
+// @Description Создать новую роль с описанием, владельцем, уровнем риска, признаком requestable и приложением
 // @Tags role
 // @Accept json
 // @Produce json
 // @Param request body role.AddRoleRequest true "create role request"
 // @Success 200 {object} common.ResponseExample
 // @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample "Владелец роли не найден"
+// @Failure 409 {object} common.ResponseExample "Conflict"
 // @Router /roles [post]
 func (c *Controller) CreateRole(ctx *fiber.Ctx) error {
 	// анмаршалим JSON body запроса в структуру AddRoleRequest
@@ -72,14 +94,22 @@ func (c *Controller) CreateRole(ctx *fiber.Ctx) error {
 	}
 
 	// вызываем метод Add сервиса role.Service
-	response, err := c.roleService.Add(ctx.Context(), request.Name)
+	response, err := c.roleService.Add(ctx.Context(), request)
 	if err != nil {
 		switch {
-		// если сервис возвращает ошибку RequestValidationError или AlreadyExistsError,
+		// если владелец роли не найден, то мы возвращаем ответ с кодом 404 (NotFound)
+		case errors.As(err, &common.NotFoundError{}):
+			c.logger.ErrorCtx(ctx.Context(), "create role: owner not found")
+			return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+		// если сервис возвращает ошибку RequestValidationError,
 		// то мы возвращаем ответ с кодом 400 (BadRequest)
-		case errors.As(err, &common.RequestValidationError{}) || errors.As(err, &common.AlreadyExistsError{}):
-			c.logger.ErrorCtx(ctx.Context(), "create role: validation or already exists error")
+		case errors.As(err, &common.RequestValidationError{}):
+			c.logger.ErrorCtx(ctx.Context(), "create role: validation error")
 			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+		// если роль с таким именем уже есть, то мы возвращаем ответ с кодом 409 (Conflict)
+		case errors.As(err, &common.AlreadyExistsError{}):
+			c.logger.ErrorCtx(ctx.Context(), "create role: already exists")
+			return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
 		// если сервис возвращает другую ошибку, то мы возвращаем ответ с кодом 500 (InternalServerError)
 		default:
 			c.logger.ErrorCtx(ctx.Context(), "create role: internal error")
@@ -96,6 +126,77 @@ func (c *Controller) CreateRole(ctx *fiber.Ctx) error {
 	return nil
 }
 
+// функция-хендлер для обновления роли по ID
+// UpdateRole обновляет роль
+// @Summary Обновить роль
+// @Description Обновить имя и описательные данные роли по идентификатору
+// @Tags role
+// @Accept json
+// @Produce json
+// @Param id path int true "ID роли"
+// @Param If-Match header string false "ETag версии, которую изменяет клиент"
+// @Param request body role.UpdateRoleRequest true "update role request"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Failure 412 {object} common.ResponseExample "Precondition Failed"
+// @Router /roles/{id} [put]
+func (c *Controller) UpdateRole(ctx *fiber.Ctx) error {
+	// получаем ID из параметра маршрута
+	idParam := ctx.Params("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "update role: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	// получаем ожидаемую версию роли из заголовка If-Match
+	version, err := web.IfMatchVersion(ctx)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update role: invalid If-Match header")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// анмаршалим JSON body запроса в структуру UpdateRoleRequest
+	var request UpdateRoleRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update role: invalid JSON")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// вызываем метод Update сервиса role.Service
+	response, err := c.roleService.Update(ctx.Context(), id, request, version)
+	if err != nil {
+		switch {
+		case errors.As(err, &common.NotFoundError{}):
+			c.logger.ErrorCtx(ctx.Context(), "update role: not found")
+			return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+		case errors.As(err, &common.PreconditionFailedError{}):
+			c.logger.ErrorCtx(ctx.Context(), "update role: version mismatch")
+			return common.ErrResponse(ctx, fiber.StatusPreconditionFailed, err.Error())
+		case errors.As(err, &common.AlreadyExistsError{}):
+			c.logger.ErrorCtx(ctx.Context(), "update role: already exists")
+			return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+		case errors.As(err, &common.RequestValidationError{}):
+			c.logger.ErrorCtx(ctx.Context(), "update role: validation error")
+			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+		default:
+			c.logger.ErrorCtx(ctx.Context(), "update role: internal error")
+			return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	// новая версия роли для последующих условных запросов
+	ctx.Set(fiber.HeaderETag, web.ETag(response.Version))
+
+	if err = common.OkResponse(ctx, response); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update role: error returning role")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning updated role")
+	}
+	return nil
+}
+
 // функция-хендлер для получения роли по ID
 // GetRole получает роль по ID
 // @Summary Получить роль по ID
@@ -104,7 +205,9 @@ func (c *Controller) CreateRole(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID роли"
+// @Param If-None-Match header string false "ETag ранее полученной версии"
 // @Success 200 {object} common.ResponseExample
+// @Success 304 {string} string "Not Modified"
 // @Failure 400 {object} common.ResponseExample
 // @Failure 404 {object} common.ResponseExample
 // @Router /roles/{id} [get]
@@ -133,6 +236,13 @@ func (c *Controller) GetRole(ctx *fiber.Ctx) error {
 		}
 	}
 
+	// если версия роли не изменилась, возвращаем 304 Not Modified без тела
+	etag := web.ETag(response.Version)
+	if web.NotModified(ctx, etag) {
+		return nil
+	}
+	ctx.Set(fiber.HeaderETag, etag)
+
 	if err = common.OkResponse(ctx, response); err != nil {
 		c.logger.ErrorCtx(ctx.Context(), "get role: error returning role")
 		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning role")
@@ -143,19 +253,47 @@ func (c *Controller) GetRole(ctx *fiber.Ctx) error {
 // функция-хендлер для получения всех ролей
 // GetAllRoles получает список всех ролей
 // @Summary Получить все роли
-// @Description Получить список всех ролей
+// @Description Получить список всех ролей; удаленные роли возвращаются только с includeDeleted=true.
+// @Description Список можно отфильтровать по владельцу, уровню риска, признаку requestable и приложению
 // @Tags role
 // @Accept json
 // @Produce json
+// @Param includeDeleted query bool false "Включить удаленные роли (требует права role:restore)"
+// @Param ownerId query int false "ID сотрудника-владельца роли"
+// @Param riskLevel query string false "Уровень риска" Enums(low, medium, high, critical)
+// @Param requestable query bool false "Доступна ли роль для самостоятельного запроса"
+// @Param application query string false "Приложение или система (без учета регистра)"
 // @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 403 {object} common.ResponseExample "Forbidden"
 // @Failure 500 {object} common.ResponseExample
 // @Router /roles [get]
 func (c *Controller) GetAllRoles(ctx *fiber.Ctx) error {
+	var request FindAllRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get all roles: invalid query")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// удаленные роли видны только тем, кому политика разрешает их восстанавливать
+	findAll := c.roleService.FindAll
+	if request.IncludeDeleted {
+		if !c.server.Allowed(ctx, web.RoleRestore) {
+			return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+		}
+		findAll = c.roleService.FindAllWithDeleted
+	}
+	// при заданных фильтрах роли отбираются по описательным данным
+	if request.filtered() {
+		findAll = func(ctx context.Context) ([]Response, error) {
+			return c.roleService.FindByFilter(ctx, request)
+		}
+	}
+
 	// вызываем метод FindAll сервиса role.Service
-	responses, err := c.roleService.FindAll(ctx.Context())
+	responses, err := findAll(ctx.Context())
 	if err != nil {
-		c.logger.ErrorCtx(ctx.Context(), "get all roles: internal error")
-		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+		return c.serviceError(ctx, "get all roles", err)
 	}
 
 	// возвращаем успешный ответ
@@ -166,6 +304,83 @@ func (c *Controller) GetAllRoles(ctx *fiber.Ctx) error {
 	return nil
 }
 
+// функция-хендлер для постраничного получения ролей
+// GetRolesPage получает страницу ролей
+// @Summary Получить страницу ролей
+// @Description Получить страницу ролей с поиском по имени, описанию и приложению, фильтрами и сортировкой.
+// @Description С параметрами after или limit включается режим курсора: роли возвращаются по возрастанию ID
+// @Description порциями до limit записей вместе с next_cursor для следующего запроса (null в конце списка)
+// @Tags role
+// @Produce json
+// @Param pageNumber query int false "Номер страницы (с 0)"
+// @Param pageSize query int false "Размер страницы (1-100, по умолчанию 20)"
+// @Param textFilter query string false "Поиск по имени, описанию и приложению (минимум 3 символа)"
+// @Param sortBy query string false "Поле сортировки" Enums(name, created_at)
+// @Param sortOrder query string false "Направление сортировки" Enums(asc, desc)
+// @Param ownerId query int false "ID сотрудника-владельца роли"
+// @Param riskLevel query string false "Уровень риска" Enums(low, medium, high, critical)
+// @Param requestable query bool false "Доступна ли роль для самостоятельного запроса"
+// @Param application query string false "Приложение или система (без учета регистра)"
+// @Param includeDeleted query bool false "Включить удаленные роли (требует права role:restore)"
+// @Param after query string false "Курсор из next_cursor предыдущего ответа (режим курсора)"
+// @Param limit query int false "Размер порции в режиме курсора (1-1000, по умолчанию 100)"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /roles/page [get]
+func (c *Controller) GetRolesPage(ctx *fiber.Ctx) error {
+	// обход по курсору для выгрузки всех ролей: глубокие offset слишком медленные
+	if ctx.Query("after") != "" || ctx.Query("limit") != "" {
+		return c.getRolesAfter(ctx)
+	}
+
+	request := PageRequest{PageSize: 20}
+	if err := ctx.QueryParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get roles page: invalid query")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	if request.IncludeDeleted && !c.server.Allowed(ctx, web.RoleRestore) {
+		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+	}
+
+	response, err := c.roleService.FindPage(ctx.Context(), request)
+	if err != nil {
+		return c.serviceError(ctx, "get roles page", err)
+	}
+
+	if err = common.OkResponse(ctx, response); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get roles page: error returning roles")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning roles")
+	}
+	return nil
+}
+
+// getRolesAfter возвращает порцию ролей после курсора
+func (c *Controller) getRolesAfter(ctx *fiber.Ctx) error {
+	if ctx.Query("sortBy") != "" || ctx.Query("sortOrder") != "" {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "sorting is not supported with cursor pagination")
+	}
+	request := CursorRequest{Limit: 100}
+	if err := ctx.QueryParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get roles after cursor: invalid query")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+	if request.IncludeDeleted && !c.server.Allowed(ctx, web.RoleRestore) {
+		return common.ErrResponse(ctx, fiber.StatusForbidden, "Permission denied")
+	}
+
+	response, err := c.roleService.FindAfter(ctx.Context(), request)
+	if err != nil {
+		return c.serviceError(ctx, "get roles after cursor", err)
+	}
+
+	if err = common.OkResponse(ctx, response); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get roles after cursor: error returning roles")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning roles")
+	}
+	return nil
+}
+
 // функция-хендлер для получения ролей по списку ID
 // GetRolesByIds получает роли по списку ID
 // @Summary Получить роли по списку ID
@@ -178,6 +393,9 @@ func (c *Controller) GetAllRoles(ctx *fiber.Ctx) error {
 // @Failure 400 {object} common.ResponseExample
 // @Router /roles/by-ids [post]
 func (c *Controller) GetRolesByIds(ctx *fiber.Ctx) error {
+	// POST используется только для передачи списка ID, данные не изменяются
+	web.SkipAudit(ctx)
+
 	// анмаршалим JSON body запроса в структуру FindByIdsRequest
 	var request FindByIdsRequest
 	if err := ctx.BodyParser(&request); err != nil {
@@ -220,6 +438,7 @@ func (c *Controller) GetRolesByIds(ctx *fiber.Ctx) error {
 // @Param request body role.DeleteByIdsRequest true "список ID ролей для удаления"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample "Часть ролей не найдена, ничего не удалено"
 // @Router /roles [delete]
 func (c *Controller) DeleteRolesByIds(ctx *fiber.Ctx) error {
 	// анмаршалим JSON body запроса в структуру DeleteByIdsRequest
@@ -240,10 +459,10 @@ func (c *Controller) DeleteRolesByIds(ctx *fiber.Ctx) error {
 	}
 
 	// вызываем метод DeleteByIds сервиса role.Service
+	// если часть ролей не найдена, сервис ничего не удаляет и возвращает NotFoundError (404)
 	err := c.roleService.DeleteByIds(ctx.Context(), request.Ids)
 	if err != nil {
-		c.logger.ErrorCtx(ctx.Context(), "delete roles by ids: internal error")
-		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+		return c.serviceError(ctx, "delete roles by ids", err)
 	}
 
 	// возвращаем успешный ответ (статус 204 No Content)
@@ -259,9 +478,11 @@ func (c *Controller) DeleteRolesByIds(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID роли"
+// @Param If-Match header string false "ETag версии, которую удаляет клиент"
 // @Success 204 {string} string "No Content"
 // @Failure 400 {object} common.ResponseExample
 // @Failure 404 {object} common.ResponseExample
+// @Failure 412 {object} common.ResponseExample "Precondition Failed"
 // @Router /roles/{id} [delete]
 func (c *Controller) DeleteRole(ctx *fiber.Ctx) error {
 	// получаем ID из параметра маршрута
@@ -272,13 +493,27 @@ func (c *Controller) DeleteRole(ctx *fiber.Ctx) error {
 		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
 	}
 
-	// вызываем метод DeleteById сервиса role.Service
-	err = c.roleService.DeleteById(ctx.Context(), id)
+	// получаем ожидаемую версию роли из заголовка If-Match
+	version, err := web.IfMatchVersion(ctx)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "delete role: invalid If-Match header")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	// вызываем метод DeleteById (или DeleteByIdIfMatch при заданной версии) сервиса role.Service
+	if version > 0 {
+		err = c.roleService.DeleteByIdIfMatch(ctx.Context(), id, version)
+	} else {
+		err = c.roleService.DeleteById(ctx.Context(), id)
+	}
 	if err != nil {
 		switch {
 		case errors.As(err, &common.NotFoundError{}):
 			c.logger.ErrorCtx(ctx.Context(), "delete role: not found")
 			return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+		case errors.As(err, &common.PreconditionFailedError{}):
+			c.logger.ErrorCtx(ctx.Context(), "delete role: version mismatch")
+			return common.ErrResponse(ctx, fiber.StatusPreconditionFailed, err.Error())
 		case errors.As(err, &common.RequestValidationError{}):
 			c.logger.ErrorCtx(ctx.Context(), "delete role: validation error")
 			return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
@@ -290,3 +525,213 @@ func (c *Controller) DeleteRole(ctx *fiber.Ctx) error {
 
 	return ctx.SendStatus(204)
 }
+
+// функция-хендлер для получения родительских ролей
+// GetRoleParents получает роли, от которых роль наследуется
+// @Summary Получить родительские роли
+// @Description Получить роли, от которых роль непосредственно наследуется
+// @Tags role
+// @Produce json
+// @Param id path int true "ID роли"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/parents [get]
+func (c *Controller) GetRoleParents(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "get role parents: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	responses, err := c.roleService.FindParents(ctx.Context(), id)
+	if err != nil {
+		return c.serviceError(ctx, "get role parents", err)
+	}
+
+	if err = common.OkResponse(ctx, responses); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get role parents: error returning roles")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning roles")
+	}
+	return nil
+}
+
+// функция-хендлер для получения дочерних ролей
+// GetRoleChildren получает роли, которые наследуются от роли
+// @Summary Получить дочерние роли
+// @Description Получить роли, которые непосредственно наследуются от роли
+// @Tags role
+// @Produce json
+// @Param id path int true "ID роли"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/children [get]
+func (c *Controller) GetRoleChildren(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "get role children: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	responses, err := c.roleService.FindChildren(ctx.Context(), id)
+	if err != nil {
+		return c.serviceError(ctx, "get role children", err)
+	}
+
+	if err = common.OkResponse(ctx, responses); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get role children: error returning roles")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning roles")
+	}
+	return nil
+}
+
+// функция-хендлер для добавления родительских ролей
+// AddRoleParents добавляет роли родительские роли
+// @Summary Добавить родительские роли
+// @Description Добавить роли родительские роли; связь, образующая цикл в иерархии, отклоняется, как и связь,
+// @Description из-за которой сотрудники нарушили бы запрещающие правила разделения обязанностей
+// @Tags role
+// @Accept json
+// @Produce json
+// @Param id path int true "ID роли"
+// @Param request body role.AddParentsRequest true "список ID родительских ролей"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /roles/{id}/parents [post]
+func (c *Controller) AddRoleParents(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "add role parents: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	var request AddParentsRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "add role parents: invalid JSON")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	responses, err := c.roleService.AddParents(ctx.Context(), id, request)
+	if err != nil {
+		return c.serviceError(ctx, "add role parents", err)
+	}
+
+	if err = common.OkResponse(ctx, responses); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "add role parents: error returning roles")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning roles")
+	}
+	return nil
+}
+
+// функция-хендлер для удаления родительских ролей
+// RemoveRoleParents удаляет связи роли с родительскими ролями
+// @Summary Удалить родительские роли
+// @Description Удалить связи роли с родительскими ролями
+// @Tags role
+// @Accept json
+// @Param id path int true "ID роли"
+// @Param request body role.RemoveParentsRequest true "список ID родительских ролей"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/parents [delete]
+func (c *Controller) RemoveRoleParents(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "remove role parents: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	var request RemoveParentsRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "remove role parents: invalid JSON")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := c.roleService.RemoveParents(ctx.Context(), id, request); err != nil {
+		return c.serviceError(ctx, "remove role parents", err)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// функция-хендлер для восстановления удаленной роли
+// RestoreRole восстанавливает удаленную роль
+// @Summary Восстановить роль
+// @Description Снять с роли пометку об удалении
+// @Tags role
+// @Produce json
+// @Param id path int true "ID роли"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 404 {object} common.ResponseExample
+// @Router /roles/{id}/restore [post]
+func (c *Controller) RestoreRole(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.logger.ErrorCtx(ctx.Context(), "restore role: invalid id")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid role id")
+	}
+
+	response, err := c.roleService.Restore(ctx.Context(), id)
+	if err != nil {
+		return c.serviceError(ctx, "restore role", err)
+	}
+
+	if err = common.OkResponse(ctx, response); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "restore role: error returning role")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning role")
+	}
+	return nil
+}
+
+// функция-хендлер для окончательного удаления ролей
+// PurgeDeletedRoles окончательно удаляет роли, удаленные раньше срока хранения
+// @Summary Очистить удаленные роли
+// @Description Окончательно удалить роли, помеченные удаленными раньше чем older_than_days дней назад
+// @Tags role
+// @Accept json
+// @Produce json
+// @Param request body role.PurgeDeletedRequest true "срок хранения удаленных ролей"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Router /roles/purge [post]
+func (c *Controller) PurgeDeletedRoles(ctx *fiber.Ctx) error {
+	var request PurgeDeletedRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "purge deleted roles: invalid JSON")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	response, err := c.roleService.PurgeDeleted(ctx.Context(), request)
+	if err != nil {
+		return c.serviceError(ctx, "purge deleted roles", err)
+	}
+
+	if err = common.OkResponse(ctx, response); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "purge deleted roles: error returning result")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning purge result")
+	}
+	return nil
+}
+
+// serviceError формирует ответ на ошибку сервиса ролей с соответствующим HTTP статусом
+func (c *Controller) serviceError(ctx *fiber.Ctx, operation string, err error) error {
+	switch {
+	case errors.As(err, &common.NotFoundError{}):
+		c.logger.ErrorCtx(ctx.Context(), operation+": not found")
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	case errors.As(err, &common.RequestValidationError{}):
+		c.logger.ErrorCtx(ctx.Context(), operation+": validation error")
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.ConflictError{}):
+		c.logger.ErrorCtx(ctx.Context(), operation+": conflict")
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	default:
+		c.logger.ErrorCtx(ctx.Context(), operation+": internal error")
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -39,31 +39,91 @@ func (m *MockRoleService) FindById(ctx context.Context, id int64) (Response, err
 	return args.Get(0).(Response), args.Error(1)
 }
 
-func (m *MockRoleService) Add(ctx context.Context, name string) (Response, error) {
-	args := m.Called(ctx, name)
+func (m *MockRoleService) Add(ctx context.Context, request AddRoleRequest) (Response, error) {
+	args := m.Called(ctx, request)
 	return args.Get(0).(Response), args.Error(1)
 }
 
+func (m *MockRoleService) FindByFilter(ctx context.Context, request FindAllRequest) ([]Response, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockRoleService) FindPage(ctx context.Context, request PageRequest) (PageResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(PageResponse), args.Error(1)
+}
+
+func (m *MockRoleService) FindAfter(ctx context.Context, request CursorRequest) (CursorResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(CursorResponse), args.Error(1)
+}
+
 func (m *MockRoleService) FindAll(ctx context.Context) ([]Response, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]Response), args.Error(1)
 }
 
+func (m *MockRoleService) FindAllWithDeleted(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockRoleService) Restore(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockRoleService) PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(PurgeResponse), args.Error(1)
+}
+
 func (m *MockRoleService) FindByIds(ctx context.Context, ids []int64) ([]Response, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]Response), args.Error(1)
 }
 
+func (m *MockRoleService) Update(ctx context.Context, id int64, request UpdateRoleRequest, version int64) (Response, error) {
+	args := m.Called(ctx, id, request, version)
+	return args.Get(0).(Response), args.Error(1)
+}
+
 func (m *MockRoleService) DeleteById(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockRoleService) DeleteByIdIfMatch(ctx context.Context, id, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
 func (m *MockRoleService) DeleteByIds(ctx context.Context, ids []int64) error {
 	args := m.Called(ctx, ids)
 	return args.Error(0)
 }
 
+func (m *MockRoleService) FindParents(ctx context.Context, id int64) ([]Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockRoleService) FindChildren(ctx context.Context, id int64) ([]Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockRoleService) AddParents(ctx context.Context, id int64, request AddParentsRequest) ([]Response, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockRoleService) RemoveParents(ctx context.Context, id int64, request RemoveParentsRequest) error {
+	args := m.Called(ctx, id, request)
+	return args.Error(0)
+}
+
 // setupTest инициализирует тестовое окружение
 func setupTest(t *testing.T) (*fiber.App, *MockRoleService) {
 	t.Helper()
@@ -165,7 +225,7 @@ func TestCreateRole(t *testing.T) {
 		request := AddRoleRequest{Name: "Admin"}
 		expected := Response{Id: 1, Name: "Admin"}
 		mockService.On("ValidateRequest", request).Return(nil).Once()
-		mockService.On("Add", mock.Anything, "Admin").Return(expected, nil).Once()
+		mockService.On("Add", mock.Anything, AddRoleRequest{Name: "Admin"}).Return(expected, nil).Once()
 
 		req := createAuthRequest(t, "POST", "/api/v1/roles", request)
 		resp, err := app.Test(req)
@@ -216,7 +276,7 @@ func TestCreateRole(t *testing.T) {
 
 		request := AddRoleRequest{Name: "Admin"}
 		mockService.On("ValidateRequest", request).Return(nil).Once()
-		mockService.On("Add", mock.Anything, "Admin").Return(
+		mockService.On("Add", mock.Anything, AddRoleRequest{Name: "Admin"}).Return(
 			Response{},
 			common.AlreadyExistsError{Message: "role already exists"},
 		).Once()
@@ -230,7 +290,7 @@ func TestCreateRole(t *testing.T) {
 			}
 		}()
 
-		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, 409, resp.StatusCode)
 
 		var result common.Response[any]
 		parseResponse(t, resp, &result)
@@ -244,7 +304,7 @@ func TestCreateRole(t *testing.T) {
 
 		request := AddRoleRequest{Name: "Test Role"}
 		mockService.On("ValidateRequest", request).Return(nil).Once()
-		mockService.On("Add", mock.Anything, "Test Role").Return(
+		mockService.On("Add", mock.Anything, AddRoleRequest{Name: "Test Role"}).Return(
 			Response{},
 			errors.New("internal server error"),
 		).Once()
@@ -438,6 +498,130 @@ func TestGetAllRoles(t *testing.T) {
 	})
 }
 
+func TestGetRolesPage(t *testing.T) {
+	t.Run("should use default page size", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		expected := PageResponse{Result: []Response{{Id: 1, Name: "Admin"}}, PageSize: 20, Total: 1}
+		mockService.On("FindPage", mock.Anything, PageRequest{PageSize: 20}).Return(expected, nil).Once()
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/page", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[PageResponse]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, expected, result.Data)
+	})
+
+	t.Run("should pass paging, sorting and filters", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		expected := PageRequest{
+			PageSize: 50, PageNumber: 2, TextFilter: "billing", SortBy: "created_at", SortOrder: "desc",
+			FindAllRequest: FindAllRequest{RiskLevel: RiskHigh},
+		}
+		mockService.On("FindPage", mock.Anything, expected).Return(PageResponse{}, nil).Once()
+
+		url := "/api/v1/roles/page?pageSize=50&pageNumber=2&textFilter=billing&sortBy=created_at&sortOrder=desc&riskLevel=high"
+		resp, err := app.Test(createAuthRequest(t, "GET", url, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for invalid page size", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/page?pageSize=many", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, mockService.Calls)
+	})
+
+	t.Run("should return 400 on validation error", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		mockService.On("FindPage", mock.Anything, mock.Anything).Return(
+			PageResponse{}, common.RequestValidationError{Message: "sortBy must be one of name created_at"},
+		).Once()
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/page?sortBy=version", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should switch to cursor mode when after is given", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		next := common.EncodeCursor(42)
+		expected := CursorResponse{Result: []Response{{Id: 42, Name: "Admin"}}, Limit: 100, NextCursor: &next}
+		request := CursorRequest{Limit: 100, After: "aWQ6MTA", FindAllRequest: FindAllRequest{RiskLevel: RiskHigh}}
+		mockService.On("FindAfter", mock.Anything, request).Return(expected, nil).Once()
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/page?after=aWQ6MTA&riskLevel=high", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[CursorResponse]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, expected, result.Data)
+		mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 for sorting in cursor mode", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/page?limit=10&sortBy=name", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, mockService.Calls)
+	})
+}
+
+func TestGetRolesByFilter(t *testing.T) {
+	t.Run("should pass metadata filters to service", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		ownerId := int64(7)
+		requestable := true
+		expected := FindAllRequest{OwnerId: &ownerId, RiskLevel: RiskHigh, Requestable: &requestable, Application: "billing"}
+		mockService.On("FindByFilter", mock.Anything, expected).Return([]Response{{Id: 3, Name: "Payments"}}, nil).Once()
+
+		req := createAuthRequest(t, "GET", "/api/v1/roles?ownerId=7&riskLevel=high&requestable=true&application=billing", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		mockService.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("should return 400 for invalid filter", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		mockService.On("FindByFilter", mock.Anything, mock.Anything).Return(
+			[]Response{}, common.RequestValidationError{Message: "riskLevel must be one of low medium high critical"},
+		).Once()
+
+		req := createAuthRequest(t, "GET", "/api/v1/roles?riskLevel=extreme", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for malformed owner id", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		req := createAuthRequest(t, "GET", "/api/v1/roles?ownerId=abc", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Empty(t, mockService.Calls)
+	})
+}
+
 func TestGetRolesByIds(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		app, mockService := setupTest(t)
@@ -537,6 +721,125 @@ func TestGetRolesByIds(t *testing.T) {
 	})
 }
 
+func TestUpdateRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		request := UpdateRoleRequest{Name: "Manager"}
+		expected := Response{Id: 1, Name: "Manager"}
+		mockService.On("Update", mock.Anything, int64(1), request, int64(0)).Return(expected, nil).Once()
+
+		req := createAuthRequest(t, "PUT", "/api/v1/roles/1", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		var result common.Response[Response]
+		parseResponse(t, resp, &result)
+		assert.True(t, result.Success)
+		assert.Equal(t, expected, result.Data)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		request := UpdateRoleRequest{Name: "Manager"}
+		mockService.On("Update", mock.Anything, int64(999), request, int64(0)).Return(
+			Response{}, common.NotFoundError{Message: "role with id 999 not found"},
+		).Once()
+
+		req := createAuthRequest(t, "PUT", "/api/v1/roles/999", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("failed to close response body: %v", err)
+			}
+		}()
+
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		request := UpdateRoleRequest{Name: "M"}
+		mockService.On("Update", mock.Anything, int64(1), request, int64(0)).Return(
+			Response{}, common.RequestValidationError{Message: "name must be at least 2 characters long"},
+		).Once()
+
+		req := createAuthRequest(t, "PUT", "/api/v1/roles/1", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("failed to close response body: %v", err)
+			}
+		}()
+
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestRoleConditionalRequests(t *testing.T) {
+	t.Run("GET returns ETag", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		mockService.On("FindById", mock.Anything, int64(1)).Return(Response{Id: 1, Name: "Admin", Version: 2}, nil).Once()
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/1", nil))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("GET with matching If-None-Match returns 304", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		mockService.On("FindById", mock.Anything, int64(1)).Return(Response{Id: 1, Name: "Admin", Version: 2}, nil).Once()
+
+		req := createAuthRequest(t, "GET", "/api/v1/roles/1", nil)
+		req.Header.Set("If-None-Match", `W/"1", "2"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 304, resp.StatusCode)
+	})
+
+	t.Run("PUT with stale If-Match returns 412", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		request := UpdateRoleRequest{Name: "Manager"}
+		mockService.On("Update", mock.Anything, int64(1), request, int64(1)).Return(
+			Response{}, common.PreconditionFailedError{Message: "role with id 1 has been modified"},
+		).Once()
+
+		req := createAuthRequest(t, "PUT", "/api/v1/roles/1", request)
+		req.Header.Set("If-Match", `"1"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 412, resp.StatusCode)
+	})
+
+	t.Run("DELETE with If-Match deletes matching version", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		mockService.On("DeleteByIdIfMatch", mock.Anything, int64(1), int64(2)).Return(nil).Once()
+
+		req := createAuthRequest(t, "DELETE", "/api/v1/roles/1", nil)
+		req.Header.Set("If-Match", `"2"`)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+}
+
 func TestDeleteRole(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		app, mockService := setupTest(t)
@@ -679,6 +982,28 @@ func TestDeleteRolesByIds(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode)
 	})
 
+	t.Run("Missing IDs", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		defer mockService.AssertExpectations(t)
+
+		request := DeleteByIdsRequest{Ids: []int64{1, 2}}
+		mockService.On("ValidateRequest", request).Return(nil).Once()
+		mockService.On("DeleteByIds", mock.Anything, []int64{1, 2}).Return(
+			common.NotFoundError{Message: "roles not found: [2]; no roles were deleted"},
+		).Once()
+
+		req := createAuthRequest(t, "DELETE", "/api/v1/roles", request)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("failed to close response body: %v", err)
+			}
+		}()
+
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+
 	t.Run("Invalid JSON", func(t *testing.T) {
 		app, _ := setupTest(t)
 
@@ -865,15 +1190,15 @@ func TestRoleIncorrectDataHttpResponses(t *testing.T) {
 			},
 		},
 		{
-			name:           "already_exists_error_400",
+			name:           "already_exists_error_409",
 			method:         "POST",
 			url:            "/api/v1/roles",
 			body:           AddRoleRequest{Name: "Admin"},
-			expectedStatus: 400,
+			expectedStatus: 409,
 			expectedError:  "already exists",
 			setupMock: func(m *MockRoleService) {
 				m.On("ValidateRequest", AddRoleRequest{Name: "Admin"}).Return(nil)
-				m.On("Add", mock.Anything, "Admin").Return(
+				m.On("Add", mock.Anything, AddRoleRequest{Name: "Admin"}).Return(
 					Response{}, common.AlreadyExistsError{Message: "role already exists"})
 			},
 		},
@@ -1118,7 +1443,7 @@ func TestRoleBoundaryValues(t *testing.T) {
 			expectedStatus: 200,
 			setupMock: func(m *MockRoleService) {
 				m.On("ValidateRequest", AddRoleRequest{Name: "AB"}).Return(nil)
-				m.On("Add", mock.Anything, "AB").Return(Response{Id: 1, Name: "AB"}, nil)
+				m.On("Add", mock.Anything, AddRoleRequest{Name: "AB"}).Return(Response{Id: 1, Name: "AB"}, nil)
 			},
 		},
 		{
@@ -1130,7 +1455,7 @@ func TestRoleBoundaryValues(t *testing.T) {
 			setupMock: func(m *MockRoleService) {
 				longName := strings.Repeat("R", 100)
 				m.On("ValidateRequest", AddRoleRequest{Name: longName}).Return(nil)
-				m.On("Add", mock.Anything, longName).Return(Response{Id: 1, Name: longName}, nil)
+				m.On("Add", mock.Anything, AddRoleRequest{Name: longName}).Return(Response{Id: 1, Name: longName}, nil)
 			},
 		},
 		{
@@ -1247,6 +1572,7 @@ func TestRole_AuthZ(t *testing.T) {
 		{"GetRole", "GET", "/api/v1/roles/1", nil, []string{web.IdmAdmin, web.IdmUser}},
 		{"GetAllRoles", "GET", "/api/v1/roles", nil, []string{web.IdmAdmin, web.IdmUser}},
 		{"GetRolesByIds", "POST", "/api/v1/roles/by-ids", FindByIdsRequest{Ids: []int64{1}}, []string{web.IdmAdmin, web.IdmUser}},
+		{"UpdateRole", "PUT", "/api/v1/roles/1", UpdateRoleRequest{Name: "Test"}, []string{web.IdmAdmin}},
 		{"DeleteRole", "DELETE", "/api/v1/roles/1", nil, []string{web.IdmAdmin}},
 		{"DeleteRolesByIds", "DELETE", "/api/v1/roles", DeleteByIdsRequest{Ids: []int64{1}}, []string{web.IdmAdmin}},
 	}
@@ -1358,3 +1684,120 @@ func TestRole_AuthZ(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleHierarchy(t *testing.T) {
+	t.Run("should return parents of role", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		expected := []Response{{Id: 1, Name: "engineer"}}
+		mockService.On("FindParents", mock.Anything, int64(2)).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/2/parents", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result common.Response[[]Response]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, expected, result.Data)
+	})
+
+	t.Run("should return children of role", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		expected := []Response{{Id: 2, Name: "senior-engineer"}}
+		mockService.On("FindChildren", mock.Anything, int64(1)).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles/1/children", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("should add parents", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		request := AddParentsRequest{ParentIds: []int64{1}}
+		mockService.On("AddParents", mock.Anything, int64(2), request).Return([]Response{{Id: 1, Name: "engineer"}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/2/parents", request))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 400 for hierarchy cycle", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		request := AddParentsRequest{ParentIds: []int64{2}}
+		mockService.On("AddParents", mock.Anything, int64(1), request).
+			Return([]Response(nil), common.RequestValidationError{Message: "role 1 cannot inherit from role 2: hierarchy cycle 1 -> 2 -> 1"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/1/parents", request))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should return 404 for missing role", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		request := RemoveParentsRequest{ParentIds: []int64{1}}
+		mockService.On("RemoveParents", mock.Anything, int64(9), request).
+			Return(common.NotFoundError{Message: "role with id 9 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/roles/9/parents", request))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestRoleSoftDelete(t *testing.T) {
+	t.Run("should return deleted roles for admin", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		deletedAt := time.Now()
+		expected := []Response{{Id: 1, Name: "engineer"}, {Id: 2, Name: "legacy", DeletedAt: &deletedAt}}
+		mockService.On("FindAllWithDeleted", mock.Anything).Return(expected, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/roles?includeDeleted=true", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockService.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("should forbid deleted roles for user", func(t *testing.T) {
+		app, mockService := setupTest(t)
+
+		req := httptest.NewRequest("GET", "/api/v1/roles?includeDeleted=true", nil)
+		req.Header.Set("Authorization", "Bearer "+generateValidToken([]string{web.IdmUser}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Empty(t, mockService.Calls)
+	})
+
+	t.Run("should restore role", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		mockService.On("Restore", mock.Anything, int64(2)).Return(Response{Id: 2, Name: "legacy"}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/2/restore", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("should return 404 when restoring role that is not deleted", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		mockService.On("Restore", mock.Anything, int64(3)).
+			Return(Response{}, common.NotFoundError{Message: "deleted role with id 3 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/3/restore", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should purge deleted roles", func(t *testing.T) {
+		app, mockService := setupTest(t)
+		request := PurgeDeletedRequest{OlderThanDays: 30}
+		mockService.On("PurgeDeleted", mock.Anything, request).Return(PurgeResponse{Purged: 4}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/roles/purge", request))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result common.Response[PurgeResponse]
+		parseResponse(t, resp, &result)
+		assert.Equal(t, int64(4), result.Data.Purged)
+	})
+}
@@ -2,12 +2,28 @@ package role
 
 import "time"
 
+// Уровни риска роли
+const (
+	RiskLow      = "low"
+	RiskMedium   = "medium"
+	RiskHigh     = "high"
+	RiskCritical = "critical"
+)
+
 // Entity представляет сущность роли в базе данных
 type Entity struct {
-	Id        int64     `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	Id        int64      `db:"id"`
+	Name      string     `db:"name"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	Version   int64      `db:"version"`
+	DeletedAt *time.Time `db:"deleted_at"`
+	// описательные данные роли
+	Description string `db:"description"`
+	OwnerId     *int64 `db:"owner_id"`
+	RiskLevel   string `db:"risk_level"`
+	Requestable bool   `db:"requestable"`
+	Application string `db:"application"`
 }
 
 // toResponse преобразует Entity в Response
@@ -17,13 +33,58 @@ func (e *Entity) toResponse() Response {
 		Name:      e.Name,
 		CreatedAt: e.CreatedAt,
 		UpdatedAt: e.UpdatedAt,
+		Version:   e.Version,
+		DeletedAt: e.DeletedAt,
+		Metadata: Metadata{
+			Description: e.Description,
+			OwnerId:     e.OwnerId,
+			RiskLevel:   e.RiskLevel,
+			Requestable: e.Requestable,
+			Application: e.Application,
+		},
 	}
 }
 
 // Response представляет ответ API для роли
 type Response struct {
-	Id        int64     `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id        int64      `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Metadata
+}
+
+// PageResponse представляет страницу ролей
+type PageResponse struct {
+	Result     []Response `json:"result"`
+	PageSize   int        `json:"page_size"`
+	PageNumber int        `json:"page_number"`
+	Total      int64      `json:"total"`
+}
+
+// CursorResponse представляет порцию ролей при обходе по курсору; NextCursor равен null, если ролей больше нет
+type CursorResponse struct {
+	Result     []Response `json:"result"`
+	Limit      int        `json:"limit"`
+	NextCursor *string    `json:"next_cursor"`
+}
+
+// ParentLink представляет связь роли с родительской ролью в иерархии
+type ParentLink struct {
+	RoleId   int64 `db:"role_id"`
+	ParentId int64 `db:"parent_id"`
+}
+
+// SodViolationEntity представляет сотрудника, который нарушил бы запрещающее правило разделения обязанностей
+type SodViolationEntity struct {
+	EmployeeId   int64  `db:"employee_id"`
+	EmployeeName string `db:"employee_name"`
+	RuleName     string `db:"rule_name"`
+}
+
+// PurgeResponse представляет результат окончательного удаления ролей
+type PurgeResponse struct {
+	Purged int64 `json:"purged"`
 }
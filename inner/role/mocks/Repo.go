@@ -5,6 +5,7 @@ package mocks
 import (
 	"context"
 	role "idm/inner/role"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -32,6 +33,52 @@ func (_m *Repo) Add(ctx context.Context, e *role.Entity) error {
 	return r0
 }
 
+// AddParents provides a mock function with given fields: ctx, id, parentIds, at, check
+func (_m *Repo) AddParents(ctx context.Context, id int64, parentIds []int64, at time.Time, check role.ParentsCheck) error {
+	ret := _m.Called(ctx, id, parentIds, at, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddParents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64, time.Time, role.ParentsCheck) error); ok {
+		r0 = rf(ctx, id, parentIds, at, check)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountAll provides a mock function with given fields: ctx, req
+func (_m *Repo) CountAll(ctx context.Context, req role.PageRequest) (int64, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountAll")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) (int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) int64); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.PageRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteById provides a mock function with given fields: ctx, id
 func (_m *Repo) DeleteById(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -50,22 +97,120 @@ func (_m *Repo) DeleteById(ctx context.Context, id int64) error {
 	return r0
 }
 
+// DeleteByIdAndVersion provides a mock function with given fields: ctx, id, version
+func (_m *Repo) DeleteByIdAndVersion(ctx context.Context, id int64, version int64) (int64, error) {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByIdAndVersion")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteByIds provides a mock function with given fields: ctx, ids
-func (_m *Repo) DeleteByIds(ctx context.Context, ids []int64) error {
+func (_m *Repo) DeleteByIds(ctx context.Context, ids []int64) ([]int64, error) {
 	ret := _m.Called(ctx, ids)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteByIds")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []int64) error); ok {
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]int64, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []int64); ok {
 		r0 = rf(ctx, ids)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EmployeeExists provides a mock function with given fields: ctx, id
+func (_m *Repo) EmployeeExists(ctx context.Context, id int64) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EmployeeExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAfter provides a mock function with given fields: ctx, filter, afterId, limit
+func (_m *Repo) FindAfter(ctx context.Context, filter role.PageRequest, afterId int64, limit int) ([]role.Entity, error) {
+	ret := _m.Called(ctx, filter, afterId, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAfter")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest, int64, int) ([]role.Entity, error)); ok {
+		return rf(ctx, filter, afterId, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest, int64, int) []role.Entity); ok {
+		r0 = rf(ctx, filter, afterId, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.PageRequest, int64, int) error); ok {
+		r1 = rf(ctx, filter, afterId, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // FindAll provides a mock function with given fields: ctx
@@ -98,6 +243,66 @@ func (_m *Repo) FindAll(ctx context.Context) ([]role.Entity, error) {
 	return r0, r1
 }
 
+// FindAllWithDeleted provides a mock function with given fields: ctx
+func (_m *Repo) FindAllWithDeleted(ctx context.Context) ([]role.Entity, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllWithDeleted")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]role.Entity, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []role.Entity); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByFilter provides a mock function with given fields: ctx, filter
+func (_m *Repo) FindByFilter(ctx context.Context, filter role.FindAllRequest) ([]role.Entity, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByFilter")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.FindAllRequest) ([]role.Entity, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.FindAllRequest) []role.Entity); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.FindAllRequest) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindById provides a mock function with given fields: ctx, id
 func (_m *Repo) FindById(ctx context.Context, id int64) (role.Entity, error) {
 	ret := _m.Called(ctx, id)
@@ -156,6 +361,188 @@ func (_m *Repo) FindByIds(ctx context.Context, ids []int64) ([]role.Entity, erro
 	return r0, r1
 }
 
+// FindChildren provides a mock function with given fields: ctx, id
+func (_m *Repo) FindChildren(ctx context.Context, id int64) ([]role.Entity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindChildren")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]role.Entity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []role.Entity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPage provides a mock function with given fields: ctx, req
+func (_m *Repo) FindPage(ctx context.Context, req role.PageRequest) ([]role.Entity, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPage")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) ([]role.Entity, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) []role.Entity); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.PageRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindParents provides a mock function with given fields: ctx, id
+func (_m *Repo) FindParents(ctx context.Context, id int64) ([]role.Entity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindParents")
+	}
+
+	var r0 []role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]role.Entity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []role.Entity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Entity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, before
+func (_m *Repo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveParents provides a mock function with given fields: ctx, id, parentIds
+func (_m *Repo) RemoveParents(ctx context.Context, id int64, parentIds []int64) error {
+	ret := _m.Called(ctx, id, parentIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveParents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) error); ok {
+		r0 = rf(ctx, id, parentIds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id, at
+func (_m *Repo) Restore(ctx context.Context, id int64, at time.Time) (role.Entity, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 role.Entity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (role.Entity, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) role.Entity); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Get(0).(role.Entity)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, e
+func (_m *Repo) Update(ctx context.Context, e *role.Entity) error {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *role.Entity) error); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepo creates a new instance of Repo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepo(t interface {
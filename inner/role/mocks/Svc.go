@@ -14,9 +14,9 @@ type Svc struct {
 	mock.Mock
 }
 
-// Add provides a mock function with given fields: ctx, name
-func (_m *Svc) Add(ctx context.Context, name string) (role.Response, error) {
-	ret := _m.Called(ctx, name)
+// Add provides a mock function with given fields: ctx, request
+func (_m *Svc) Add(ctx context.Context, request role.AddRoleRequest) (role.Response, error) {
+	ret := _m.Called(ctx, request)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Add")
@@ -24,17 +24,47 @@ func (_m *Svc) Add(ctx context.Context, name string) (role.Response, error) {
 
 	var r0 role.Response
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (role.Response, error)); ok {
-		return rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, role.AddRoleRequest) (role.Response, error)); ok {
+		return rf(ctx, request)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) role.Response); ok {
-		r0 = rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, role.AddRoleRequest) role.Response); ok {
+		r0 = rf(ctx, request)
 	} else {
 		r0 = ret.Get(0).(role.Response)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, name)
+	if rf, ok := ret.Get(1).(func(context.Context, role.AddRoleRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddParents provides a mock function with given fields: ctx, id, request
+func (_m *Svc) AddParents(ctx context.Context, id int64, request role.AddParentsRequest) ([]role.Response, error) {
+	ret := _m.Called(ctx, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddParents")
+	}
+
+	var r0 []role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, role.AddParentsRequest) ([]role.Response, error)); ok {
+		return rf(ctx, id, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, role.AddParentsRequest) []role.Response); ok {
+		r0 = rf(ctx, id, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, role.AddParentsRequest) error); ok {
+		r1 = rf(ctx, id, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,6 +90,24 @@ func (_m *Svc) DeleteById(ctx context.Context, id int64) error {
 	return r0
 }
 
+// DeleteByIdIfMatch provides a mock function with given fields: ctx, id, version
+func (_m *Svc) DeleteByIdIfMatch(ctx context.Context, id int64, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByIdIfMatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteByIds provides a mock function with given fields: ctx, ids
 func (_m *Svc) DeleteByIds(ctx context.Context, ids []int64) error {
 	ret := _m.Called(ctx, ids)
@@ -78,6 +126,34 @@ func (_m *Svc) DeleteByIds(ctx context.Context, ids []int64) error {
 	return r0
 }
 
+// FindAfter provides a mock function with given fields: ctx, request
+func (_m *Svc) FindAfter(ctx context.Context, request role.CursorRequest) (role.CursorResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAfter")
+	}
+
+	var r0 role.CursorResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.CursorRequest) (role.CursorResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.CursorRequest) role.CursorResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(role.CursorResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.CursorRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAll provides a mock function with given fields: ctx
 func (_m *Svc) FindAll(ctx context.Context) ([]role.Response, error) {
 	ret := _m.Called(ctx)
@@ -108,6 +184,66 @@ func (_m *Svc) FindAll(ctx context.Context) ([]role.Response, error) {
 	return r0, r1
 }
 
+// FindAllWithDeleted provides a mock function with given fields: ctx
+func (_m *Svc) FindAllWithDeleted(ctx context.Context) ([]role.Response, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllWithDeleted")
+	}
+
+	var r0 []role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]role.Response, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []role.Response); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByFilter provides a mock function with given fields: ctx, request
+func (_m *Svc) FindByFilter(ctx context.Context, request role.FindAllRequest) ([]role.Response, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByFilter")
+	}
+
+	var r0 []role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.FindAllRequest) ([]role.Response, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.FindAllRequest) []role.Response); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.FindAllRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindById provides a mock function with given fields: ctx, id
 func (_m *Svc) FindById(ctx context.Context, id int64) (role.Response, error) {
 	ret := _m.Called(ctx, id)
@@ -166,6 +302,196 @@ func (_m *Svc) FindByIds(ctx context.Context, ids []int64) ([]role.Response, err
 	return r0, r1
 }
 
+// FindChildren provides a mock function with given fields: ctx, id
+func (_m *Svc) FindChildren(ctx context.Context, id int64) ([]role.Response, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindChildren")
+	}
+
+	var r0 []role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]role.Response, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []role.Response); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPage provides a mock function with given fields: ctx, request
+func (_m *Svc) FindPage(ctx context.Context, request role.PageRequest) (role.PageResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPage")
+	}
+
+	var r0 role.PageResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) (role.PageResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.PageRequest) role.PageResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(role.PageResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.PageRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindParents provides a mock function with given fields: ctx, id
+func (_m *Svc) FindParents(ctx context.Context, id int64) ([]role.Response, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindParents")
+	}
+
+	var r0 []role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]role.Response, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []role.Response); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]role.Response)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx, request
+func (_m *Svc) PurgeDeleted(ctx context.Context, request role.PurgeDeletedRequest) (role.PurgeResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 role.PurgeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, role.PurgeDeletedRequest) (role.PurgeResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, role.PurgeDeletedRequest) role.PurgeResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(role.PurgeResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, role.PurgeDeletedRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveParents provides a mock function with given fields: ctx, id, request
+func (_m *Svc) RemoveParents(ctx context.Context, id int64, request role.RemoveParentsRequest) error {
+	ret := _m.Called(ctx, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveParents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, role.RemoveParentsRequest) error); ok {
+		r0 = rf(ctx, id, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *Svc) Restore(ctx context.Context, id int64) (role.Response, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (role.Response, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) role.Response); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(role.Response)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, request, version
+func (_m *Svc) Update(ctx context.Context, id int64, request role.UpdateRoleRequest, version int64) (role.Response, error) {
+	ret := _m.Called(ctx, id, request, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 role.Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, role.UpdateRoleRequest, int64) (role.Response, error)); ok {
+		return rf(ctx, id, request, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, role.UpdateRoleRequest, int64) role.Response); ok {
+		r0 = rf(ctx, id, request, version)
+	} else {
+		r0 = ret.Get(0).(role.Response)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, role.UpdateRoleRequest, int64) error); ok {
+		r1 = rf(ctx, id, request, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateRequest provides a mock function with given fields: request
 func (_m *Svc) ValidateRequest(request interface{}) error {
 	ret := _m.Called(request)
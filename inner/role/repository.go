@@ -2,6 +2,12 @@ package role
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -18,32 +24,340 @@ func NewRepository(database *sqlx.DB) *Repository {
 }
 
 func (r *Repository) Add(ctx context.Context, e *Entity) error {
-	query := `insert into role (name, created_at, updated_at) values ($1, $2, $3) returning id`
-	return r.db.QueryRowContext(ctx, query, e.Name, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+	query := `insert into role (name, created_at, updated_at, description, owner_id, risk_level, requestable, application)
+		values ($1, $2, $3, $4, $5, $6, $7, $8) returning id`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.CreatedAt, e.UpdatedAt,
+		e.Description, e.OwnerId, e.RiskLevel, e.Requestable, e.Application).Scan(&e.Id)
 }
 
+// FindById возвращает роль по ID; удаленные роли не возвращаются
 func (r *Repository) FindById(ctx context.Context, id int64) (res Entity, err error) {
-	err = r.db.GetContext(ctx, &res, "select * from role where id = $1", id)
+	err = r.db.GetContext(ctx, &res, "select * from role where id = $1 and deleted_at is null", id)
 	return res, err
 }
 
+// FindAll возвращает все неудаленные роли
 func (r *Repository) FindAll(ctx context.Context) (res []Entity, err error) {
+	err = r.db.SelectContext(ctx, &res, "select * from role where deleted_at is null")
+	return res, err
+}
+
+// FindAllWithDeleted возвращает все роли, включая удаленные
+func (r *Repository) FindAllWithDeleted(ctx context.Context) (res []Entity, err error) {
 	err = r.db.SelectContext(ctx, &res, "select * from role")
 	return res, err
 }
 
+// FindByFilter возвращает роли, удовлетворяющие фильтрам по описательным данным
+func (r *Repository) FindByFilter(ctx context.Context, filter FindAllRequest) (res []Entity, err error) {
+	where, args := buildFilter(filter)
+	err = r.db.SelectContext(ctx, &res, "select * from role"+where+" order by id", args...)
+	return res, err
+}
+
+// textFilterCondition ищет подстроку в имени, описании и приложении роли
+const textFilterCondition = "(name ilike $%[1]d or description ilike $%[1]d or application ilike $%[1]d)"
+
+// sortColumns сопоставляет допустимые поля сортировки с выражениями SQL
+var sortColumns = map[string]string{
+	"name":       "lower(name)",
+	"created_at": "created_at",
+}
+
+// FindPage возвращает страницу ролей, отобранных по фильтрам запроса и отсортированных по SortBy.
+// При равных значениях поля сортировки порядок определяется ID, чтобы страницы не пересекались
+func (r *Repository) FindPage(ctx context.Context, req PageRequest) (res []Entity, err error) {
+	where, args := buildPageFilter(req)
+	column, ok := sortColumns[req.SortBy]
+	if !ok {
+		column = sortColumns["name"]
+	}
+	direction := "asc"
+	if req.SortOrder == "desc" {
+		direction = "desc"
+	}
+	args = append(args, req.PageSize, req.PageNumber*req.PageSize)
+	query := fmt.Sprintf("select * from role%s order by %s %s, id %s limit $%d offset $%d",
+		where, column, direction, direction, len(args)-1, len(args))
+	err = r.db.SelectContext(ctx, &res, query, args...)
+	return res, err
+}
+
+// FindAfter возвращает не более limit ролей с ID больше afterId, отобранных по фильтрам, по возрастанию ID.
+// В отличие от FindPage, запрос не пропускает строки через offset и одинаково быстр на любой глубине списка
+func (r *Repository) FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) (res []Entity, err error) {
+	conditions, args := pageConditions(filter)
+	args = append(args, afterId)
+	conditions = append(conditions, fmt.Sprintf("id > $%d", len(args)))
+	args = append(args, limit)
+	query := fmt.Sprintf("select * from role%s order by id limit $%d", whereClause(conditions), len(args))
+	err = r.db.SelectContext(ctx, &res, query, args...)
+	return res, err
+}
+
+// CountAll возвращает количество ролей, подходящих под фильтры запроса
+func (r *Repository) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	where, args := buildPageFilter(req)
+	var total int64
+	err := r.db.GetContext(ctx, &total, "select count(*) from role"+where, args...)
+	return total, err
+}
+
+// EmployeeExists проверяет наличие неудаленного сотрудника, который может быть владельцем роли
+func (r *Repository) EmployeeExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "select exists(select 1 from employee where id = $1 and deleted_at is null)", id)
+	return exists, err
+}
+
 func (r *Repository) FindByIds(ctx context.Context, ids []int64) (res []Entity, err error) {
-	query := `select * from role where id = any($1)`
+	query := `select * from role where id = any($1) and deleted_at is null`
 	err = r.db.SelectContext(ctx, &res, query, pq.Array(ids))
 	return res, err
 }
 
+// Update обновляет имя и описательные данные роли и увеличивает версию записи.
+// Если e.Version больше 0, обновление выполняется только при совпадении версии.
+// Возвращает sql.ErrNoRows, если роль не найдена, удалена или версия не совпала
+func (r *Repository) Update(ctx context.Context, e *Entity) error {
+	query := `update role set name = $1, updated_at = $2, version = version + 1,
+		description = $5, owner_id = $6, risk_level = $7, requestable = $8, application = $9
+		where id = $3 and deleted_at is null and ($4::bigint = 0 or version = $4::bigint) returning created_at, version`
+	return r.db.QueryRowContext(ctx, query, e.Name, e.UpdatedAt, e.Id, e.Version,
+		e.Description, e.OwnerId, e.RiskLevel, e.Requestable, e.Application).Scan(&e.CreatedAt, &e.Version)
+}
+
+// DeleteById помечает роль удаленной (мягкое удаление); запись можно восстановить через Restore.
+// Возвращает sql.ErrNoRows, если роль не найдена или уже удалена
 func (r *Repository) DeleteById(ctx context.Context, id int64) error {
-	_, err := r.db.ExecContext(ctx, "delete from role where id = $1", id)
-	return err
+	res, err := r.db.ExecContext(ctx, "update role set deleted_at = now(), version = version + 1 where id = $1 and deleted_at is null", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteByIdAndVersion помечает роль удаленной только при совпадении версии, возвращает количество удаленных строк
+func (r *Repository) DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "update role set deleted_at = now(), version = version + 1 where id = $1 and version = $2 and deleted_at is null", id, version)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DeleteByIds помечает роли удаленными (мягкое удаление) и возвращает ID найденных ролей.
+// Удаление выполняется целиком или не выполняется: если часть ID не найдена, транзакция откатывается,
+// и по возвращенным ID можно определить, каких ролей не хватило
+func (r *Repository) DeleteByIds(ctx context.Context, ids []int64) (deleted []int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `update role set deleted_at = now(), version = version + 1
+		where id = any($1) and deleted_at is null returning id`
+	if err = tx.SelectContext(ctx, &deleted, query, pq.Array(ids)); err != nil {
+		return nil, errors.Join(err, tx.Rollback())
+	}
+	if len(deleted) < countUnique(ids) {
+		return deleted, tx.Rollback()
+	}
+	return deleted, tx.Commit()
+}
+
+// Restore снимает с роли пометку об удалении. Возвращает sql.ErrNoRows, если удаленная роль не найдена
+func (r *Repository) Restore(ctx context.Context, id int64, at time.Time) (res Entity, err error) {
+	query := `update role set deleted_at = null, updated_at = $2, version = version + 1
+		where id = $1 and deleted_at is not null returning *`
+	err = r.db.GetContext(ctx, &res, query, id, at)
+	return res, err
 }
 
-func (r *Repository) DeleteByIds(ctx context.Context, ids []int64) error {
-	_, err := r.db.ExecContext(ctx, "delete from role where id = any($1)", pq.Array(ids))
+// PurgeDeleted окончательно удаляет роли, помеченные удаленными раньше before, возвращает их количество
+func (r *Repository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "delete from role where deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FindParents возвращает роли, от которых роль непосредственно наследуется
+func (r *Repository) FindParents(ctx context.Context, id int64) (res []Entity, err error) {
+	query := `select r.* from role_parent rp join role r on r.id = rp.parent_id
+		where rp.role_id = $1 and r.deleted_at is null order by r.id`
+	err = r.db.SelectContext(ctx, &res, query, id)
+	return res, err
+}
+
+// FindChildren возвращает роли, которые непосредственно наследуются от роли
+func (r *Repository) FindChildren(ctx context.Context, id int64) (res []Entity, err error) {
+	query := `select r.* from role_parent rp join role r on r.id = rp.role_id
+		where rp.parent_id = $1 and r.deleted_at is null order by r.id`
+	err = r.db.SelectContext(ctx, &res, query, id)
+	return res, err
+}
+
+// ParentsCheck проверяет добавление родительских ролей по текущим связям иерархии и по нарушениям запрещающих
+// правил разделения обязанностей, которые оно вызвало бы; ошибка проверки отменяет добавление
+type ParentsCheck func(links []ParentLink, violations []SodViolationEntity) error
+
+// AddParents добавляет роли родительские роли; уже существующие связи не изменяются.
+// Связи иерархии читаются и проверяются check в одной транзакции с добавлением под блокировкой role_parent,
+// поэтому параллельные изменения иерархии и назначения ролей не могут обойти проверку
+func (r *Repository) AddParents(ctx context.Context, id int64, parentIds []int64, at time.Time, check ParentsCheck) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "lock table role_parent in share row exclusive mode"); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	var links []ParentLink
+	if err := tx.SelectContext(ctx, &links, "select role_id, parent_id from role_parent"); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	var violations []SodViolationEntity
+	if err := tx.SelectContext(ctx, &violations, newSodViolationsQuery, id, pq.Array(parentIds)); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if err := check(links, violations); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	query := `insert into role_parent (role_id, parent_id, created_at)
+		select $1, parent_id, $3 from unnest($2::bigint[]) as parent_id
+		on conflict (role_id, parent_id) do nothing`
+	if _, err := tx.ExecContext(ctx, query, id, pq.Array(parentIds), at); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// newSodViolationsQuery выбирает нарушения запрещающих правил разделения обязанностей, которые появились бы
+// у сотрудников, если роль $1 унаследует роли $2. Роли учитываются с иерархией, назначения - вместе с еще
+// не начавшимися. Нарушения, которые есть и при текущей иерархии, не выбираются
+const newSodViolationsQuery = `with recursive link as (
+		select role_id, parent_id from role_parent
+		union
+		select $1::bigint, parent_id from unnest($2::bigint[]) as parent_id
+	),
+	granted as (
+		select er.employee_id, er.role_id
+		from employee_role er
+		join employee e on e.id = er.employee_id and e.deleted_at is null
+		join role r on r.id = er.role_id and r.deleted_at is null
+		where er.valid_until is null or er.valid_until > now()
+	),
+	held_before as (
+		select employee_id, role_id from granted
+		union
+		select h.employee_id, rp.parent_id
+		from role_parent rp
+		join held_before h on rp.role_id = h.role_id
+		join role r on r.id = rp.parent_id and r.deleted_at is null
+	),
+	held_after as (
+		select employee_id, role_id from granted
+		union
+		select h.employee_id, l.parent_id
+		from link l
+		join held_after h on l.role_id = h.role_id
+		join role r on r.id = l.parent_id and r.deleted_at is null
+	)
+	select e.id as employee_id, e.name as employee_name, s.name as rule_name
+	from sod_rule s
+	join held_after a on a.role_id = s.role_a_id
+	join held_after b on b.role_id = s.role_b_id and b.employee_id = a.employee_id
+	join employee e on e.id = a.employee_id
+	where s.mode = 'prevent'
+		and not exists (select 1 from held_before x join held_before y on y.employee_id = x.employee_id
+			where x.employee_id = a.employee_id and x.role_id = s.role_a_id and y.role_id = s.role_b_id)
+	order by s.name, e.id`
+
+// RemoveParents удаляет связи роли с родительскими ролями
+func (r *Repository) RemoveParents(ctx context.Context, id int64, parentIds []int64) error {
+	_, err := r.db.ExecContext(ctx, "delete from role_parent where role_id = $1 and parent_id = any($2)", id, pq.Array(parentIds))
 	return err
 }
+
+// countUnique возвращает количество различных ID в списке
+func countUnique(ids []int64) int {
+	unique := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return len(unique)
+}
+
+// buildFilter формирует условие where и его параметры из непустых фильтров запроса.
+// Приложение сравнивается без учета регистра; удаленные роли отбираются только при IncludeDeleted
+func buildFilter(filter FindAllRequest) (string, []any) {
+	conditions, args := filterConditions(filter)
+	return whereClause(conditions), args
+}
+
+// buildPageFilter формирует условие where и его параметры для постраничного просмотра ролей
+func buildPageFilter(req PageRequest) (string, []any) {
+	conditions, args := pageConditions(req)
+	return whereClause(conditions), args
+}
+
+// pageConditions дополняет фильтры по описательным данным поиском подстроки из TextFilter
+func pageConditions(req PageRequest) ([]string, []any) {
+	conditions, args := filterConditions(req.FindAllRequest)
+	if validTextFilter(req.TextFilter) {
+		args = append(args, "%"+req.TextFilter+"%")
+		conditions = append(conditions, fmt.Sprintf(textFilterCondition, len(args)))
+	}
+	return conditions, args
+}
+
+// filterConditions возвращает условия и параметры для фильтров по описательным данным роли
+func filterConditions(filter FindAllRequest) ([]string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at is null")
+	}
+	if filter.OwnerId != nil {
+		add("owner_id = $%d", *filter.OwnerId)
+	}
+	if filter.RiskLevel != "" {
+		add("risk_level = $%d", filter.RiskLevel)
+	}
+	if filter.Requestable != nil {
+		add("requestable = $%d", *filter.Requestable)
+	}
+	if filter.Application != "" {
+		add("lower(application) = lower($%d)", filter.Application)
+	}
+	return conditions, args
+}
+
+// whereClause объединяет условия в where; без условий возвращает пустую строку
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " where " + strings.Join(conditions, " and ")
+}
+
+// validTextFilter проверяет, что фильтр содержит минимум 3 непробельных символа
+func validTextFilter(s string) bool {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(s), "")) >= 3
+}
@@ -2,17 +2,43 @@ package role
 
 import "time"
 
+// Metadata содержит описательные данные роли, общие для запросов создания и изменения.
+// OwnerId - сотрудник, ответственный за роль; requestable разрешает сотрудникам запрашивать роль через заявки;
+// application - система или приложение, к которому относится роль
+type Metadata struct {
+	Description string `json:"description" validate:"omitempty,max=1000"`
+	OwnerId     *int64 `json:"owner_id" validate:"omitempty,gt=0"`
+	RiskLevel   string `json:"risk_level" validate:"omitempty,oneof=low medium high critical"`
+	Requestable bool   `json:"requestable"`
+	Application string `json:"application" validate:"omitempty,max=100"`
+}
+
+// applyTo переносит описательные данные в сущность роли; уровень риска по умолчанию low
+func (m Metadata) applyTo(e *Entity) {
+	e.Description = m.Description
+	e.OwnerId = m.OwnerId
+	e.RiskLevel = m.RiskLevel
+	if e.RiskLevel == "" {
+		e.RiskLevel = RiskLow
+	}
+	e.Requestable = m.Requestable
+	e.Application = m.Application
+}
+
 type AddRoleRequest struct {
 	Name string `json:"name" validate:"required,min=2,max=100"`
+	Metadata
 }
 
 func (req *AddRoleRequest) ToEntity() Entity {
 	now := time.Now()
-	return Entity{
+	e := Entity{
 		Name:      req.Name,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	req.Metadata.applyTo(&e)
+	return e
 }
 
 type FindByIdRequest struct {
@@ -33,4 +59,57 @@ type DeleteByIdsRequest struct {
 
 type UpdateRoleRequest struct {
 	Name string `json:"name" validate:"required,min=2,max=50"`
+	Metadata
+}
+
+// FindAllRequest используется для фильтрации списка ролей; незаданные фильтры не ограничивают выборку
+type FindAllRequest struct {
+	OwnerId        *int64 `query:"ownerId" validate:"omitempty,gt=0"`
+	RiskLevel      string `query:"riskLevel" validate:"omitempty,oneof=low medium high critical"`
+	Requestable    *bool  `query:"requestable"`
+	Application    string `query:"application" validate:"omitempty,max=100"`
+	IncludeDeleted bool   `query:"includeDeleted"`
+}
+
+// PageRequest используется для постраничного просмотра ролей с фильтрами и сортировкой.
+// TextFilter ищется в имени, описании и приложении роли (минимум 3 непробельных символа);
+// сортировка по имени (по умолчанию) или дате создания, при равенстве - по ID
+type PageRequest struct {
+	PageSize   int    `query:"pageSize" validate:"min=1,max=100"`
+	PageNumber int    `query:"pageNumber" validate:"min=0"`
+	TextFilter string `query:"textFilter"`
+	SortBy     string `query:"sortBy" validate:"omitempty,oneof=name created_at"`
+	SortOrder  string `query:"sortOrder" validate:"omitempty,oneof=asc desc"`
+	FindAllRequest
+}
+
+// CursorRequest используется для последовательного обхода ролей по курсору (keyset-пагинация).
+// Роли возвращаются по возрастанию ID начиная после записи, на которую указывает after;
+// фильтры те же, что и у PageRequest, сортировка не поддерживается
+type CursorRequest struct {
+	Limit      int    `query:"limit" validate:"min=1,max=1000"`
+	After      string `query:"after"`
+	TextFilter string `query:"textFilter"`
+	FindAllRequest
+}
+
+// filtered сообщает, задан ли хотя бы один фильтр по описательным данным роли
+func (req FindAllRequest) filtered() bool {
+	return req.OwnerId != nil || req.RiskLevel != "" || req.Requestable != nil || req.Application != ""
+}
+
+// AddParentsRequest используется для добавления роли родительских ролей
+type AddParentsRequest struct {
+	ParentIds []int64 `json:"parent_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// RemoveParentsRequest используется для удаления родительских ролей
+type RemoveParentsRequest struct {
+	ParentIds []int64 `json:"parent_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// PurgeDeletedRequest используется для окончательного удаления ролей,
+// удаленных (мягко) раньше чем older_than_days дней назад
+type PurgeDeletedRequest struct {
+	OlderThanDays int `json:"older_than_days" validate:"required,min=1"`
 }
@@ -2,8 +2,12 @@ package role
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"idm/inner/common"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -19,8 +23,22 @@ type Repo interface {
 	Add(ctx context.Context, e *Entity) error
 	FindAll(ctx context.Context) ([]Entity, error)
 	FindByIds(ctx context.Context, ids []int64) ([]Entity, error)
+	Update(ctx context.Context, e *Entity) error
 	DeleteById(ctx context.Context, id int64) error
-	DeleteByIds(ctx context.Context, ids []int64) error
+	DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error)
+	DeleteByIds(ctx context.Context, ids []int64) ([]int64, error)
+	FindAllWithDeleted(ctx context.Context) ([]Entity, error)
+	Restore(ctx context.Context, id int64, at time.Time) (Entity, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	FindParents(ctx context.Context, id int64) ([]Entity, error)
+	FindChildren(ctx context.Context, id int64) ([]Entity, error)
+	AddParents(ctx context.Context, id int64, parentIds []int64, at time.Time, check ParentsCheck) error
+	RemoveParents(ctx context.Context, id int64, parentIds []int64) error
+	FindByFilter(ctx context.Context, filter FindAllRequest) ([]Entity, error)
+	EmployeeExists(ctx context.Context, id int64) (bool, error)
+	FindPage(ctx context.Context, req PageRequest) ([]Entity, error)
+	CountAll(ctx context.Context, req PageRequest) (int64, error)
+	FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error)
 }
 type Validator interface {
 	Validate(any) error
@@ -54,6 +72,9 @@ func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
 	}
 
 	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+	}
 	if err != nil {
 		return Response{}, fmt.Errorf("error finding role with id %d: %w", id, err)
 	}
@@ -62,20 +83,22 @@ func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
 }
 
 // Add добавляет новую роль
-func (svc *Service) Add(ctx context.Context, name string) (Response, error) {
-	req := AddRoleRequest{Name: name}
-	if err := svc.validator.ValidateWithCustomMessages(req); err != nil {
+func (svc *Service) Add(ctx context.Context, request AddRoleRequest) (Response, error) {
+	if err := svc.validator.ValidateWithCustomMessages(request); err != nil {
 		return Response{}, err
 	}
-
-	now := time.Now()
-	entity := &Entity{
-		Name:      name,
-		CreatedAt: now,
-		UpdatedAt: now,
+	if err := svc.checkOwner(ctx, request.OwnerId); err != nil {
+		return Response{}, err
 	}
 
-	if err := svc.repo.Add(ctx, entity); err != nil {
+	entity := request.ToEntity()
+	entity.Version = 1
+
+	err := svc.repo.Add(ctx, &entity)
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
 		return Response{}, fmt.Errorf("error adding role: %w", err)
 	}
 
@@ -97,6 +120,78 @@ func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
 	return responses, nil
 }
 
+// FindByFilter возвращает роли, отобранные по владельцу, уровню риска, признаку requestable и приложению
+func (svc *Service) FindByFilter(ctx context.Context, request FindAllRequest) ([]Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindByFilter(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error finding roles by filter: %w", err)
+	}
+	return toResponses(entities), nil
+}
+
+// FindPage возвращает страницу ролей с учетом фильтров и сортировки
+func (svc *Service) FindPage(ctx context.Context, req PageRequest) (PageResponse, error) {
+	if err := svc.ValidateRequest(req); err != nil {
+		return PageResponse{}, err
+	}
+
+	entities, err := svc.repo.FindPage(ctx, req)
+	if err != nil {
+		return PageResponse{}, fmt.Errorf("error finding page of roles: %w", err)
+	}
+	total, err := svc.repo.CountAll(ctx, req)
+	if err != nil {
+		return PageResponse{}, fmt.Errorf("error counting roles: %w", err)
+	}
+
+	return PageResponse{
+		Result:     toResponses(entities),
+		PageSize:   req.PageSize,
+		PageNumber: req.PageNumber,
+		Total:      total,
+	}, nil
+}
+
+// FindAfter возвращает следующую порцию ролей после курсора и курсор для продолжения обхода
+func (svc *Service) FindAfter(ctx context.Context, req CursorRequest) (CursorResponse, error) {
+	if err := svc.ValidateRequest(req); err != nil {
+		return CursorResponse{}, err
+	}
+	afterId, err := common.DecodeCursor(req.After)
+	if err != nil {
+		return CursorResponse{}, err
+	}
+
+	// запрашиваем на одну запись больше, чтобы узнать, есть ли продолжение
+	filter := PageRequest{TextFilter: req.TextFilter, FindAllRequest: req.FindAllRequest}
+	entities, err := svc.repo.FindAfter(ctx, filter, afterId, req.Limit+1)
+	if err != nil {
+		return CursorResponse{}, fmt.Errorf("error finding roles after cursor: %w", err)
+	}
+
+	response := CursorResponse{Limit: req.Limit}
+	if len(entities) > req.Limit {
+		entities = entities[:req.Limit]
+		next := common.EncodeCursor(entities[len(entities)-1].Id)
+		response.NextCursor = &next
+	}
+	response.Result = toResponses(entities)
+	return response, nil
+}
+
+// FindAllWithDeleted возвращает все роли, включая удаленные
+func (svc *Service) FindAllWithDeleted(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAllWithDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding all roles including deleted: %w", err)
+	}
+	return toResponses(entities), nil
+}
+
 // FindByIds возвращает роли по списку ID
 func (svc *Service) FindByIds(ctx context.Context, ids []int64) ([]Response, error) {
 
@@ -118,6 +213,41 @@ func (svc *Service) FindByIds(ctx context.Context, ids []int64) ([]Response, err
 	return responses, nil
 }
 
+// Update обновляет имя и описательные данные роли.
+// Если version больше 0, обновление выполняется только для этой версии записи (If-Match)
+func (svc *Service) Update(ctx context.Context, id int64, request UpdateRoleRequest, version int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: "invalid role id"}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkOwner(ctx, request.OwnerId); err != nil {
+		return Response{}, err
+	}
+
+	entity := &Entity{
+		Id:        id,
+		Name:      request.Name,
+		UpdatedAt: time.Now(),
+		Version:   version,
+	}
+	request.Metadata.applyTo(entity)
+
+	err := svc.repo.Update(ctx, entity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, svc.versionError(ctx, id, version)
+	}
+	if common.IsUniqueViolation(err) {
+		return Response{}, nameTakenError(request.Name)
+	}
+	if err != nil {
+		return Response{}, fmt.Errorf("error updating role with id %d: %w", id, err)
+	}
+
+	return entity.toResponse(), nil
+}
+
 // DeleteById удаляет роль по ID
 func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 	if err := svc.validator.ValidateWithCustomMessages(id); err != nil {
@@ -128,6 +258,9 @@ func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 		return common.RequestValidationError{Message: "invalid role id"}
 	}
 	err := svc.repo.DeleteById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+	}
 	if err != nil {
 		return fmt.Errorf("error deleting role with id %d: %w", id, err)
 	}
@@ -135,17 +268,303 @@ func (svc *Service) DeleteById(ctx context.Context, id int64) error {
 	return nil
 }
 
-// DeleteByIds удаляет роли по списку ID
+// DeleteByIdIfMatch удаляет роль по ID только при совпадении версии записи (If-Match)
+func (svc *Service) DeleteByIdIfMatch(ctx context.Context, id, version int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: "invalid role id"}
+	}
+
+	deleted, err := svc.repo.DeleteByIdAndVersion(ctx, id, version)
+	if err != nil {
+		return fmt.Errorf("error deleting role with id %d: %w", id, err)
+	}
+	if deleted == 0 {
+		return svc.versionError(ctx, id, version)
+	}
+
+	return nil
+}
+
+// versionError определяет, почему роль не была изменена: она отсутствует
+// или ее текущая версия отличается от ожидаемой
+func (svc *Service) versionError(ctx context.Context, id, version int64) error {
+	if version <= 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+	}
+
+	current, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+	}
+	if err != nil {
+		return fmt.Errorf("error finding role with id %d: %w", id, err)
+	}
+	return common.PreconditionFailedError{
+		Message: fmt.Sprintf("role with id %d has been modified: expected version %d, current version %d", id, version, current.Version),
+	}
+}
+
+// DeleteByIds удаляет роли по списку ID.
+// Если хотя бы одна роль не найдена, не удаляется ни одна, а ошибка перечисляет отсутствующие ID
 func (svc *Service) DeleteByIds(ctx context.Context, ids []int64) error {
 
 	if err := svc.validator.ValidateWithCustomMessages(ids); err != nil {
 		return err
 	}
 
-	err := svc.repo.DeleteByIds(ctx, ids)
+	deleted, err := svc.repo.DeleteByIds(ctx, ids)
 	if err != nil {
 		return fmt.Errorf("error deleting roles by ids: %w", err)
 	}
+	if missing := missingIds(ids, deleted); len(missing) > 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("roles not found: %v; no roles were deleted", missing)}
+	}
+
+	return nil
+}
+
+// Restore восстанавливает удаленную роль
+func (svc *Service) Restore(ctx context.Context, id int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: "invalid role id"}
+	}
+
+	entity, err := svc.repo.Restore(ctx, id, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("deleted role with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, fmt.Errorf("error restoring role with id %d: %w", id, err)
+	}
+
+	return entity.toResponse(), nil
+}
+
+// PurgeDeleted окончательно удаляет роли, удаленные раньше чем request.OlderThanDays дней назад,
+// вместе с их назначениями сотрудникам, правами и связями иерархии
+func (svc *Service) PurgeDeleted(ctx context.Context, request PurgeDeletedRequest) (PurgeResponse, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return PurgeResponse{}, err
+	}
+
+	before := time.Now().AddDate(0, 0, -request.OlderThanDays)
+	purged, err := svc.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return PurgeResponse{}, fmt.Errorf("error purging deleted roles: %w", err)
+	}
+
+	return PurgeResponse{Purged: purged}, nil
+}
+
+// FindParents возвращает родительские роли, от которых роль непосредственно наследуется
+func (svc *Service) FindParents(ctx context.Context, id int64) ([]Response, error) {
+	if err := svc.checkRole(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindParents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding parents of role %d: %w", id, err)
+	}
+	return toResponses(entities), nil
+}
+
+// FindChildren возвращает дочерние роли, которые непосредственно наследуются от роли
+func (svc *Service) FindChildren(ctx context.Context, id int64) ([]Response, error) {
+	if err := svc.checkRole(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entities, err := svc.repo.FindChildren(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error finding children of role %d: %w", id, err)
+	}
+	return toResponses(entities), nil
+}
+
+// AddParents добавляет роли родительские роли и возвращает актуальный список родителей.
+// Иерархия ролей должна оставаться ациклической: связь, замыкающая цикл, отклоняется. Отклоняется и
+// наследование, из-за которого у сотрудников появились бы роли, совмещать которые запрещено правилами
+// разделения обязанностей
+func (svc *Service) AddParents(ctx context.Context, id int64, request AddParentsRequest) ([]Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return nil, err
+	}
+	if err := svc.checkRole(ctx, id); err != nil {
+		return nil, err
+	}
+
+	parents, err := svc.repo.FindByIds(ctx, request.ParentIds)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding roles by ids", Err: err}
+	}
+	if missing := missingIds(request.ParentIds, entityIds(parents)); len(missing) > 0 {
+		return nil, common.NotFoundError{Message: fmt.Sprintf("roles not found: %v", missing)}
+	}
+
+	// проверка выполняется репозиторием в транзакции добавления, ее ошибка возвращается как есть
+	var rejected error
+	check := func(links []ParentLink, violations []SodViolationEntity) error {
+		rejected = checkAcyclic(links, id, request.ParentIds)
+		if rejected == nil && len(violations) > 0 {
+			rejected = sodViolationError(id, request.ParentIds, violations)
+		}
+		return rejected
+	}
+	err = svc.repo.AddParents(ctx, id, request.ParentIds, time.Now(), check)
+	if rejected != nil {
+		return nil, rejected
+	}
+	if err != nil {
+		return nil, common.RepositoryError{Message: fmt.Sprintf("error adding parents to role %d", id), Err: err}
+	}
+	return svc.FindParents(ctx, id)
+}
+
+// RemoveParents удаляет связи роли с родительскими ролями
+func (svc *Service) RemoveParents(ctx context.Context, id int64, request RemoveParentsRequest) error {
+	if err := svc.ValidateRequest(request); err != nil {
+		return err
+	}
+	if err := svc.checkRole(ctx, id); err != nil {
+		return err
+	}
+
+	if err := svc.repo.RemoveParents(ctx, id, request.ParentIds); err != nil {
+		return fmt.Errorf("error removing parents from role %d: %w", id, err)
+	}
+	return nil
+}
+
+// checkRole проверяет корректность ID и наличие роли
+func (svc *Service) checkRole(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: "invalid role id"}
+	}
+
+	_, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+	}
+	if err != nil {
+		return fmt.Errorf("error finding role with id %d: %w", id, err)
+	}
+	return nil
+}
+
+// checkOwner проверяет, что владелец роли, если он указан, является неудаленным сотрудником
+func (svc *Service) checkOwner(ctx context.Context, ownerId *int64) error {
+	if ownerId == nil {
+		return nil
+	}
+
+	exists, err := svc.repo.EmployeeExists(ctx, *ownerId)
+	if err != nil {
+		return fmt.Errorf("error finding employee with id %d: %w", *ownerId, err)
+	}
+	if !exists {
+		return common.NotFoundError{Message: fmt.Sprintf("role owner: employee with id %d not found", *ownerId)}
+	}
+	return nil
+}
+
+// checkAcyclic проверяет, что связи роли id с родителями parentIds не замыкают цикл в иерархии links
+func checkAcyclic(links []ParentLink, id int64, parentIds []int64) error {
+	for _, parentId := range parentIds {
+		if cycle := findCycle(links, id, parentId); cycle != nil {
+			return common.RequestValidationError{
+				Message: fmt.Sprintf("role %d cannot inherit from role %d: hierarchy cycle %s", id, parentId, formatPath(cycle)),
+			}
+		}
+	}
+	return nil
+}
+
+// sodViolationError перечисляет нарушения правил разделения обязанностей, к которым привело бы наследование
+func sodViolationError(id int64, parentIds []int64, violations []SodViolationEntity) error {
+	items := make([]string, len(violations))
+	for i, violation := range violations {
+		items[i] = fmt.Sprintf("'%s' by employee %d (%s)", violation.RuleName, violation.EmployeeId, violation.EmployeeName)
+	}
+	return common.ConflictError{
+		Message: fmt.Sprintf("role %d cannot inherit from roles %v: segregation-of-duties rules would be violated: %s",
+			id, parentIds, strings.Join(items, ", ")),
+	}
+}
+
+// findCycle проверяет, замкнет ли связь roleId -> parentId цикл в иерархии.
+// Цикл возникает, если roleId уже достижима из parentId по связям "роль -> родитель".
+// Возвращает путь цикла, начинающийся и заканчивающийся roleId, или nil
+func findCycle(links []ParentLink, roleId, parentId int64) []int64 {
+	parentsOf := make(map[int64][]int64)
+	for _, link := range links {
+		parentsOf[link.RoleId] = append(parentsOf[link.RoleId], link.ParentId)
+	}
 
+	// обход в ширину с запоминанием предыдущей вершины для восстановления пути
+	previous := map[int64]int64{parentId: roleId}
+	queue := []int64{parentId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == roleId {
+			path := []int64{roleId}
+			for node := previous[roleId]; node != roleId; node = previous[node] {
+				path = append(path, node)
+			}
+			path = append(path, roleId)
+			slices.Reverse(path)
+			return path
+		}
+		for _, next := range parentsOf[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
 	return nil
 }
+
+// formatPath форматирует путь по иерархии ролей в виде "1 -> 2 -> 1"
+func formatPath(path []int64) string {
+	parts := make([]string, len(path))
+	for i, id := range path {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// nameTakenError возвращает ошибку о том, что имя уже занято другой ролью
+func nameTakenError(name string) error {
+	return common.AlreadyExistsError{Message: fmt.Sprintf("role with name '%s' already exists", name)}
+}
+
+// entityIds возвращает ID ролей из списка
+func entityIds(entities []Entity) []int64 {
+	ids := make([]int64, len(entities))
+	for i, entity := range entities {
+		ids[i] = entity.Id
+	}
+	return ids
+}
+
+// toResponses преобразует список Entity в список Response
+func toResponses(entities []Entity) []Response {
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses
+}
+
+// missingIds возвращает ID из requested, которых нет среди found
+func missingIds(requested, found []int64) []int64 {
+	var missing []int64
+	for _, id := range requested {
+		if !slices.Contains(found, id) && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -2,12 +2,14 @@ package role
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"idm/inner/common"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -46,21 +48,96 @@ func (m *MockRepo) FindAll(ctx context.Context) ([]Entity, error) {
 	return args.Get(0).([]Entity), args.Error(1)
 }
 
+func (m *MockRepo) FindAllWithDeleted(ctx context.Context) ([]Entity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) Restore(ctx context.Context, id int64, at time.Time) (Entity, error) {
+	args := m.Called(ctx, id, at)
+	return args.Get(0).(Entity), args.Error(1)
+}
+
+func (m *MockRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockRepo) FindByIds(ctx context.Context, ids []int64) ([]Entity, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]Entity), args.Error(1)
 }
 
+func (m *MockRepo) Update(ctx context.Context, e *Entity) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
 func (m *MockRepo) DeleteById(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRepo) DeleteByIds(ctx context.Context, ids []int64) error {
+func (m *MockRepo) DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) DeleteByIds(ctx context.Context, ids []int64) ([]int64, error) {
 	args := m.Called(ctx, ids)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockRepo) FindParents(ctx context.Context, id int64) ([]Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) FindChildren(ctx context.Context, id int64) ([]Entity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+// AddParents возвращает ошибку из ожидания, а без нее - результат проверки check
+// на связях иерархии и нарушениях правил из ожидания
+func (m *MockRepo) AddParents(ctx context.Context, id int64, parentIds []int64, at time.Time, check ParentsCheck) error {
+	args := m.Called(ctx, id, parentIds, at)
+	if err := args.Error(2); err != nil {
+		return err
+	}
+	return check(args.Get(0).([]ParentLink), args.Get(1).([]SodViolationEntity))
+}
+
+func (m *MockRepo) RemoveParents(ctx context.Context, id int64, parentIds []int64) error {
+	args := m.Called(ctx, id, parentIds)
 	return args.Error(0)
 }
 
+func (m *MockRepo) FindByFilter(ctx context.Context, filter FindAllRequest) ([]Entity, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) EmployeeExists(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
+func (m *MockRepo) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error) {
+	args := m.Called(ctx, filter, afterId, limit)
+	return args.Get(0).([]Entity), args.Error(1)
+}
+
 func TestRoleService_FindById(t *testing.T) {
 	a := assert.New(t)
 
@@ -123,7 +200,7 @@ func TestRoleService_Add(t *testing.T) {
 			entity.Id = 1
 		})
 
-		got, err := svc.Add(context.Background(), "Admin")
+		got, err := svc.Add(context.Background(), AddRoleRequest{Name: "Admin"})
 
 		a.Nil(err)
 		a.Equal(int64(1), got.Id)
@@ -142,7 +219,7 @@ func TestRoleService_Add(t *testing.T) {
 		// Ошибка валидации для пустого имени
 		validator.On("ValidateWithCustomMessages", AddRoleRequest{Name: ""}).Return(errors.New("name cannot be empty"))
 
-		response, err := svc.Add(context.Background(), "")
+		response, err := svc.Add(context.Background(), AddRoleRequest{Name: ""})
 
 		a.Empty(response)
 		a.NotNil(err)
@@ -151,6 +228,60 @@ func TestRoleService_Add(t *testing.T) {
 		validator.AssertExpectations(t)
 		repo.AssertExpectations(t)
 	})
+
+	t.Run("should return already exists on unique violation", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+
+		validator.On("ValidateWithCustomMessages", AddRoleRequest{Name: "Admin"}).Return(nil)
+		repo.On("Add", mock.Anything, mock.AnythingOfType("*role.Entity")).Return(&pq.Error{Code: "23505"})
+
+		_, err := svc.Add(context.Background(), AddRoleRequest{Name: "Admin"})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+		a.Equal("role with name 'Admin' already exists", err.Error())
+	})
+
+	t.Run("should add role with metadata and default risk level", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		ownerId := int64(7)
+		request := AddRoleRequest{Name: "Payments", Metadata: Metadata{
+			Description: "Проведение платежей", OwnerId: &ownerId, Requestable: true, Application: "billing",
+		}}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("EmployeeExists", mock.Anything, int64(7)).Return(true, nil)
+		repo.On("Add", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.OwnerId != nil && *e.OwnerId == 7 && e.RiskLevel == RiskLow && e.Requestable && e.Application == "billing"
+		})).Return(nil)
+
+		got, err := svc.Add(context.Background(), request)
+
+		a.Nil(err)
+		a.Equal("Проведение платежей", got.Description)
+		a.Equal(RiskLow, got.RiskLevel)
+		a.True(got.Requestable)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing owner", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		ownerId := int64(99)
+		request := AddRoleRequest{Name: "Payments", Metadata: Metadata{OwnerId: &ownerId}}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("EmployeeExists", mock.Anything, int64(99)).Return(false, nil)
+
+		_, err := svc.Add(context.Background(), request)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		repo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
 }
 
 func TestRoleService_FindAll(t *testing.T) {
@@ -215,6 +346,276 @@ func TestRoleService_DeleteById(t *testing.T) {
 	})
 }
 
+func TestRoleService_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should update role successfully", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := UpdateRoleRequest{Name: "Admin"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*role.Entity")).Return(nil)
+
+		got, err := svc.Update(context.Background(), 1, request, 0)
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Id)
+		a.Equal("Admin", got.Name)
+		a.False(got.UpdatedAt.IsZero())
+
+		validator.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found error if role does not exist", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := UpdateRoleRequest{Name: "Admin"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*role.Entity")).Return(sql.ErrNoRows)
+
+		_, err := svc.Update(context.Background(), 5, request, 0)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should return validation error for invalid id", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+
+		_, err := svc.Update(context.Background(), 0, UpdateRoleRequest{Name: "Admin"}, 0)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "Update", 0))
+	})
+
+	t.Run("should return precondition failed error on version mismatch", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := UpdateRoleRequest{Name: "Admin"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool { return e.Version == 2 })).Return(sql.ErrNoRows)
+		repo.On("FindById", mock.Anything, int64(1)).Return(Entity{Id: 1, Name: "User", Version: 5}, nil)
+
+		_, err := svc.Update(context.Background(), 1, request, 2)
+
+		a.NotNil(err)
+		a.True(errors.As(err, &common.PreconditionFailedError{}))
+		a.Contains(err.Error(), "current version 5")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should update metadata", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := UpdateRoleRequest{Name: "Admin", Metadata: Metadata{RiskLevel: RiskCritical, Application: "core"}}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.RiskLevel == RiskCritical && e.Application == "core" && e.OwnerId == nil
+		})).Return(nil)
+
+		got, err := svc.Update(context.Background(), 1, request, 0)
+
+		a.Nil(err)
+		a.Equal(RiskCritical, got.RiskLevel)
+		repo.AssertNotCalled(t, "EmployeeExists", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRoleService_FindPage(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return page with total", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := PageRequest{PageSize: 2, PageNumber: 1, TextFilter: "pay", SortBy: "created_at", SortOrder: "desc"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindPage", mock.Anything, request).Return([]Entity{{Id: 3, Name: "Payments"}, {Id: 4, Name: "Payroll"}}, nil)
+		repo.On("CountAll", mock.Anything, request).Return(int64(5), nil)
+
+		got, err := svc.FindPage(context.Background(), request)
+
+		a.Nil(err)
+		a.Len(got.Result, 2)
+		a.Equal(int64(5), got.Total)
+		a.Equal(2, got.PageSize)
+		a.Equal(1, got.PageNumber)
+	})
+
+	t.Run("should return validation error for unknown sort field", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := PageRequest{PageSize: 20, SortBy: "version"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(errors.New("sortBy must be one of name created_at"))
+
+		_, err := svc.FindPage(context.Background(), request)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindPage", 0))
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := PageRequest{PageSize: 20}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindPage", mock.Anything, request).Return([]Entity{}, errors.New("database error"))
+
+		_, err := svc.FindPage(context.Background(), request)
+
+		a.ErrorContains(err, "error finding page of roles")
+		a.True(repo.AssertNumberOfCalls(t, "CountAll", 0))
+	})
+}
+
+func TestRoleService_FindAfter(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return next cursor when more roles remain", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := CursorRequest{Limit: 2, After: common.EncodeCursor(10), TextFilter: "pay"}
+		filter := PageRequest{TextFilter: "pay"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindAfter", mock.Anything, filter, int64(10), 3).
+			Return([]Entity{{Id: 11, Name: "Payments"}, {Id: 12, Name: "Payroll"}, {Id: 15, Name: "Payouts"}}, nil)
+
+		got, err := svc.FindAfter(context.Background(), request)
+
+		a.Nil(err)
+		a.Len(got.Result, 2)
+		a.Equal(2, got.Limit)
+		a.NotNil(got.NextCursor)
+		next, err := common.DecodeCursor(*got.NextCursor)
+		a.Nil(err)
+		a.Equal(int64(12), next)
+	})
+
+	t.Run("should return nil cursor on the last portion", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := CursorRequest{Limit: 2}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindAfter", mock.Anything, PageRequest{}, int64(0), 3).Return([]Entity{{Id: 1, Name: "Admin"}}, nil)
+
+		got, err := svc.FindAfter(context.Background(), request)
+
+		a.Nil(err)
+		a.Len(got.Result, 1)
+		a.Nil(got.NextCursor)
+	})
+
+	t.Run("should return validation error for malformed cursor", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := CursorRequest{Limit: 2, After: "not-a-cursor"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+
+		_, err := svc.FindAfter(context.Background(), request)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindAfter", 0))
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := CursorRequest{Limit: 2}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindAfter", mock.Anything, PageRequest{}, int64(0), 3).Return([]Entity{}, errors.New("database error"))
+
+		_, err := svc.FindAfter(context.Background(), request)
+
+		a.ErrorContains(err, "error finding roles after cursor")
+	})
+}
+
+func TestRoleService_FindByFilter(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return filtered roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		requestable := true
+		request := FindAllRequest{RiskLevel: RiskHigh, Requestable: &requestable}
+
+		validator.On("ValidateWithCustomMessages", request).Return(nil)
+		repo.On("FindByFilter", mock.Anything, request).Return([]Entity{{Id: 3, Name: "Payments", RiskLevel: RiskHigh, Requestable: true}}, nil)
+
+		got, err := svc.FindByFilter(context.Background(), request)
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(RiskHigh, got[0].RiskLevel)
+	})
+
+	t.Run("should return validation error for invalid filter", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+		request := FindAllRequest{RiskLevel: "extreme"}
+
+		validator.On("ValidateWithCustomMessages", request).Return(errors.New("riskLevel must be one of low medium high critical"))
+
+		_, err := svc.FindByFilter(context.Background(), request)
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.True(repo.AssertNumberOfCalls(t, "FindByFilter", 0))
+	})
+}
+
+func TestRoleService_DeleteByIdIfMatch(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should delete role with matching version", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockValidator))
+		repo.On("DeleteByIdAndVersion", mock.Anything, int64(1), int64(5)).Return(int64(1), nil)
+
+		err := svc.DeleteByIdIfMatch(context.Background(), 1, 5)
+
+		a.Nil(err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found error if role does not exist", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockValidator))
+		repo.On("DeleteByIdAndVersion", mock.Anything, int64(7), int64(5)).Return(int64(0), nil)
+		repo.On("FindById", mock.Anything, int64(7)).Return(Entity{}, sql.ErrNoRows)
+
+		err := svc.DeleteByIdIfMatch(context.Background(), 7, 5)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
 func TestRoleService_FindByIds(t *testing.T) {
 	a := assert.New(t)
 
@@ -303,7 +704,7 @@ func TestRoleService_DeleteByIds(t *testing.T) {
 			return s[0] == 1 && s[1] == 2
 		})).Return(nil)
 
-		repo.On("DeleteByIds", mock.Anything, ids).Return(nil)
+		repo.On("DeleteByIds", mock.Anything, ids).Return(ids, nil)
 
 		err := svc.DeleteByIds(context.Background(), ids)
 
@@ -328,7 +729,7 @@ func TestRoleService_DeleteByIds(t *testing.T) {
 			}
 			return s[0] == 1 && s[1] == 2
 		})).Return(nil)
-		repo.On("DeleteByIds", mock.Anything, ids).Return(repoErr)
+		repo.On("DeleteByIds", mock.Anything, ids).Return([]int64(nil), repoErr)
 
 		// Вызываем тестируемый метод
 		err := svc.DeleteByIds(context.Background(), ids)
@@ -338,6 +739,23 @@ func TestRoleService_DeleteByIds(t *testing.T) {
 		a.Contains(err.Error(), "error deleting roles by ids")
 		a.True(repo.AssertNumberOfCalls(t, "DeleteByIds", 1))
 	})
+
+	t.Run("should return not found with missing ids", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+
+		ids := []int64{1, 2, 3}
+		validator.On("ValidateWithCustomMessages", ids).Return(nil)
+		repo.On("DeleteByIds", mock.Anything, ids).Return([]int64{1}, nil)
+
+		err := svc.DeleteByIds(context.Background(), ids)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+		a.Contains(err.Error(), "[2 3]")
+		a.Contains(err.Error(), "no roles were deleted")
+	})
 }
 
 // Добавляем недостающие тесты с обработкой ошибок
@@ -362,6 +780,35 @@ func TestRoleService_FindById_ErrorHandling(t *testing.T) {
 		a.Contains(err.Error(), "database error")
 		a.True(repo.AssertNumberOfCalls(t, "FindById", 1))
 	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+
+		validator.On("ValidateWithCustomMessages", int64(42)).Return(nil)
+		repo.On("FindById", mock.Anything, int64(42)).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.FindById(context.Background(), 42)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+		a.Equal("role with id 42 not found", err.Error())
+	})
+
+	t.Run("should return not found when deleting missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		svc := NewService(repo, validator)
+
+		validator.On("ValidateWithCustomMessages", int64(42)).Return(nil)
+		repo.On("DeleteById", mock.Anything, int64(42)).Return(sql.ErrNoRows)
+
+		err := svc.DeleteById(context.Background(), 42)
+
+		var notFound common.NotFoundError
+		a.ErrorAs(err, &notFound)
+	})
 }
 
 func TestRoleService_Add_ErrorHandling(t *testing.T) {
@@ -376,7 +823,7 @@ func TestRoleService_Add_ErrorHandling(t *testing.T) {
 		repo.On("Add", mock.Anything, mock.AnythingOfType("*role.Entity")).Return(repoErr)
 		validator.On("ValidateWithCustomMessages", AddRoleRequest{Name: "Admin"}).Return(nil)
 
-		response, err := svc.Add(context.Background(), "Admin")
+		response, err := svc.Add(context.Background(), AddRoleRequest{Name: "Admin"})
 
 		a.Empty(response)
 		a.NotNil(err)
@@ -448,18 +895,74 @@ func (s *StubRepo) FindAll(ctx context.Context) ([]Entity, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *StubRepo) FindAllWithDeleted(ctx context.Context) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) Restore(ctx context.Context, id int64, at time.Time) (Entity, error) {
+	return Entity{}, errors.New("not implemented")
+}
+
+func (s *StubRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
 func (s *StubRepo) FindByIds(ctx context.Context, ids []int64) ([]Entity, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *StubRepo) Update(ctx context.Context, e *Entity) error {
+	return errors.New("not implemented")
+}
+
 func (s *StubRepo) DeleteById(ctx context.Context, id int64) error {
 	return errors.New("not implemented")
 }
 
-func (s *StubRepo) DeleteByIds(ctx context.Context, ids []int64) error {
+func (s *StubRepo) DeleteByIdAndVersion(ctx context.Context, id, version int64) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *StubRepo) DeleteByIds(ctx context.Context, ids []int64) ([]int64, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) FindParents(ctx context.Context, id int64) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) FindChildren(ctx context.Context, id int64) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) AddParents(ctx context.Context, id int64, parentIds []int64, at time.Time, check ParentsCheck) error {
+	return errors.New("not implemented")
+}
+
+func (s *StubRepo) RemoveParents(ctx context.Context, id int64, parentIds []int64) error {
 	return errors.New("not implemented")
 }
 
+func (s *StubRepo) FindByFilter(ctx context.Context, filter FindAllRequest) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) EmployeeExists(ctx context.Context, id int64) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (s *StubRepo) FindPage(ctx context.Context, req PageRequest) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *StubRepo) CountAll(ctx context.Context, req PageRequest) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (s *StubRepo) FindAfter(ctx context.Context, filter PageRequest, afterId int64, limit int) ([]Entity, error) {
+	return nil, errors.New("not implemented")
+}
+
 type StubValidator struct{}
 
 func (s *StubValidator) Validate(request any) error {
@@ -537,7 +1040,7 @@ func TestRoleBusinessLogicProtection(t *testing.T) {
 					AddRoleRequest{Name: ""},
 				).Return(common.RequestValidationError{Message: "name cannot be empty"}).Once()
 
-				_, err := svc.Add(context.Background(), "")
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: ""})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -551,7 +1054,7 @@ func TestRoleBusinessLogicProtection(t *testing.T) {
 					AddRoleRequest{Name: "A"},
 				).Return(common.RequestValidationError{Message: "name too short"}).Once()
 
-				_, err := svc.Add(context.Background(), "A")
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: "A"})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -566,7 +1069,7 @@ func TestRoleBusinessLogicProtection(t *testing.T) {
 					AddRoleRequest{Name: longName},
 				).Return(common.RequestValidationError{Message: "name too long"}).Once()
 
-				_, err := svc.Add(context.Background(), longName)
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: longName})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -721,7 +1224,7 @@ func TestRoleValidationErrorTypes(t *testing.T) {
 		{
 			name: "add_validation_error",
 			testFunc: func() error {
-				_, err := svc.Add(context.Background(), "")
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: ""})
 				return err
 			},
 		},
@@ -808,7 +1311,7 @@ func TestRoleBusinessLogicProtectionDetailed(t *testing.T) {
 			testFunc: func(repo *MockRepo, svc *Service) error {
 				validator := svc.validator.(*MockValidator)
 				validator.On("ValidateWithCustomMessages", AddRoleRequest{Name: "   "}).Return(common.RequestValidationError{Message: "name cannot be only whitespace"}).Once()
-				_, err := svc.Add(context.Background(), "   ")
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: "   "})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -824,7 +1327,7 @@ func TestRoleBusinessLogicProtectionDetailed(t *testing.T) {
 					entity := args.Get(1).(*Entity)
 					entity.Id = 1
 				})
-				_, err := svc.Add(context.Background(), "Администратор")
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: "Администратор"})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -854,7 +1357,7 @@ func TestRoleBusinessLogicProtectionDetailed(t *testing.T) {
 					entity := args.Get(1).(*Entity)
 					entity.Id = 1
 				})
-				_, err := svc.Add(context.Background(), "AB") // Минимально допустимое имя
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: "AB"}) // Минимально допустимое имя
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -871,7 +1374,7 @@ func TestRoleBusinessLogicProtectionDetailed(t *testing.T) {
 					entity := args.Get(1).(*Entity)
 					entity.Id = 1
 				})
-				_, err := svc.Add(context.Background(), maxName)
+				_, err := svc.Add(context.Background(), AddRoleRequest{Name: maxName})
 				validator.AssertExpectations(t)
 				return err
 			},
@@ -953,7 +1456,7 @@ func TestRoleEdgeCasesValidation(t *testing.T) {
 				entity.Id = 1
 			}).Once()
 
-			_, err := svc.Add(context.Background(), name)
+			_, err := svc.Add(context.Background(), AddRoleRequest{Name: name})
 			validator.AssertExpectations(t)
 			a.NoError(err, "Name with special characters should be valid: %s", name)
 		}
@@ -996,3 +1499,162 @@ func TestRoleEdgeCasesValidation(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 }
+
+func TestRoleService_AddParents(t *testing.T) {
+	a := assert.New(t)
+
+	setup := func(links []ParentLink, violations []SodViolationEntity) (*Service, *MockRepo) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(nil)
+		repo.On("FindById", mock.Anything, mock.AnythingOfType("int64")).Return(Entity{}, nil)
+		repo.On("FindByIds", mock.Anything, mock.Anything).Return([]Entity{{Id: 1}, {Id: 2}, {Id: 3}}, nil)
+		repo.On("AddParents", mock.Anything, mock.AnythingOfType("int64"), mock.Anything, mock.AnythingOfType("time.Time")).
+			Return(links, violations, nil)
+		return NewService(repo, validator), repo
+	}
+
+	t.Run("should add parent when hierarchy stays acyclic", func(t *testing.T) {
+		// 3 -> 2 уже есть, добавляем 2 -> 1
+		svc, repo := setup([]ParentLink{{RoleId: 3, ParentId: 2}}, nil)
+		repo.On("FindParents", mock.Anything, int64(2)).Return([]Entity{{Id: 1, Name: "engineer"}}, nil)
+
+		got, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{1}})
+
+		a.Nil(err)
+		a.Equal([]Response{{Id: 1, Name: "engineer"}}, got)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject indirect cycle", func(t *testing.T) {
+		// 3 -> 2 -> 1 уже есть, связь 1 -> 3 замкнет цикл
+		svc, repo := setup([]ParentLink{{RoleId: 3, ParentId: 2}, {RoleId: 2, ParentId: 1}}, nil)
+
+		_, err := svc.AddParents(context.Background(), 1, AddParentsRequest{ParentIds: []int64{3}})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Contains(err.Error(), "1 -> 3 -> 2 -> 1")
+		repo.AssertNotCalled(t, "FindParents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject role as its own parent", func(t *testing.T) {
+		svc, repo := setup(nil, nil)
+
+		_, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{2}})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Contains(err.Error(), "2 -> 2")
+		repo.AssertNotCalled(t, "FindParents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject inheritance violating sod rule", func(t *testing.T) {
+		// сотрудник 5 с ролью 2 получил бы через родителя 1 роль, запрещенную вместе с уже имеющейся
+		svc, repo := setup(nil, []SodViolationEntity{{EmployeeId: 5, EmployeeName: "Ivan", RuleName: "payments"}})
+
+		_, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{1}})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		a.Contains(err.Error(), "'payments' by employee 5 (Ivan)")
+		repo.AssertNotCalled(t, "FindParents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(nil)
+		repo.On("FindById", mock.Anything, int64(2)).Return(Entity{Id: 2}, nil)
+		repo.On("FindByIds", mock.Anything, []int64{1}).Return([]Entity{{Id: 1}}, nil)
+		repo.On("AddParents", mock.Anything, int64(2), []int64{1}, mock.AnythingOfType("time.Time")).
+			Return([]ParentLink(nil), []SodViolationEntity(nil), errors.New("db down"))
+		svc := NewService(repo, validator)
+
+		_, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{1}})
+
+		a.True(errors.As(err, &common.RepositoryError{}))
+		a.Contains(err.Error(), "db down")
+	})
+
+	t.Run("should report missing parent roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(nil)
+		repo.On("FindById", mock.Anything, int64(2)).Return(Entity{Id: 2}, nil)
+		repo.On("FindByIds", mock.Anything, []int64{1, 5}).Return([]Entity{{Id: 1}}, nil)
+		svc := NewService(repo, validator)
+
+		_, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{1, 5}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		a.Contains(err.Error(), "[5]")
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(nil)
+		repo.On("FindById", mock.Anything, int64(2)).Return(Entity{}, sql.ErrNoRows)
+		svc := NewService(repo, validator)
+
+		_, err := svc.AddParents(context.Background(), 2, AddParentsRequest{ParentIds: []int64{1}})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestRoleService_Restore(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should restore deleted role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockValidator))
+		repo.On("Restore", mock.Anything, int64(2), mock.AnythingOfType("time.Time")).Return(Entity{Id: 2, Name: "legacy", Version: 3}, nil)
+
+		got, err := svc.Restore(context.Background(), 2)
+
+		a.Nil(err)
+		a.Equal(int64(3), got.Version)
+		a.Nil(got.DeletedAt)
+	})
+
+	t.Run("should return not found when role is not deleted", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, new(MockValidator))
+		repo.On("Restore", mock.Anything, int64(2), mock.AnythingOfType("time.Time")).Return(Entity{}, sql.ErrNoRows)
+
+		_, err := svc.Restore(context.Background(), 2)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestRoleService_PurgeDeleted(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should purge roles deleted before retention period", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(nil)
+		svc := NewService(repo, validator)
+		expectedBefore := time.Now().AddDate(0, 0, -30)
+		repo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return before.Sub(expectedBefore).Abs() < time.Minute
+		})).Return(int64(5), nil)
+
+		got, err := svc.PurgeDeleted(context.Background(), PurgeDeletedRequest{OlderThanDays: 30})
+
+		a.Nil(err)
+		a.Equal(int64(5), got.Purged)
+	})
+
+	t.Run("should not purge with invalid retention", func(t *testing.T) {
+		repo := new(MockRepo)
+		validator := new(MockValidator)
+		validator.On("ValidateWithCustomMessages", mock.Anything).Return(errors.New("older_than_days must be at least 1"))
+		svc := NewService(repo, validator)
+
+		_, err := svc.PurgeDeleted(context.Background(), PurgeDeletedRequest{})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		repo.AssertNotCalled(t, "PurgeDeleted", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,249 @@
+package sod
+
+import (
+	"context"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/web"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Controller структура контроллера для работы с правилами разделения обязанностей
+type Controller struct {
+	server     *web.Server
+	sodService Svc
+	logger     *common.Logger
+}
+
+// Svc интерфейс сервиса для работы с правилами разделения обязанностей
+type Svc interface {
+	Add(ctx context.Context, request AddRuleRequest) (Response, error)                 // создание правила
+	FindById(ctx context.Context, id int64) (Response, error)                          // правило по ID
+	FindAll(ctx context.Context) ([]Response, error)                                   // все правила
+	Update(ctx context.Context, id int64, request UpdateRuleRequest) (Response, error) // изменение правила
+	DeleteById(ctx context.Context, id int64) error                                    // удаление правила
+	FindViolations(ctx context.Context) ([]ViolationResponse, error)                   // отчет о нарушениях
+}
+
+// NewController создает новый экземпляр контроллера правил разделения обязанностей
+func NewController(server *web.Server, sodService Svc, logger *common.Logger) *Controller {
+	return &Controller{
+		server:     server,
+		sodService: sodService,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты для работы с правилами разделения обязанностей
+func (c *Controller) RegisterRoutes() {
+	api := c.server.GroupApiV1
+
+	// /violations регистрируется раньше /:id
+	api.Post("/sod-rules", c.server.Require(web.SodRuleWrite), c.CreateSodRule)
+	api.Get("/sod-rules", c.server.Require(web.SodRuleRead), c.GetAllSodRules)
+	api.Get("/sod-rules/violations", c.server.Require(web.SodRuleRead), c.GetSodViolations)
+	api.Get("/sod-rules/:id", c.server.Require(web.SodRuleRead), c.GetSodRule)
+	api.Put("/sod-rules/:id", c.server.Require(web.SodRuleWrite), c.UpdateSodRule)
+	api.Delete("/sod-rules/:id", c.server.Require(web.SodRuleDelete), c.DeleteSodRule)
+}
+
+// CreateSodRule создает правило разделения обязанностей
+// @Summary Создать правило разделения обязанностей
+// @Description Запретить совмещать две роли. В режиме prevent (по умолчанию) назначение, нарушающее правило, отклоняется,
+// @Description в режиме detect - допускается и попадает в отчет о нарушениях.
+// @Description Правило в режиме prevent отклоняется (409), если обе роли уже действуют у кого-то из сотрудников
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body sod.AddRuleRequest true "данные правила"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /sod-rules [post]
+func (c *Controller) CreateSodRule(ctx *fiber.Ctx) error {
+	var req AddRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create sod rule: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.sodService.Add(ctx.Context(), req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "create sod rule: failed to create sod rule", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning created sod rule")
+	}
+	return nil
+}
+
+// GetAllSodRules получает все правила разделения обязанностей
+// @Summary Получить все правила разделения обязанностей
+// @Description Получить список правил с ролями, которые запрещено совмещать
+// @Tags sod
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /sod-rules [get]
+func (c *Controller) GetAllSodRules(ctx *fiber.Ctx) error {
+	resp, err := c.sodService.FindAll(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get all sod rules: failed to find sod rules", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning sod rules")
+	}
+	return nil
+}
+
+// GetSodViolations получает отчет о нарушениях правил разделения обязанностей
+// @Summary Получить отчет о нарушениях разделения обязанностей
+// @Description Получить сотрудников, у которых одновременно действуют роли, совмещать которые запрещено (с учетом наследования ролей).
+// @Description В отчет попадают нарушения правил обоих режимов, в том числе возникшие до создания правила
+// @Tags sod
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 500 {object} common.ResponseExample
+// @Router /sod-rules/violations [get]
+func (c *Controller) GetSodViolations(ctx *fiber.Ctx) error {
+	resp, err := c.sodService.FindViolations(ctx.Context())
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get sod violations: failed to find violations", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning sod violations")
+	}
+	return nil
+}
+
+// GetSodRule получает правило разделения обязанностей по ID
+// @Summary Получить правило разделения обязанностей по ID
+// @Description Получить правило по его идентификатору
+// @Tags sod
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID правила"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /sod-rules/{id} [get]
+func (c *Controller) GetSodRule(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid sod rule id")
+	}
+
+	resp, err := c.sodService.FindById(ctx.Context(), id)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "get sod rule: failed to find sod rule", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning sod rule")
+	}
+	return nil
+}
+
+// UpdateSodRule изменяет правило разделения обязанностей
+// @Summary Изменить правило разделения обязанностей
+// @Description Изменить название, описание, роли или режим правила; как и при создании, правило в режиме prevent
+// @Description отклоняется (409), если обе роли уже действуют у кого-то из сотрудников
+// @Tags sod
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID правила"
+// @Param request body sod.UpdateRuleRequest true "данные правила"
+// @Success 200 {object} common.ResponseExample
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Failure 409 {object} common.ResponseExample "Conflict"
+// @Router /sod-rules/{id} [put]
+func (c *Controller) UpdateSodRule(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid sod rule id")
+	}
+
+	var req UpdateRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update sod rule: invalid JSON", zap.Error(err))
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	}
+
+	resp, err := c.sodService.Update(ctx.Context(), id, req)
+	if err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "update sod rule: failed to update sod rule", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	if err := common.OkResponse(ctx, resp); err != nil {
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, "error returning updated sod rule")
+	}
+	return nil
+}
+
+// DeleteSodRule удаляет правило разделения обязанностей
+// @Summary Удалить правило разделения обязанностей
+// @Description Удалить правило; ранее отклоненные им назначения не восстанавливаются
+// @Tags sod
+// @Security BearerAuth
+// @Param id path int true "ID правила"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Failure 404 {object} common.ResponseExample
+// @Router /sod-rules/{id} [delete]
+func (c *Controller) DeleteSodRule(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, "invalid sod rule id")
+	}
+
+	if err := c.sodService.DeleteById(ctx.Context(), id); err != nil {
+		c.logger.ErrorCtx(ctx.Context(), "delete sod rule: failed to delete sod rule", zap.Error(err))
+		return handleError(ctx, err)
+	}
+
+	ctx.Status(fiber.StatusNoContent)
+	return nil
+}
+
+// handleError централизованная обработка ошибок с соответствующими HTTP статусами
+func handleError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.As(err, &common.RequestValidationError{}):
+		return common.ErrResponse(ctx, fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &common.AlreadyExistsError{}), errors.As(err, &common.ConflictError{}):
+		return common.ErrResponse(ctx, fiber.StatusConflict, err.Error())
+	case errors.As(err, &common.NotFoundError{}):
+		return common.ErrResponse(ctx, fiber.StatusNotFound, err.Error())
+	default:
+		return common.ErrResponse(ctx, fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,163 @@
+package sod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"idm/inner/common"
+	"idm/inner/web"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSodService - полный мок для интерфейса Svc
+type MockSodService struct {
+	mock.Mock
+}
+
+func (m *MockSodService) Add(ctx context.Context, request AddRuleRequest) (Response, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockSodService) FindById(ctx context.Context, id int64) (Response, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockSodService) FindAll(ctx context.Context) ([]Response, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Response), args.Error(1)
+}
+
+func (m *MockSodService) Update(ctx context.Context, id int64, request UpdateRuleRequest) (Response, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(Response), args.Error(1)
+}
+
+func (m *MockSodService) DeleteById(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSodService) FindViolations(ctx context.Context) ([]ViolationResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]ViolationResponse), args.Error(1)
+}
+
+func setupTest(t *testing.T) (*fiber.App, *MockSodService) {
+	t.Helper()
+
+	logger := common.NewTestLogger()
+	server := web.NewServer(logger, web.AuthConfig{})
+
+	mockService := new(MockSodService)
+	NewController(server, mockService, logger).RegisterRoutes()
+	return server.App, mockService
+}
+
+// createAuthRequest создает HTTP-запрос с токеном, содержащим заданные роли
+func createAuthRequest(t *testing.T, method, url string, body interface{}, roles []string) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+web.GenerateTestToken(roles))
+	return req
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+	os.Exit(m.Run())
+}
+
+func TestCreateSodRule(t *testing.T) {
+	t.Run("should create rule for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		request := AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7}
+		svc.On("Add", mock.Anything, request).Return(Response{Id: 1, Name: "payments", Mode: ModePrevent}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/sod-rules", request, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/sod-rules", AddRuleRequest{Name: "payments"}, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+
+	t.Run("should return 409 for duplicate rule", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("Add", mock.Anything, mock.Anything).Return(Response{}, common.AlreadyExistsError{Message: "sod rule already exists"})
+
+		resp, err := app.Test(createAuthRequest(t, "POST", "/api/v1/sod-rules", AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7}, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 409, resp.StatusCode)
+	})
+}
+
+func TestGetSodViolations(t *testing.T) {
+	t.Run("should return violations report for admin", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("FindViolations", mock.Anything).Return([]ViolationResponse{{EmployeeId: 5, RuleId: 1, RuleName: "payments"}}, nil)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/sod-rules/violations", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var result common.Response[[]ViolationResponse]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "payments", result.Data[0].RuleName)
+		svc.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 403 for user", func(t *testing.T) {
+		app, svc := setupTest(t)
+
+		resp, err := app.Test(createAuthRequest(t, "GET", "/api/v1/sod-rules/violations", nil, []string{web.IdmUser}))
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Empty(t, svc.Calls)
+	})
+}
+
+func TestDeleteSodRule(t *testing.T) {
+	t.Run("should delete rule", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("DeleteById", mock.Anything, int64(1)).Return(nil)
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/sod-rules/1", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("should return 404 for missing rule", func(t *testing.T) {
+		app, svc := setupTest(t)
+		svc.On("DeleteById", mock.Anything, int64(9)).Return(common.NotFoundError{Message: "sod rule with id 9 not found"})
+
+		resp, err := app.Test(createAuthRequest(t, "DELETE", "/api/v1/sod-rules/9", nil, []string{web.IdmAdmin}))
+		assert.NoError(t, err)
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
@@ -0,0 +1,99 @@
+package sod
+
+import "time"
+
+// Режимы правила разделения обязанностей
+const (
+	ModePrevent = "prevent" // назначение, нарушающее правило, отклоняется
+	ModeDetect  = "detect"  // нарушение допускается и попадает в отчет
+)
+
+// Entity представляет правило разделения обязанностей (токсичное сочетание двух ролей) в базе данных
+type Entity struct {
+	Id          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	RoleAId     int64     `db:"role_a_id"`
+	RoleBId     int64     `db:"role_b_id"`
+	Mode        string    `db:"mode"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// RuleEntity представляет правило вместе с названиями ролей
+type RuleEntity struct {
+	Entity
+	RoleAName string `db:"role_a_name"`
+	RoleBName string `db:"role_b_name"`
+}
+
+// toResponse преобразует RuleEntity в Response
+func (e *RuleEntity) toResponse() Response {
+	return Response{
+		Id:          e.Id,
+		Name:        e.Name,
+		Description: e.Description,
+		Roles:       [2]RoleRef{{Id: e.RoleAId, Name: e.RoleAName}, {Id: e.RoleBId, Name: e.RoleBName}},
+		Mode:        e.Mode,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+// ViolationEntity представляет сотрудника, у которого действуют обе роли правила
+type ViolationEntity struct {
+	EmployeeId   int64  `db:"employee_id"`
+	EmployeeName string `db:"employee_name"`
+	RuleId       int64  `db:"rule_id"`
+	RuleName     string `db:"rule_name"`
+	Mode         string `db:"mode"`
+	RoleAId      int64  `db:"role_a_id"`
+	RoleAName    string `db:"role_a_name"`
+	RoleBId      int64  `db:"role_b_id"`
+	RoleBName    string `db:"role_b_name"`
+}
+
+// HolderEntity представляет сотрудника, у которого действуют обе роли правила
+type HolderEntity struct {
+	Id   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// toResponse преобразует ViolationEntity в ViolationResponse
+func (e *ViolationEntity) toResponse() ViolationResponse {
+	return ViolationResponse{
+		EmployeeId:   e.EmployeeId,
+		EmployeeName: e.EmployeeName,
+		RuleId:       e.RuleId,
+		RuleName:     e.RuleName,
+		Mode:         e.Mode,
+		Roles:        [2]RoleRef{{Id: e.RoleAId, Name: e.RoleAName}, {Id: e.RoleBId, Name: e.RoleBName}},
+	}
+}
+
+// RoleRef представляет роль в составе правила
+type RoleRef struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Response представляет ответ API для правила разделения обязанностей
+type Response struct {
+	Id          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Roles       [2]RoleRef `json:"roles"`
+	Mode        string     `json:"mode"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ViolationResponse представляет нарушение правила в отчете
+type ViolationResponse struct {
+	EmployeeId   int64      `json:"employee_id"`
+	EmployeeName string     `json:"employee_name"`
+	RuleId       int64      `json:"rule_id"`
+	RuleName     string     `json:"rule_name"`
+	Mode         string     `json:"mode"`
+	Roles        [2]RoleRef `json:"roles"`
+}
@@ -0,0 +1,170 @@
+package sod
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Repository представляет репозиторий для работы с правилами разделения обязанностей
+type Repository struct {
+	db *sqlx.DB
+}
+
+// NewRepository создает новый экземпляр Repository
+func NewRepository(database *sqlx.DB) *Repository {
+	return &Repository{db: database}
+}
+
+// selectRules - выборка правил с названиями ролей
+const selectRules = `SELECT s.*, ra.name AS role_a_name, rb.name AS role_b_name
+	FROM sod_rule s
+	JOIN role ra ON ra.id = s.role_a_id
+	JOIN role rb ON rb.id = s.role_b_id`
+
+// FindExistingRoleIds возвращает те ID из списка, для которых существуют неудаленные роли
+func (r *Repository) FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error) {
+	var res []int64
+	err := r.db.SelectContext(ctx, &res, "SELECT id FROM role WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(roleIds))
+	return res, err
+}
+
+// RuleCheck проверяет правило по сотрудникам, которые нарушили бы его как новое запрещающее правило;
+// ошибка проверки отменяет изменение
+type RuleCheck func(holders []HolderEntity) error
+
+// Add сохраняет новое правило. Правило в режиме prevent проверяется check в одной транзакции с сохранением
+// под блокировкой sod_rule, поэтому параллельные назначения ролей не могут обойти проверку
+func (r *Repository) Add(ctx context.Context, e *Entity, check RuleCheck) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE sod_rule IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if e.Mode == ModePrevent {
+		if err := findHolders(ctx, tx, e, check); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	query := `INSERT INTO sod_rule (name, description, role_a_id, role_b_id, mode, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	err = tx.QueryRowContext(ctx, query, e.Name, e.Description, e.RoleAId, e.RoleBId, e.Mode, e.CreatedAt, e.UpdatedAt).Scan(&e.Id)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// FindById возвращает правило по ID
+func (r *Repository) FindById(ctx context.Context, id int64) (res RuleEntity, err error) {
+	err = r.db.GetContext(ctx, &res, selectRules+" WHERE s.id = $1", id)
+	return res, err
+}
+
+// FindAll возвращает все правила
+func (r *Repository) FindAll(ctx context.Context) (res []RuleEntity, err error) {
+	err = r.db.SelectContext(ctx, &res, selectRules+" ORDER BY s.name, s.id")
+	return res, err
+}
+
+// Update изменяет правило. Возвращает sql.ErrNoRows, если правило не найдено.
+// Если правило становится запрещающим или запрещает другую пару ролей, оно проверяется check так же, как в Add
+func (r *Repository) Update(ctx context.Context, e *Entity, check RuleCheck) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE sod_rule IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	var current Entity
+	if err := tx.GetContext(ctx, &current, "SELECT * FROM sod_rule WHERE id = $1", e.Id); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	prevented := current.Mode == ModePrevent && current.RoleAId == e.RoleAId && current.RoleBId == e.RoleBId
+	if e.Mode == ModePrevent && !prevented {
+		if err := findHolders(ctx, tx, e, check); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	query := `UPDATE sod_rule SET name = $1, description = $2, role_a_id = $3, role_b_id = $4, mode = $5, updated_at = $6
+		WHERE id = $7 RETURNING created_at`
+	err = tx.QueryRowContext(ctx, query, e.Name, e.Description, e.RoleAId, e.RoleBId, e.Mode, e.UpdatedAt, e.Id).Scan(&e.CreatedAt)
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// findHolders вызывает check для сотрудников, у которых действуют обе роли правила e.
+// Роли учитываются с иерархией, назначения - вместе с еще не начавшимися, как при проверке назначения ролей
+func findHolders(ctx context.Context, tx *sqlx.Tx, e *Entity, check RuleCheck) error {
+	query := `WITH RECURSIVE held AS (
+			SELECT er.employee_id, er.role_id
+			FROM employee_role er
+			JOIN employee e ON e.id = er.employee_id AND e.deleted_at IS NULL
+			JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+			WHERE er.valid_until IS NULL OR er.valid_until > now()
+			UNION
+			SELECT h.employee_id, rp.parent_id
+			FROM role_parent rp
+			JOIN held h ON rp.role_id = h.role_id
+			JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+		)
+		SELECT e.id, e.name
+		FROM held a
+		JOIN held b ON b.employee_id = a.employee_id AND b.role_id = $2
+		JOIN employee e ON e.id = a.employee_id
+		WHERE a.role_id = $1
+		ORDER BY e.id`
+	var holders []HolderEntity
+	if err := tx.SelectContext(ctx, &holders, query, e.RoleAId, e.RoleBId); err != nil {
+		return err
+	}
+	return check(holders)
+}
+
+// DeleteById удаляет правило, возвращает количество удаленных строк
+func (r *Repository) DeleteById(ctx context.Context, id int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM sod_rule WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindViolations возвращает сотрудников, у которых одновременно действуют обе роли правила.
+// Роли учитываются с иерархией: роль, унаследованная через назначенную, тоже считается действующей
+func (r *Repository) FindViolations(ctx context.Context) (res []ViolationEntity, err error) {
+	query := `WITH RECURSIVE held AS (
+			SELECT er.employee_id, er.role_id
+			FROM employee_role er
+			JOIN employee e ON e.id = er.employee_id AND e.deleted_at IS NULL
+			JOIN role r ON r.id = er.role_id AND r.deleted_at IS NULL
+			WHERE (er.valid_from IS NULL OR er.valid_from <= now()) AND (er.valid_until IS NULL OR er.valid_until > now())
+			UNION
+			SELECT h.employee_id, rp.parent_id
+			FROM role_parent rp
+			JOIN held h ON rp.role_id = h.role_id
+			JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+		)
+		SELECT e.id AS employee_id, e.name AS employee_name, s.id AS rule_id, s.name AS rule_name, s.mode,
+			s.role_a_id, ra.name AS role_a_name, s.role_b_id, rb.name AS role_b_name
+		FROM sod_rule s
+		JOIN held a ON a.role_id = s.role_a_id
+		JOIN held b ON b.role_id = s.role_b_id AND b.employee_id = a.employee_id
+		JOIN employee e ON e.id = a.employee_id
+		JOIN role ra ON ra.id = s.role_a_id
+		JOIN role rb ON rb.id = s.role_b_id
+		ORDER BY s.name, s.id, e.id`
+	err = r.db.SelectContext(ctx, &res, query)
+	return res, err
+}
@@ -0,0 +1,20 @@
+package sod
+
+// AddRuleRequest используется для создания правила разделения обязанностей.
+// Порядок ролей не важен; без mode правило запрещающее (prevent)
+type AddRuleRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	RoleAId     int64  `json:"role_a_id" validate:"required,gt=0"`
+	RoleBId     int64  `json:"role_b_id" validate:"required,gt=0"`
+	Mode        string `json:"mode" validate:"omitempty,oneof=prevent detect"`
+}
+
+// UpdateRuleRequest используется для изменения правила разделения обязанностей
+type UpdateRuleRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=500"`
+	RoleAId     int64  `json:"role_a_id" validate:"required,gt=0"`
+	RoleBId     int64  `json:"role_b_id" validate:"required,gt=0"`
+	Mode        string `json:"mode" validate:"omitempty,oneof=prevent detect"`
+}
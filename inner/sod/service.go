@@ -0,0 +1,241 @@
+package sod
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"idm/inner/common"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Service структура, которая инкапсулирует бизнес-логику правил разделения обязанностей
+type Service struct {
+	repo      Repo
+	validator Validator
+}
+
+// Repo интерфейс репозитория для правил разделения обязанностей
+type Repo interface {
+	FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error)
+	Add(ctx context.Context, e *Entity, check RuleCheck) error
+	FindById(ctx context.Context, id int64) (RuleEntity, error)
+	FindAll(ctx context.Context) ([]RuleEntity, error)
+	Update(ctx context.Context, e *Entity, check RuleCheck) error
+	DeleteById(ctx context.Context, id int64) (int64, error)
+	FindViolations(ctx context.Context) ([]ViolationEntity, error)
+}
+
+type Validator interface {
+	Validate(any) error
+	ValidateWithCustomMessages(any) error
+}
+
+// rolesConstraint - уникальный индекс на пару ролей правила
+const rolesConstraint = "sod_rule_roles_key"
+
+// NewService функция-конструктор для Service
+func NewService(repo Repo, validator Validator) *Service {
+	return &Service{
+		repo:      repo,
+		validator: validator,
+	}
+}
+
+func (svc *Service) ValidateRequest(request any) error {
+	if err := svc.validator.ValidateWithCustomMessages(request); err != nil {
+		return common.RequestValidationError{Message: err.Error()}
+	}
+	return nil
+}
+
+// Add создает правило, запрещающее совмещать две роли.
+// Запрещающее (prevent) правило не создается, пока у сотрудников действуют обе его роли
+func (svc *Service) Add(ctx context.Context, request AddRuleRequest) (Response, error) {
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkRoles(ctx, request.RoleAId, request.RoleBId); err != nil {
+		return Response{}, err
+	}
+
+	now := time.Now()
+	entity := newEntity(request.Name, request.Description, request.RoleAId, request.RoleBId, request.Mode)
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	// проверка выполняется репозиторием в транзакции сохранения, ее ошибка возвращается как есть
+	var rejected error
+	check := func(holders []HolderEntity) error {
+		rejected = checkHolders(entity, holders)
+		return rejected
+	}
+	err := svc.repo.Add(ctx, entity, check)
+	if rejected != nil {
+		return Response{}, rejected
+	}
+	if constraint, ok := common.UniqueViolationConstraint(err); ok {
+		return Response{}, uniqueViolationError(constraint, request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: "error adding sod rule", Err: err}
+	}
+	return svc.FindById(ctx, entity.Id)
+}
+
+// FindById возвращает правило по ID
+func (svc *Service) FindById(ctx context.Context, id int64) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid sod rule id: %d", id)}
+	}
+
+	entity, err := svc.repo.FindById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("sod rule with id %d not found", id)}
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error finding sod rule with id %d", id), Err: err}
+	}
+	return entity.toResponse(), nil
+}
+
+// FindAll возвращает все правила
+func (svc *Service) FindAll(ctx context.Context) ([]Response, error) {
+	entities, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding all sod rules", Err: err}
+	}
+
+	responses := make([]Response, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// Update изменяет правило. Как и в Add, правило не может стать запрещающим для пары ролей,
+// которые уже действуют у сотрудников
+func (svc *Service) Update(ctx context.Context, id int64, request UpdateRuleRequest) (Response, error) {
+	if id <= 0 {
+		return Response{}, common.RequestValidationError{Message: fmt.Sprintf("invalid sod rule id: %d", id)}
+	}
+	if err := svc.ValidateRequest(request); err != nil {
+		return Response{}, err
+	}
+	if err := svc.checkRoles(ctx, request.RoleAId, request.RoleBId); err != nil {
+		return Response{}, err
+	}
+
+	entity := newEntity(request.Name, request.Description, request.RoleAId, request.RoleBId, request.Mode)
+	entity.Id = id
+	entity.UpdatedAt = time.Now()
+	var rejected error
+	check := func(holders []HolderEntity) error {
+		rejected = checkHolders(entity, holders)
+		return rejected
+	}
+	err := svc.repo.Update(ctx, entity, check)
+	if rejected != nil {
+		return Response{}, rejected
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Response{}, common.NotFoundError{Message: fmt.Sprintf("sod rule with id %d not found", id)}
+	}
+	if constraint, ok := common.UniqueViolationConstraint(err); ok {
+		return Response{}, uniqueViolationError(constraint, request.Name)
+	}
+	if err != nil {
+		return Response{}, common.RepositoryError{Message: fmt.Sprintf("error updating sod rule with id %d", id), Err: err}
+	}
+	return svc.FindById(ctx, id)
+}
+
+// DeleteById удаляет правило
+func (svc *Service) DeleteById(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return common.RequestValidationError{Message: fmt.Sprintf("invalid sod rule id: %d", id)}
+	}
+
+	deleted, err := svc.repo.DeleteById(ctx, id)
+	if err != nil {
+		return common.RepositoryError{Message: fmt.Sprintf("error deleting sod rule with id %d", id), Err: err}
+	}
+	if deleted == 0 {
+		return common.NotFoundError{Message: fmt.Sprintf("sod rule with id %d not found", id)}
+	}
+	return nil
+}
+
+// FindViolations возвращает отчет о сотрудниках, у которых действуют роли, совмещать которые запрещено.
+// В отчет попадают нарушения правил обоих режимов, включая возникшие до создания правила
+func (svc *Service) FindViolations(ctx context.Context) ([]ViolationResponse, error) {
+	entities, err := svc.repo.FindViolations(ctx)
+	if err != nil {
+		return nil, common.RepositoryError{Message: "error finding sod violations", Err: err}
+	}
+
+	responses := make([]ViolationResponse, len(entities))
+	for i, entity := range entities {
+		responses[i] = entity.toResponse()
+	}
+	return responses, nil
+}
+
+// checkRoles проверяет, что правило связывает две разные существующие роли
+func (svc *Service) checkRoles(ctx context.Context, roleAId, roleBId int64) error {
+	if roleAId == roleBId {
+		return common.RequestValidationError{Message: "role_a_id and role_b_id must be different roles"}
+	}
+
+	existing, err := svc.repo.FindExistingRoleIds(ctx, []int64{roleAId, roleBId})
+	if err != nil {
+		return common.RepositoryError{Message: "error finding roles by ids", Err: err}
+	}
+	for _, id := range []int64{roleAId, roleBId} {
+		if !slices.Contains(existing, id) {
+			return common.NotFoundError{Message: fmt.Sprintf("role with id %d not found", id)}
+		}
+	}
+	return nil
+}
+
+// checkHolders проверяет, что ни у одного сотрудника не действуют обе роли запрещающего правила
+func checkHolders(e *Entity, holders []HolderEntity) error {
+	if len(holders) == 0 {
+		return nil
+	}
+	names := make([]string, len(holders))
+	for i, holder := range holders {
+		names[i] = fmt.Sprintf("%d (%s)", holder.Id, holder.Name)
+	}
+	return common.ConflictError{
+		Message: fmt.Sprintf("sod rule '%s' would be violated by employees holding both roles: %s; revoke the roles or use detect mode",
+			e.Name, strings.Join(names, ", ")),
+	}
+}
+
+// newEntity создает правило с упорядоченной парой ролей и режимом prevent по умолчанию
+func newEntity(name, description string, roleAId, roleBId int64, mode string) *Entity {
+	if roleAId > roleBId {
+		roleAId, roleBId = roleBId, roleAId
+	}
+	if mode == "" {
+		mode = ModePrevent
+	}
+	return &Entity{
+		Name:        name,
+		Description: description,
+		RoleAId:     roleAId,
+		RoleBId:     roleBId,
+		Mode:        mode,
+	}
+}
+
+// uniqueViolationError сообщает, какое из уникальных полей правила уже занято
+func uniqueViolationError(constraint, name string) error {
+	if constraint == rolesConstraint {
+		return common.AlreadyExistsError{Message: "sod rule for this pair of roles already exists"}
+	}
+	return common.AlreadyExistsError{Message: fmt.Sprintf("sod rule with name '%s' already exists", name)}
+}
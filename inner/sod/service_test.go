@@ -0,0 +1,237 @@
+package sod
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"idm/inner/common"
+	"idm/inner/common/validator"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo - mock-объект репозитория правил разделения обязанностей
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) FindExistingRoleIds(ctx context.Context, roleIds []int64) ([]int64, error) {
+	args := m.Called(ctx, roleIds)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+// Add возвращает ошибку из ожидания, а без нее - результат проверки check на сотрудниках из ожидания
+func (m *MockRepo) Add(ctx context.Context, e *Entity, check RuleCheck) error {
+	args := m.Called(ctx, e)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	return check(args.Get(0).([]HolderEntity))
+}
+
+func (m *MockRepo) FindById(ctx context.Context, id int64) (RuleEntity, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(RuleEntity), args.Error(1)
+}
+
+func (m *MockRepo) FindAll(ctx context.Context) ([]RuleEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]RuleEntity), args.Error(1)
+}
+
+// Update возвращает ошибку из ожидания, а без нее - результат проверки check на сотрудниках из ожидания
+func (m *MockRepo) Update(ctx context.Context, e *Entity, check RuleCheck) error {
+	args := m.Called(ctx, e)
+	if err := args.Error(1); err != nil {
+		return err
+	}
+	return check(args.Get(0).([]HolderEntity))
+}
+
+func (m *MockRepo) DeleteById(ctx context.Context, id int64) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepo) FindViolations(ctx context.Context) ([]ViolationEntity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]ViolationEntity), args.Error(1)
+}
+
+func paymentsRule() RuleEntity {
+	return RuleEntity{
+		Entity:    Entity{Id: 1, Name: "payments", RoleAId: 3, RoleBId: 7, Mode: ModePrevent},
+		RoleAName: "payments-approver",
+		RoleBName: "payments-creator",
+	}
+}
+
+func TestSodService_Add(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should add rule with ordered roles and prevent mode", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, []int64{7, 3}).Return([]int64{3, 7}, nil)
+		repo.On("Add", mock.Anything, mock.MatchedBy(func(e *Entity) bool {
+			return e.RoleAId == 3 && e.RoleBId == 7 && e.Mode == ModePrevent
+		})).Return([]HolderEntity{}, nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*Entity).Id = 1
+		})
+		repo.On("FindById", mock.Anything, int64(1)).Return(paymentsRule(), nil)
+
+		got, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 7, RoleBId: 3})
+
+		a.Nil(err)
+		a.Equal(int64(1), got.Id)
+		a.Equal("payments-approver", got.Roles[0].Name)
+		a.Equal(ModePrevent, got.Mode)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject rule with the same role twice", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 3})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should reject unknown mode", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+
+		_, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7, Mode: "warn"})
+
+		a.True(errors.As(err, &common.RequestValidationError{}))
+		a.Empty(repo.Calls)
+	})
+
+	t.Run("should return not found for missing role", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, []int64{3, 7}).Return([]int64{3}, nil)
+
+		_, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+		a.Contains(err.Error(), "role with id 7")
+	})
+
+	t.Run("should return already exists for duplicate pair of roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, mock.Anything).Return([]int64{3, 7}, nil)
+		repo.On("Add", mock.Anything, mock.Anything).Return([]HolderEntity(nil), &pq.Error{Code: "23505", Constraint: rolesConstraint})
+
+		_, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7})
+
+		a.True(errors.As(err, &common.AlreadyExistsError{}))
+		a.Contains(err.Error(), "pair of roles")
+	})
+
+	t.Run("should reject prevent rule already violated by employees", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, mock.Anything).Return([]int64{3, 7}, nil)
+		repo.On("Add", mock.Anything, mock.Anything).Return([]HolderEntity{{Id: 5, Name: "Ivan"}}, nil)
+
+		_, err := svc.Add(context.Background(), AddRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		a.Contains(err.Error(), "5 (Ivan)")
+		repo.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSodService_Update(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should switch rule to detect mode", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		detected := paymentsRule()
+		detected.Mode = ModeDetect
+		repo.On("FindExistingRoleIds", mock.Anything, mock.Anything).Return([]int64{3, 7}, nil)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(e *Entity) bool { return e.Id == 1 && e.Mode == ModeDetect })).
+			Return([]HolderEntity{}, nil)
+		repo.On("FindById", mock.Anything, int64(1)).Return(detected, nil)
+
+		got, err := svc.Update(context.Background(), 1, UpdateRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7, Mode: ModeDetect})
+
+		a.Nil(err)
+		a.Equal(ModeDetect, got.Mode)
+	})
+
+	t.Run("should return not found for missing rule", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, mock.Anything).Return([]int64{3, 7}, nil)
+		repo.On("Update", mock.Anything, mock.Anything).Return([]HolderEntity(nil), sql.ErrNoRows)
+
+		_, err := svc.Update(context.Background(), 9, UpdateRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7})
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+
+	t.Run("should reject switching to prevent mode when rule is violated", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindExistingRoleIds", mock.Anything, mock.Anything).Return([]int64{3, 7}, nil)
+		repo.On("Update", mock.Anything, mock.Anything).Return([]HolderEntity{{Id: 5, Name: "Ivan"}}, nil)
+
+		_, err := svc.Update(context.Background(), 1, UpdateRuleRequest{Name: "payments", RoleAId: 3, RoleBId: 7, Mode: ModePrevent})
+
+		a.True(errors.As(err, &common.ConflictError{}))
+		a.Contains(err.Error(), "detect mode")
+	})
+}
+
+func TestSodService_DeleteById(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return not found when nothing deleted", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("DeleteById", mock.Anything, int64(9)).Return(int64(0), nil)
+
+		err := svc.DeleteById(context.Background(), 9)
+
+		a.True(errors.As(err, &common.NotFoundError{}))
+	})
+}
+
+func TestSodService_FindViolations(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("should return violations with roles", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindViolations", mock.Anything).Return([]ViolationEntity{{
+			EmployeeId: 5, EmployeeName: "John Doe", RuleId: 1, RuleName: "payments", Mode: ModeDetect,
+			RoleAId: 3, RoleAName: "payments-approver", RoleBId: 7, RoleBName: "payments-creator",
+		}}, nil)
+
+		got, err := svc.FindViolations(context.Background())
+
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(int64(5), got[0].EmployeeId)
+		a.Equal(RoleRef{Id: 7, Name: "payments-creator"}, got[0].Roles[1])
+	})
+
+	t.Run("should wrap repository error", func(t *testing.T) {
+		repo := new(MockRepo)
+		svc := NewService(repo, validator.New())
+		repo.On("FindViolations", mock.Anything).Return([]ViolationEntity(nil), errors.New("db down"))
+
+		_, err := svc.FindViolations(context.Background())
+
+		a.True(errors.As(err, &common.RepositoryError{}))
+	})
+}
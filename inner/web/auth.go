@@ -1,13 +1,16 @@
 package web
 
 import (
+	"errors"
 	"fmt"
-	jwtMiddleware "github.com/gofiber/contrib/jwt"
+	"github.com/MicahParks/keyfunc/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 	"idm/inner/common"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,7 +21,8 @@ const (
 )
 
 type IdmClaims struct {
-	RealmAccess RealmAccessClaims `json:"realm_access"`
+	RealmAccess       RealmAccessClaims `json:"realm_access"`
+	PreferredUsername string            `json:"preferred_username,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -26,32 +30,126 @@ type RealmAccessClaims struct {
 	Roles []string `json:"roles"`
 }
 
-var CreateAuthMiddleware = func(logger *common.Logger) fiber.Handler {
-	if secret := getTestSecret(); secret != "" {
-		config := jwtMiddleware.Config{
-			ContextKey:   JwtKey,
-			ErrorHandler: createJwtErrorHandler(logger),
-			Claims:       &IdmClaims{},
-			KeyFunc: func(token *jwt.Token) (interface{}, error) {
+// AuthConfig параметры проверки JWT-токенов
+type AuthConfig struct {
+	JwkUrl    string        // адрес JWKS для проверки подписи токенов
+	Issuer    string        // ожидаемый издатель токена (iss), пустое значение - не проверяется
+	Audience  string        // ожидаемая аудитория токена (aud), пустое значение - не проверяется
+	ClockSkew time.Duration // допустимое расхождение часов при проверке exp, nbf и iat
+}
+
+// NewAuthConfig формирует параметры проверки токенов из общей конфигурации приложения
+func NewAuthConfig(cfg common.Config) AuthConfig {
+	return AuthConfig{
+		JwkUrl:    cfg.KeycloakJwkUrl,
+		Issuer:    cfg.KeycloakIssuer,
+		Audience:  cfg.KeycloakAudience,
+		ClockSkew: cfg.AuthClockSkew,
+	}
+}
+
+// parserOptions возвращает опции проверки зарегистрированных claims токена
+func (cfg AuthConfig) parserOptions() []jwt.ParserOption {
+	var options []jwt.ParserOption
+	if cfg.ClockSkew > 0 {
+		options = append(options, jwt.WithLeeway(cfg.ClockSkew))
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	return options
+}
+
+var errMissingToken = errors.New("missing or malformed JWT")
+
+// CreateAuthMiddleware создает middleware, который проверяет подпись токена (через JWKS
+// или тестовый секрет AUTH_TEST_SECRET), срок действия с учетом ClockSkew, издателя и аудиторию
+var CreateAuthMiddleware = func(logger *common.Logger, cfg AuthConfig) fiber.Handler {
+	errorHandler := createJwtErrorHandler(logger)
+	parser := jwt.NewParser(cfg.parserOptions()...)
+	jwks := &jwksKeyFunc{url: cfg.JwkUrl}
+
+	return func(ctx *fiber.Ctx) error {
+		raw, err := extractBearerToken(ctx)
+		if err != nil {
+			return errorHandler(ctx, err)
+		}
+
+		// Тестовый секрет проверяется при каждом запросе, чтобы тесты могли задавать его динамически
+		keyFunc := jwks.Keyfunc
+		if secret := getTestSecret(); secret != "" {
+			keyFunc = func(token *jwt.Token) (interface{}, error) {
 				if token.Method.Alg() != "HS256" {
 					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 				}
 				return []byte(secret), nil
-			},
+			}
+		}
+
+		token, err := parser.ParseWithClaims(raw, &IdmClaims{}, keyFunc)
+		if err != nil || !token.Valid {
+			return errorHandler(ctx, err)
 		}
-		return jwtMiddleware.New(config)
+
+		ctx.Locals(JwtKey, token)
+		return ctx.Next()
 	}
-	config := jwtMiddleware.Config{
-		ContextKey:   JwtKey,
-		ErrorHandler: createJwtErrorHandler(logger),
-		JWKSetURLs:   []string{"http://localhost:9990/realms/idm/protocol/openid-connect/certs"},
-		Claims:       &IdmClaims{},
+}
+
+// Для обратной совместимости: middleware без проверки издателя и аудитории
+var AuthMiddleware = func(logger *common.Logger) fiber.Handler {
+	return CreateAuthMiddleware(logger, AuthConfig{})
+}
+
+// extractBearerToken извлекает токен из заголовка Authorization вида "Bearer <token>"
+func extractBearerToken(ctx *fiber.Ctx) (string, error) {
+	auth := ctx.Get(fiber.HeaderAuthorization)
+	const scheme = "Bearer"
+	if len(auth) <= len(scheme)+1 || !strings.EqualFold(auth[:len(scheme)], scheme) || auth[len(scheme)] != ' ' {
+		return "", errMissingToken
 	}
-	return jwtMiddleware.New(config)
+	return strings.TrimSpace(auth[len(scheme)+1:]), nil
 }
 
-// Для обратной совместимости
-var AuthMiddleware = CreateAuthMiddleware
+// jwksKeyFunc лениво загружает JWKS при первом запросе и повторяет загрузку, если она не удалась
+type jwksKeyFunc struct {
+	url  string
+	mu   sync.Mutex
+	jwks *keyfunc.JWKS
+}
+
+func (k *jwksKeyFunc) Keyfunc(token *jwt.Token) (interface{}, error) {
+	jwks, err := k.get()
+	if err != nil {
+		return nil, err
+	}
+	return jwks.Keyfunc(token)
+}
+
+func (k *jwksKeyFunc) get() (*keyfunc.JWKS, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.jwks != nil {
+		return k.jwks, nil
+	}
+	if k.url == "" {
+		return nil, errors.New("JWK set URL is not configured")
+	}
+	jwks, err := keyfunc.Get(k.url, keyfunc.Options{
+		RefreshInterval:   time.Hour,
+		RefreshRateLimit:  5 * time.Minute,
+		RefreshTimeout:    10 * time.Second,
+		RefreshUnknownKID: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JWK set: %w", err)
+	}
+	k.jwks = jwks
+	return jwks, nil
+}
 
 func createJwtErrorHandler(logger *common.Logger) fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
@@ -90,6 +188,19 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// GetClaims извлекает claims аутентифицированного субъекта, сохраненные middleware аутентификации
+func GetClaims(c *fiber.Ctx) (*IdmClaims, error) {
+	token, ok := c.Locals(JwtKey).(*jwt.Token)
+	if !ok || token == nil {
+		return nil, errors.New("missing or invalid token")
+	}
+	claims, ok := token.Claims.(*IdmClaims)
+	if !ok || claims == nil {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
+}
+
 // RequireRoles создает middleware для проверки ролей пользователя
 func RequireRoles(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
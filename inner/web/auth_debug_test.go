@@ -26,7 +26,7 @@ func TestAuthMiddlewareDebug(t *testing.T) {
 
 	// Применяем наш middleware динамически
 	app.Use(func(c *fiber.Ctx) error {
-		return CreateAuthMiddleware(logger)(c)
+		return CreateAuthMiddleware(logger, AuthConfig{})(c)
 	})
 
 	// Простой тестовый эндпоинт
@@ -6,8 +6,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -174,3 +176,120 @@ func TestAuthMiddleware(t *testing.T) {
 		assert.Equal(t, 401, resp.StatusCode)
 	})
 }
+
+func TestAuthMiddleware_RegisteredClaims(t *testing.T) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+
+	cfg := AuthConfig{
+		Issuer:    "http://keycloak/realms/idm",
+		Audience:  "idm",
+		ClockSkew: time.Minute,
+	}
+	app := fiber.New()
+	app.Use(CreateAuthMiddleware(common.NewTestLogger(), cfg))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"message": "success"})
+	})
+
+	sign := func(claims jwt.RegisteredClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, &IdmClaims{
+			RealmAccess:      RealmAccessClaims{Roles: []string{IdmUser}},
+			RegisteredClaims: claims,
+		})
+		signed, err := token.SignedString([]byte("testsecret"))
+		assert.NoError(t, err)
+		return signed
+	}
+	valid := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Audience:  jwt.ClaimStrings{"account", cfg.Audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		claims         func() jwt.RegisteredClaims
+		expectedStatus int
+	}{
+		{
+			name:           "should accept token with expected issuer and audience",
+			claims:         valid,
+			expectedStatus: 200,
+		},
+		{
+			name: "should reject token from another issuer",
+			claims: func() jwt.RegisteredClaims {
+				c := valid()
+				c.Issuer = "http://keycloak/realms/other"
+				return c
+			},
+			expectedStatus: 401,
+		},
+		{
+			name: "should reject token for another audience",
+			claims: func() jwt.RegisteredClaims {
+				c := valid()
+				c.Audience = jwt.ClaimStrings{"account"}
+				return c
+			},
+			expectedStatus: 401,
+		},
+		{
+			name: "should accept token expired within clock skew",
+			claims: func() jwt.RegisteredClaims {
+				c := valid()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Second))
+				return c
+			},
+			expectedStatus: 200,
+		},
+		{
+			name: "should reject token expired beyond clock skew",
+			claims: func() jwt.RegisteredClaims {
+				c := valid()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-2 * time.Minute))
+				return c
+			},
+			expectedStatus: 401,
+		},
+		{
+			name: "should reject token not valid yet beyond clock skew",
+			claims: func() jwt.RegisteredClaims {
+				c := valid()
+				c.NotBefore = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+				return c
+			},
+			expectedStatus: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+sign(tt.claims()))
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewAuthConfig(t *testing.T) {
+	cfg := common.Config{
+		KeycloakJwkUrl:   "http://keycloak/realms/idm/protocol/openid-connect/certs",
+		KeycloakIssuer:   "http://keycloak/realms/idm",
+		KeycloakAudience: "idm",
+		AuthClockSkew:    30 * time.Second,
+	}
+
+	assert.Equal(t, AuthConfig{
+		JwkUrl:    cfg.KeycloakJwkUrl,
+		Issuer:    cfg.KeycloakIssuer,
+		Audience:  cfg.KeycloakAudience,
+		ClockSkew: cfg.AuthClockSkew,
+	}, NewAuthConfig(cfg))
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"idm/inner/common"
+)
+
+// Authenticator провайдер аутентификации, альтернативный JWT (например, API-ключи)
+type Authenticator interface {
+	// Supports сообщает, содержит ли запрос учетные данные этого провайдера
+	Supports(ctx *fiber.Ctx) bool
+	// Authenticate проверяет учетные данные и возвращает claims аутентифицированного субъекта
+	Authenticate(ctx *fiber.Ctx) (*IdmClaims, error)
+}
+
+// AddAuthenticator подключает дополнительный провайдер аутентификации к группе /api.
+// Провайдеры опрашиваются в порядке добавления, JWT используется, если ни один из них не подошел
+func (s *Server) AddAuthenticator(authenticator Authenticator) {
+	s.authenticators = append(s.authenticators, authenticator)
+}
+
+// ChainAuthenticators создает middleware, которое передает запрос первому провайдеру,
+// поддерживающему его учетные данные, а при отсутствии такого - в fallback (JWT middleware).
+// Провайдеры запрашиваются через функцию, чтобы их можно было подключать после регистрации middleware
+func ChainAuthenticators(logger *common.Logger, fallback fiber.Handler, authenticators func() []Authenticator) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		for _, authenticator := range authenticators() {
+			if !authenticator.Supports(ctx) {
+				continue
+			}
+
+			claims, err := authenticator.Authenticate(ctx)
+			if err != nil {
+				// текст ошибки может содержать детали хранилища, поэтому клиенту он не возвращается
+				logger.ErrorCtx(ctx.Context(), "failed autentication", zap.Error(err))
+				return common.ErrResponse(ctx, fiber.StatusUnauthorized, "Unauthorized")
+			}
+
+			// Сохраняем claims в том же виде, что и JWT middleware, чтобы RequireRoles и контроллеры работали одинаково
+			ctx.Locals(JwtKey, &jwt.Token{Claims: claims, Valid: true})
+			return ctx.Next()
+		}
+		return fallback(ctx)
+	}
+}
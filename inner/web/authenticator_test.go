@@ -0,0 +1,94 @@
+package web
+
+import (
+	"errors"
+	"idm/inner/common"
+	"io"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAuthenticator - провайдер, принимающий запросы с заголовком X-Test-Key
+type stubAuthenticator struct {
+	roles []string
+	err   error
+}
+
+func (s *stubAuthenticator) Supports(ctx *fiber.Ctx) bool {
+	return ctx.Get("X-Test-Key") != ""
+}
+
+func (s *stubAuthenticator) Authenticate(_ *fiber.Ctx) (*IdmClaims, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &IdmClaims{RealmAccess: RealmAccessClaims{Roles: s.roles}}, nil
+}
+
+func TestChainAuthenticators(t *testing.T) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+
+	logger := common.NewTestLogger()
+	setup := func(authenticators ...Authenticator) *fiber.App {
+		app := fiber.New()
+		app.Use(ChainAuthenticators(logger, AuthMiddleware(logger), func() []Authenticator { return authenticators }))
+		app.Get("/admin-only", RequireRoles(IdmAdmin), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	t.Run("should authenticate with provider and apply its roles", func(t *testing.T) {
+		app := setup(&stubAuthenticator{roles: []string{IdmAdmin}})
+
+		req := httptest.NewRequest("GET", "/admin-only", nil)
+		req.Header.Set("X-Test-Key", "key")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("should forbid when provider roles are insufficient", func(t *testing.T) {
+		app := setup(&stubAuthenticator{roles: []string{IdmUser}})
+
+		req := httptest.NewRequest("GET", "/admin-only", nil)
+		req.Header.Set("X-Test-Key", "key")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode)
+	})
+
+	t.Run("should reject when provider fails", func(t *testing.T) {
+		app := setup(&stubAuthenticator{err: errors.New("pq: connection refused")})
+
+		req := httptest.NewRequest("GET", "/admin-only", nil)
+		req.Header.Set("X-Test-Key", "key")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "Unauthorized")
+		assert.NotContains(t, string(body), "connection refused")
+	})
+
+	t.Run("should fall back to JWT when no provider supports request", func(t *testing.T) {
+		app := setup(&stubAuthenticator{roles: []string{IdmUser}})
+
+		req := httptest.NewRequest("GET", "/admin-only", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestToken([]string{IdmAdmin}))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest("GET", "/admin-only", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+	})
+}
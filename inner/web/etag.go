@@ -0,0 +1,62 @@
+package web
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag формирует значение заголовка ETag по версии записи
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfMatchVersion извлекает ожидаемую версию записи из заголовка If-Match.
+// Возвращает 0, если заголовок не задан или равен "*" (условие не проверяется).
+// If-Match требует строгого сравнения (RFC 9110, 13.1.1), поэтому слабые ETag вида W/"3" отклоняются
+func IfMatchVersion(c *fiber.Ctx) (int64, error) {
+	value := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if strings.HasPrefix(value, "W/") {
+		return 0, errors.New("invalid If-Match header: weak etag is not allowed")
+	}
+	version, err := parseETag(value)
+	if err != nil {
+		return 0, errors.New("invalid If-Match header")
+	}
+	return version, nil
+}
+
+// NotModified проверяет заголовок If-None-Match и, если он совпадает с etag,
+// отвечает 304 Not Modified. Возвращает true, если ответ уже сформирован
+func NotModified(c *fiber.Ctx, etag string) bool {
+	value := strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch))
+	if value == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(value, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			c.Set(fiber.HeaderETag, etag)
+			c.Status(fiber.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag разбирает строгий ETag вида "3" в номер версии
+func parseETag(value string) (int64, error) {
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, errors.New("etag must be a quoted string")
+	}
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors.New("etag must contain a positive version")
+	}
+	return version, nil
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		expected    int64
+		expectError bool
+	}{
+		{name: "no header", header: "", expected: 0},
+		{name: "wildcard", header: "*", expected: 0},
+		{name: "strong etag", header: `"7"`, expected: 7},
+		{name: "weak etag", header: `W/"7"`, expectError: true},
+		{name: "unquoted value", header: "7", expectError: true},
+		{name: "not a number", header: `"abc"`, expectError: true},
+		{name: "zero version", header: `"0"`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Put("/", func(c *fiber.Ctx) error {
+				version, err := IfMatchVersion(c)
+				if tt.expectError {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, version)
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+			_, err := app.Test(req)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		expectedStatus int
+	}{
+		{name: "no header", header: "", expectedStatus: 200},
+		{name: "matching etag", header: `"3"`, expectedStatus: 304},
+		{name: "matching weak etag in list", header: `"1", W/"3"`, expectedStatus: 304},
+		{name: "wildcard", header: "*", expectedStatus: 304},
+		{name: "stale etag", header: `"2"`, expectedStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if NotModified(c, ETag(3)) {
+					return nil
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("If-None-Match", tt.header)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
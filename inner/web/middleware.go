@@ -11,3 +11,18 @@ func RegisterMiddleware(app *fiber.App) {
 	app.Use(recover.New())
 	app.Use(requestid.New())
 }
+
+// skipAuditKey - ключ Locals, которым обработчик отмечает запрос, не изменяющий данные
+const skipAuditKey = "skipAudit"
+
+// SkipAudit отмечает текущий запрос как не изменяющий данные (чтение через POST, пробный запуск),
+// чтобы журнал аудита его не фиксировал
+func SkipAudit(c *fiber.Ctx) {
+	c.Locals(skipAuditKey, true)
+}
+
+// AuditSkipped сообщает, отметил ли обработчик текущий запрос через SkipAudit
+func AuditSkipped(c *fiber.Ctx) bool {
+	skipped, _ := c.Locals(skipAuditKey).(bool)
+	return skipped
+}
@@ -0,0 +1,227 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"idm/inner/common"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Permission право на выполнение группы операций API
+type Permission string
+
+const (
+	EmployeeRead        Permission = "employee:read"
+	EmployeeWrite       Permission = "employee:write"
+	EmployeeDelete      Permission = "employee:delete"
+	EmployeeRestore     Permission = "employee:restore"
+	RoleRead            Permission = "role:read"
+	RoleWrite           Permission = "role:write"
+	RoleDelete          Permission = "role:delete"
+	RoleRestore         Permission = "role:restore"
+	AssignmentRead      Permission = "assignment:read"
+	AssignmentWrite     Permission = "assignment:write"
+	PermissionRead      Permission = "permission:read"
+	PermissionWrite     Permission = "permission:write"
+	PermissionDelete    Permission = "permission:delete"
+	OrgUnitRead         Permission = "org-unit:read"
+	OrgUnitWrite        Permission = "org-unit:write"
+	OrgUnitDelete       Permission = "org-unit:delete"
+	AccessRequestCreate Permission = "access-request:create"
+	AccessRequestRead   Permission = "access-request:read"
+	AccessRequestDecide Permission = "access-request:decide"
+	AccessRequestManage Permission = "access-request:manage"
+	SodRuleRead         Permission = "sod-rule:read"
+	SodRuleWrite        Permission = "sod-rule:write"
+	SodRuleDelete       Permission = "sod-rule:delete"
+	CertificationRead   Permission = "certification:read"
+	CertificationReview Permission = "certification:review"
+	CertificationManage Permission = "certification:manage"
+	ApiKeyManage        Permission = "api-key:manage"
+	PolicyRead          Permission = "policy:read"
+	AuditRead           Permission = "audit:read"
+)
+
+// Policy декларативная политика доступа: каждому праву сопоставлен список ролей, которым оно выдано
+type Policy struct {
+	permissions map[Permission][]string
+}
+
+// PolicyRule представляет одно правило политики в ответе API
+type PolicyRule struct {
+	Permission Permission `json:"permission"`
+	Roles      []string   `json:"roles"`
+}
+
+// defaultPolicy используется сервером, для которого политика не задана
+var defaultPolicy = DefaultPolicy()
+
+// DefaultPolicy возвращает политику по умолчанию: чтение доступно IDM_ADMIN и IDM_USER, изменения - только IDM_ADMIN
+func DefaultPolicy() *Policy {
+	readers := []string{IdmAdmin, IdmUser}
+	admins := []string{IdmAdmin}
+	return &Policy{permissions: map[Permission][]string{
+		EmployeeRead:     readers,
+		EmployeeWrite:    admins,
+		EmployeeDelete:   admins,
+		EmployeeRestore:  admins,
+		RoleRead:         readers,
+		RoleWrite:        admins,
+		RoleDelete:       admins,
+		RoleRestore:      admins,
+		AssignmentRead:   readers,
+		AssignmentWrite:  admins,
+		PermissionRead:   readers,
+		PermissionWrite:  admins,
+		PermissionDelete: admins,
+		OrgUnitRead:      readers,
+		OrgUnitWrite:     admins,
+		OrgUnitDelete:    admins,
+		// заявки подают и рассматривают обычные пользователи; доступ к конкретной заявке проверяет сервис
+		AccessRequestCreate: readers,
+		AccessRequestRead:   readers,
+		AccessRequestDecide: readers,
+		AccessRequestManage: admins,
+		SodRuleRead:         admins,
+		SodRuleWrite:        admins,
+		SodRuleDelete:       admins,
+		CertificationRead:   admins,
+		CertificationReview: admins,
+		CertificationManage: admins,
+		ApiKeyManage:        admins,
+		PolicyRead:          admins,
+		AuditRead:           admins,
+	}}
+}
+
+// LoadPolicy загружает политику из JSON-файла вида {"employee:read": ["IDM_ADMIN", "HR_VIEWER"]}.
+// Права, не указанные в файле, остаются такими же, как в политике по умолчанию
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file: %w", err)
+	}
+
+	var overrides map[Permission][]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("error parsing policy file: %w", err)
+	}
+
+	policy := DefaultPolicy()
+	for permission, roles := range overrides {
+		// неизвестное право почти всегда означает опечатку, которая молча оставила бы доступ закрытым
+		if _, ok := policy.permissions[permission]; !ok {
+			return nil, fmt.Errorf("unknown permission in policy file: %s", permission)
+		}
+		policy.permissions[permission] = roles
+	}
+	return policy, nil
+}
+
+// Allows проверяет, выдано ли право хотя бы одной из ролей
+func (p *Policy) Allows(roles []string, permission Permission) bool {
+	for _, role := range roles {
+		if slices.Contains(p.permissions[permission], role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles возвращает отсортированный список ролей, которым политика выдает хотя бы одно право
+func (p *Policy) Roles() []string {
+	var roles []string
+	for _, granted := range p.permissions {
+		for _, role := range granted {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Rules возвращает правила политики, отсортированные по названию права
+func (p *Policy) Rules() []PolicyRule {
+	rules := make([]PolicyRule, 0, len(p.permissions))
+	for permission, roles := range p.permissions {
+		rules = append(rules, PolicyRule{Permission: permission, Roles: roles})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Permission < rules[j].Permission })
+	return rules
+}
+
+// SetPolicy заменяет политику доступа сервера
+func (s *Server) SetPolicy(policy *Policy) {
+	s.policy = policy
+}
+
+// Policy возвращает действующую политику доступа сервера (по умолчанию - DefaultPolicy)
+func (s *Server) Policy() *Policy {
+	if s.policy == nil {
+		return defaultPolicy
+	}
+	return s.policy
+}
+
+// Require создает middleware, пропускающее запрос, только если политика выдает право одной из ролей токена.
+// Политика читается при каждом запросе, поэтому SetPolicy можно вызывать и после регистрации маршрутов
+func (s *Server) Require(permission Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := GetClaims(c)
+		if err != nil {
+			return common.ErrResponse(c, fiber.StatusUnauthorized, err.Error())
+		}
+		if !s.Policy().Allows(claims.RealmAccess.Roles, permission) {
+			return common.ErrResponse(c, fiber.StatusForbidden, "Permission denied")
+		}
+		if hook, ok := c.Locals(authorizedHookKey).(func()); ok {
+			hook()
+		}
+		return c.Next()
+	}
+}
+
+// authorizedHookKey - ключ Locals для функции, которую Require вызывает после успешной проверки прав
+const authorizedHookKey = "authorizedHook"
+
+// OnAuthorized регистрирует функцию, которую Require вызовет для текущего запроса после успешной проверки прав
+// и до обработчика маршрута. Так middleware группы, работающее раньше Require, не обращается к данным
+// от имени клиента, которому доступ не разрешен
+func OnAuthorized(c *fiber.Ctx, hook func()) {
+	c.Locals(authorizedHookKey, hook)
+}
+
+// Allowed проверяет, выдает ли политика право одной из ролей токена текущего запроса.
+// Используется внутри обработчиков, когда право нужно только для части запроса (например, для фильтра)
+func (s *Server) Allowed(c *fiber.Ctx, permission Permission) bool {
+	claims, err := GetClaims(c)
+	return err == nil && s.Policy().Allows(claims.RealmAccess.Roles, permission)
+}
+
+// RegisterPolicyRoutes регистрирует маршрут просмотра действующей политики доступа
+func (s *Server) RegisterPolicyRoutes() {
+	s.GroupApiV1.Get("/policy", s.Require(PolicyRead), s.GetPolicy)
+}
+
+// GetPolicy возвращает действующую политику доступа
+// @Summary Получить политику доступа
+// @Description Получить список прав и ролей, которым они выданы
+// @Tags policy
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} common.ResponseExample
+// @Failure 401 {object} common.ResponseExample "Unauthorized"
+// @Failure 403 {object} common.ResponseExample "Forbidden"
+// @Router /policy [get]
+func (s *Server) GetPolicy(c *fiber.Ctx) error {
+	if err := common.OkResponse(c, s.Policy().Rules()); err != nil {
+		return common.ErrResponse(c, fiber.StatusInternalServerError, "error returning policy")
+	}
+	return nil
+}
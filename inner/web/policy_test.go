@@ -0,0 +1,128 @@
+package web
+
+import (
+	"encoding/json"
+	"idm/inner/common"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	policy := DefaultPolicy()
+
+	assert.True(t, policy.Allows([]string{IdmUser}, EmployeeRead))
+	assert.True(t, policy.Allows([]string{IdmAdmin}, EmployeeWrite))
+	assert.False(t, policy.Allows([]string{IdmUser}, EmployeeWrite))
+	assert.False(t, policy.Allows([]string{IdmUser}, ApiKeyManage))
+	assert.False(t, policy.Allows(nil, RoleRead))
+	assert.Equal(t, []string{IdmAdmin, IdmUser}, policy.Roles())
+}
+
+func TestLoadPolicy(t *testing.T) {
+	writeFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("should override listed permissions and keep defaults for the rest", func(t *testing.T) {
+		path := writeFile(t, `{"employee:write": ["IDM_ADMIN", "HR_MANAGER"]}`)
+
+		policy, err := LoadPolicy(path)
+
+		require.NoError(t, err)
+		assert.True(t, policy.Allows([]string{"HR_MANAGER"}, EmployeeWrite))
+		assert.False(t, policy.Allows([]string{"HR_MANAGER"}, EmployeeDelete))
+		assert.True(t, policy.Allows([]string{IdmUser}, EmployeeRead))
+	})
+
+	t.Run("should reject unknown permission", func(t *testing.T) {
+		path := writeFile(t, `{"employee:raed": ["IDM_USER"]}`)
+
+		_, err := LoadPolicy(path)
+
+		assert.ErrorContains(t, err, "unknown permission")
+	})
+
+	t.Run("should reject malformed file", func(t *testing.T) {
+		path := writeFile(t, `{"employee:read": "IDM_USER"`)
+
+		_, err := LoadPolicy(path)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail when file is missing", func(t *testing.T) {
+		_, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestServerRequire(t *testing.T) {
+	os.Setenv("AUTH_TEST_SECRET", "testsecret")
+	defer os.Unsetenv("AUTH_TEST_SECRET")
+
+	server := NewServer(common.NewTestLogger(), AuthConfig{})
+	server.GroupApiV1.Get("/protected", server.Require(EmployeeWrite), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	server.RegisterPolicyRoutes()
+
+	doRequest := func(path string, roles []string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if roles != nil {
+			req.Header.Set("Authorization", "Bearer "+GenerateTestToken(roles))
+		}
+		resp, err := server.App.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("should allow role granted by default policy", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, doRequest("/api/v1/protected", []string{IdmAdmin}))
+	})
+
+	t.Run("should deny role not granted by policy", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, doRequest("/api/v1/protected", []string{IdmUser}))
+	})
+
+	t.Run("should return 401 without token", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusUnauthorized, doRequest("/api/v1/protected", nil))
+	})
+
+	t.Run("should apply policy replaced after route registration", func(t *testing.T) {
+		policy := DefaultPolicy()
+		policy.permissions[EmployeeWrite] = []string{IdmUser}
+		server.SetPolicy(policy)
+		defer server.SetPolicy(nil)
+
+		assert.Equal(t, fiber.StatusOK, doRequest("/api/v1/protected", []string{IdmUser}))
+		assert.Equal(t, fiber.StatusForbidden, doRequest("/api/v1/protected", []string{IdmAdmin}))
+	})
+
+	t.Run("should return effective policy", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/policy", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestToken([]string{IdmAdmin}))
+
+		resp, err := server.App.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body common.Response[[]PolicyRule]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.True(t, body.Success)
+		assert.Len(t, body.Data, len(DefaultPolicy().permissions))
+		assert.Equal(t, AccessRequestCreate, body.Data[0].Permission)
+	})
+
+	t.Run("should deny policy view to user", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusForbidden, doRequest("/api/v1/policy", []string{IdmUser}))
+	})
+}
@@ -14,6 +14,9 @@ type Server struct {
 	GroupApiV1    fiber.Router
 	GroupInternal fiber.Router
 	logger        *common.Logger
+
+	authenticators []Authenticator
+	policy         *Policy
 }
 
 type AuthMiddlewareInterface interface {
@@ -21,7 +24,7 @@ type AuthMiddlewareInterface interface {
 }
 
 // функция-конструктор
-func NewServer(logger *common.Logger) *Server {
+func NewServer(logger *common.Logger, authCfg AuthConfig) *Server {
 	// создаём новый веб-сервер
 	app := fiber.New()
 	// 👉 подключаем middleware
@@ -32,18 +35,19 @@ func NewServer(logger *common.Logger) *Server {
 	groupInternal := app.Group("/internal")
 	groupApi := app.Group("/api")
 
-	// Применяем AuthMiddleware к API группе динамически (проверяется при каждом запросе)
-	groupApi.Use(func(c *fiber.Ctx) error {
-		return CreateAuthMiddleware(logger)(c)
-	})
-
-	groupApiV1 := groupApi.Group("/v1")
-
-	return &Server{
+	server := &Server{
 		App:           app,
 		GroupApi:      groupApi,
-		GroupApiV1:    groupApiV1,
 		GroupInternal: groupInternal,
 		logger:        logger,
 	}
+
+	// Применяем аутентификацию к API группе: сначала подключенные провайдеры (API-ключи),
+	// затем AuthMiddleware (тестовый секрет проверяется при каждом запросе)
+	groupApi.Use(ChainAuthenticators(logger, CreateAuthMiddleware(logger, authCfg), func() []Authenticator {
+		return server.authenticators
+	}))
+
+	server.GroupApiV1 = groupApi.Group("/v1")
+	return server
 }
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE employee_role (
+  employee_id BIGINT NOT NULL REFERENCES employee (id) ON DELETE CASCADE,
+  role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  PRIMARY KEY (employee_id, role_id)
+);
+
+CREATE INDEX employee_role_role_id_idx ON employee_role (role_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS employee_role;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE employee ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE role ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE role DROP COLUMN IF EXISTS version;
+ALTER TABLE employee DROP COLUMN IF EXISTS version;
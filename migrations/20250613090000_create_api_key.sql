@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE api_key (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  name TEXT NOT NULL,
+  prefix TEXT NOT NULL,
+  key_hash TEXT NOT NULL UNIQUE,
+  roles TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  revoked_at TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_key;
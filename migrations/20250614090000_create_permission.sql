@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE permission (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE,
+  description TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ DEFAULT now(),
+  updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE role_permission (
+  role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  permission_id BIGINT NOT NULL REFERENCES permission (id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE INDEX role_permission_permission_id_idx ON role_permission (permission_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS role_permission;
+DROP TABLE IF EXISTS permission;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE role_parent (
+  role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  parent_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  PRIMARY KEY (role_id, parent_id),
+  CHECK (role_id <> parent_id)
+);
+
+CREATE INDEX role_parent_parent_id_idx ON role_parent (parent_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS role_parent;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE audit_log (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  actor TEXT NOT NULL,
+  actor_name TEXT NOT NULL DEFAULT '',
+  action TEXT NOT NULL,
+  route TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id BIGINT,
+  before_state JSONB,
+  after_state JSONB,
+  request_body JSONB,
+  request_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX audit_log_occurred_at_idx ON audit_log (occurred_at);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id);
+CREATE INDEX audit_log_actor_idx ON audit_log (actor);
+
+-- журнал аудита только пополняется: изменение и удаление записей запрещены
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
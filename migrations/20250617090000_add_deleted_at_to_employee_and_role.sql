@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE employee ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE role ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- частичные индексы: удаленных записей мало, а ищутся они только при восстановлении и очистке
+CREATE INDEX employee_deleted_at_idx ON employee (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX role_deleted_at_idx ON role (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS role_deleted_at_idx;
+DROP INDEX IF EXISTS employee_deleted_at_idx;
+ALTER TABLE role DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE employee DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- дубликаты имен без учета регистра, появившиеся до введения ограничения, миграция не исправляет:
+-- имена ролей используются в политиках и внешних системах, поэтому оператор должен разрешить дубликаты сам
+-- +goose StatementBegin
+DO $$
+DECLARE
+  duplicates TEXT;
+BEGIN
+  SELECT string_agg(format('%s %s (ids %s)', kind, name, ids), '; ') INTO duplicates
+  FROM (
+    SELECT 'employee' AS kind, min(name) AS name, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+    FROM employee GROUP BY lower(name) HAVING count(*) > 1
+    UNION ALL
+    SELECT 'role', min(name), string_agg(id::TEXT, ', ' ORDER BY id)
+    FROM role GROUP BY lower(name) HAVING count(*) > 1
+  ) d;
+  IF duplicates IS NOT NULL THEN
+    RAISE EXCEPTION 'names must be unique ignoring case, rename duplicates before migrating: %', duplicates;
+  END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+-- индексы не частичные: имя удаленной записи остается занятым, чтобы ее можно было восстановить
+CREATE UNIQUE INDEX employee_name_lower_key ON employee (lower(name));
+CREATE UNIQUE INDEX role_name_lower_key ON role (lower(name));
+
+-- +goose Down
+DROP INDEX IF EXISTS role_name_lower_key;
+DROP INDEX IF EXISTS employee_name_lower_key;
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE employee
+  ADD COLUMN email TEXT NOT NULL DEFAULT '',
+  ADD COLUMN login TEXT NOT NULL DEFAULT '',
+  ADD COLUMN employee_number TEXT NOT NULL DEFAULT '',
+  ADD COLUMN first_name TEXT NOT NULL DEFAULT '',
+  ADD COLUMN last_name TEXT NOT NULL DEFAULT '',
+  ADD COLUMN job_title TEXT NOT NULL DEFAULT '',
+  ADD COLUMN phone TEXT NOT NULL DEFAULT '',
+  ADD COLUMN hire_date DATE,
+  ADD COLUMN termination_date DATE,
+  ADD COLUMN status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pre_hire', 'active', 'suspended', 'terminated'));
+
+-- логин необязателен, но если указан, то уникален без учета регистра
+CREATE UNIQUE INDEX employee_login_lower_key ON employee (lower(login)) WHERE login <> '';
+CREATE INDEX employee_email_lower_idx ON employee (lower(email)) WHERE email <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS employee_email_lower_idx;
+DROP INDEX IF EXISTS employee_login_lower_key;
+ALTER TABLE employee
+  DROP COLUMN IF EXISTS status,
+  DROP COLUMN IF EXISTS termination_date,
+  DROP COLUMN IF EXISTS hire_date,
+  DROP COLUMN IF EXISTS phone,
+  DROP COLUMN IF EXISTS job_title,
+  DROP COLUMN IF EXISTS last_name,
+  DROP COLUMN IF EXISTS first_name,
+  DROP COLUMN IF EXISTS employee_number,
+  DROP COLUMN IF EXISTS login,
+  DROP COLUMN IF EXISTS email;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE org_unit (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  name TEXT NOT NULL,
+  parent_id BIGINT REFERENCES org_unit (id) ON DELETE RESTRICT,
+  created_at TIMESTAMPTZ DEFAULT now(),
+  updated_at TIMESTAMPTZ DEFAULT now(),
+  CHECK (parent_id <> id)
+);
+
+CREATE INDEX org_unit_parent_id_idx ON org_unit (parent_id);
+-- у подразделений одного родителя имена не повторяются; корневые подразделения считаются детьми родителя 0
+CREATE UNIQUE INDEX org_unit_parent_name_key ON org_unit (COALESCE(parent_id, 0), lower(name));
+
+ALTER TABLE employee
+  ADD COLUMN org_unit_id BIGINT REFERENCES org_unit (id) ON DELETE RESTRICT,
+  ADD COLUMN manager_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  ADD CONSTRAINT employee_manager_not_self CHECK (manager_id <> id);
+
+CREATE INDEX employee_org_unit_id_idx ON employee (org_unit_id);
+CREATE INDEX employee_manager_id_idx ON employee (manager_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS employee_manager_id_idx;
+DROP INDEX IF EXISTS employee_org_unit_id_idx;
+ALTER TABLE employee
+  DROP CONSTRAINT IF EXISTS employee_manager_not_self,
+  DROP COLUMN IF EXISTS manager_id,
+  DROP COLUMN IF EXISTS org_unit_id;
+DROP TABLE IF EXISTS org_unit;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- срок действия назначения роли: NULL означает "без ограничения" с соответствующей стороны
+ALTER TABLE employee_role
+  ADD COLUMN valid_from TIMESTAMPTZ,
+  ADD COLUMN valid_until TIMESTAMPTZ,
+  ADD CONSTRAINT employee_role_validity_check CHECK (valid_until > valid_from);
+
+-- индекс для фоновой очистки просроченных назначений
+CREATE INDEX employee_role_valid_until_idx ON employee_role (valid_until) WHERE valid_until IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS employee_role_valid_until_idx;
+ALTER TABLE employee_role
+  DROP CONSTRAINT IF EXISTS employee_role_validity_check,
+  DROP COLUMN IF EXISTS valid_until,
+  DROP COLUMN IF EXISTS valid_from;
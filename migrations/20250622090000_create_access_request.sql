@@ -0,0 +1,25 @@
+-- +goose Up
+-- заявки - след согласования доступа для аудита: при окончательном удалении сотрудника или роли ссылка обнуляется
+CREATE TABLE access_request (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  requester_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  employee_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  role_id BIGINT REFERENCES role (id) ON DELETE SET NULL,
+  justification TEXT NOT NULL DEFAULT '',
+  valid_until TIMESTAMPTZ,
+  -- руководитель автора заявки на момент ее создания; NULL - заявку рассматривают только администраторы
+  approver_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  decided_by TEXT NOT NULL DEFAULT '',
+  decision_reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  decided_at TIMESTAMPTZ
+);
+
+-- на одну роль для одного сотрудника может быть только одна нерассмотренная заявка
+CREATE UNIQUE INDEX access_request_pending_key ON access_request (employee_id, role_id) WHERE status = 'pending';
+CREATE INDEX access_request_approver_pending_idx ON access_request (approver_id) WHERE status = 'pending';
+CREATE INDEX access_request_requester_id_idx ON access_request (requester_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS access_request;
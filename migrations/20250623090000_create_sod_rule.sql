@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE sod_rule (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  role_a_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  role_b_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+  -- prevent - назначение, нарушающее правило, отклоняется; detect - нарушение только попадает в отчет
+  mode TEXT NOT NULL DEFAULT 'prevent' CHECK (mode IN ('prevent', 'detect')),
+  created_at TIMESTAMPTZ DEFAULT now(),
+  updated_at TIMESTAMPTZ DEFAULT now(),
+  -- пара ролей хранится упорядоченной, чтобы одно сочетание нельзя было задать дважды в разном порядке
+  CHECK (role_a_id < role_b_id)
+);
+
+CREATE UNIQUE INDEX sod_rule_name_lower_key ON sod_rule (lower(name));
+CREATE UNIQUE INDEX sod_rule_roles_key ON sod_rule (role_a_id, role_b_id);
+CREATE INDEX sod_rule_role_b_id_idx ON sod_rule (role_b_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS sod_rule;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE certification_campaign (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  name TEXT NOT NULL,
+  -- охват кампании: все назначения роли или назначения сотрудников подразделения (включая вложенные)
+  scope_type TEXT NOT NULL CHECK (scope_type IN ('role', 'org_unit')),
+  scope_id BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+  launched_by TEXT NOT NULL DEFAULT '',
+  closed_by TEXT NOT NULL DEFAULT '',
+  summary JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  closed_at TIMESTAMPTZ
+);
+
+-- снимок назначений на момент запуска кампании; имена сохраняются, чтобы итоги не зависели от переименований.
+-- При окончательном удалении сотрудника или роли ссылка обнуляется, а история кампании остается
+CREATE TABLE certification_item (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  campaign_id BIGINT NOT NULL REFERENCES certification_campaign (id) ON DELETE CASCADE,
+  employee_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  employee_name TEXT NOT NULL,
+  role_id BIGINT REFERENCES role (id) ON DELETE SET NULL,
+  role_name TEXT NOT NULL,
+  assigned_at TIMESTAMPTZ,
+  valid_until TIMESTAMPTZ,
+  decision TEXT CHECK (decision IN ('keep', 'revoke')),
+  comment TEXT NOT NULL DEFAULT '',
+  reviewed_by TEXT NOT NULL DEFAULT '',
+  reviewed_at TIMESTAMPTZ,
+  UNIQUE (campaign_id, employee_id, role_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS certification_item;
+DROP TABLE IF EXISTS certification_campaign;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE role
+  ADD COLUMN description TEXT NOT NULL DEFAULT '',
+  ADD COLUMN owner_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+  ADD COLUMN risk_level TEXT NOT NULL DEFAULT 'low'
+    CHECK (risk_level IN ('low', 'medium', 'high', 'critical')),
+  -- существующие роли до сих пор можно было запрашивать, поэтому они остаются доступными для заявок;
+  -- новые роли по умолчанию недоступны для самостоятельного запроса
+  ADD COLUMN requestable BOOLEAN NOT NULL DEFAULT true,
+  ADD COLUMN application TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE role ALTER COLUMN requestable SET DEFAULT false;
+
+CREATE INDEX role_owner_id_idx ON role (owner_id);
+CREATE INDEX role_application_lower_idx ON role (lower(application)) WHERE application <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS role_application_lower_idx;
+DROP INDEX IF EXISTS role_owner_id_idx;
+ALTER TABLE role
+  DROP COLUMN IF EXISTS application,
+  DROP COLUMN IF EXISTS requestable,
+  DROP COLUMN IF EXISTS risk_level,
+  DROP COLUMN IF EXISTS owner_id,
+  DROP COLUMN IF EXISTS description;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- полнотекстовый индекс по анкетным данным; выражение должно совпадать с searchVector в репозитории сотрудников,
+-- иначе планировщик не сможет использовать индекс
+CREATE INDEX employee_search_idx ON employee USING gin ((
+  setweight(to_tsvector('simple', name || ' ' || first_name || ' ' || last_name), 'A') ||
+  setweight(to_tsvector('simple', login || ' ' || email || ' ' || employee_number), 'B') ||
+  setweight(to_tsvector('simple', job_title), 'C')
+));
+
+-- триграммный индекс для поиска по имени с опечатками
+CREATE INDEX employee_name_trgm_idx ON employee USING gin (name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS employee_name_trgm_idx;
+DROP INDEX IF EXISTS employee_search_idx;
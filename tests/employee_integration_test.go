@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"idm/inner/common"
+	"idm/inner/common/validator"
 	"idm/inner/database"
 	"idm/inner/employee"
 	"idm/inner/role"
@@ -224,6 +225,56 @@ func TestEmployee_TransactionalMethods_Integration(t *testing.T) {
 }
 
 // Тест пагинации
+func TestEmployee_Import_Integration(t *testing.T) {
+	cfg := common.GetConfig(".env.tests")
+	db := database.ConnectDbWithCfg(cfg)
+	defer db.Close()
+
+	employeeRepo := employee.NewRepository(db)
+	fixture, err := NewFixture(employeeRepo, role.NewRepository(db), db)
+	if err != nil {
+		t.Fatal("Не удалось создать fixture:", err)
+	}
+	if err = fixture.CleanupDatabase(); err != nil {
+		t.Fatal("Не удалось очистить базу данных:", err)
+	}
+	defer func() {
+		if err := fixture.CleanupDatabase(); err != nil {
+			t.Errorf("Failed to cleanup database: %v", err)
+		}
+	}()
+
+	existing := fixture.MustEmployee("Maria Sidorova")
+	svc := employee.NewService(employeeRepo, validator.New())
+	rows := []employee.ImportRow{
+		{Line: 2, Request: employee.AddEmployeeRequest{Name: "Ivan Petrov", Profile: employee.Profile{Login: "ipetrov"}}},
+		{Line: 3, Request: employee.AddEmployeeRequest{Name: "maria sidorova", Profile: employee.Profile{JobTitle: "Accountant"}}},
+	}
+	ctx := context.Background()
+
+	t.Run("dry run does not change employees", func(t *testing.T) {
+		report, err := svc.Import(ctx, rows, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		all, err := employeeRepo.FindAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("import creates and updates employees", func(t *testing.T) {
+		report, err := svc.Import(ctx, rows, false)
+		assert.NoError(t, err)
+		assert.True(t, report.Applied)
+		updated, err := employeeRepo.FindById(ctx, existing)
+		assert.NoError(t, err)
+		assert.Equal(t, "Accountant", updated.JobTitle)
+		all, err := employeeRepo.FindAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+}
+
 func TestEmployee_Pagination_Integration(t *testing.T) {
 	// Устанавливаем тестовый секрет для JWT ПЕРЕД всем остальным
 	os.Setenv("AUTH_TEST_SECRET", "testsecret")
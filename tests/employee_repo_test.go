@@ -7,6 +7,7 @@ import (
 	"idm/inner/employee"
 	"idm/inner/role"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -94,11 +95,56 @@ func TestEmployeeRepository(t *testing.T) {
 		id1 := fixture.MustEmployee("Delete 1")
 		id2 := fixture.MustEmployee("Delete 2")
 		ctx := context.Background()
-		err := employeeRepository.DeleteByIds(ctx, []int64{id1, id2})
+		deleted, err := employeeRepository.DeleteByIds(ctx, []int64{id1, id2})
 		a.Nil(err)
+		a.ElementsMatch([]int64{id1, id2}, deleted)
 		all, err := employeeRepository.FindAll(ctx)
 		a.Nil(err)
 		a.Empty(all)
 		clearDatabase()
 	})
+
+	t.Run("restore and purge deleted employee", func(t *testing.T) {
+		empId := fixture.MustEmployee("To Restore")
+		ctx := context.Background()
+		a.Nil(employeeRepository.DeleteById(ctx, empId))
+
+		all, err := employeeRepository.FindAllWithDeleted(ctx)
+		a.Nil(err)
+		a.Len(all, 1)
+		a.NotNil(all[0].DeletedAt)
+
+		restored, err := employeeRepository.Restore(ctx, empId, time.Now())
+		a.Nil(err)
+		a.Nil(restored.DeletedAt)
+		_, err = employeeRepository.FindById(ctx, empId)
+		a.Nil(err)
+
+		a.Nil(employeeRepository.DeleteById(ctx, empId))
+		purged, err := employeeRepository.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+		a.Nil(err)
+		a.Equal(int64(1), purged)
+		all, err = employeeRepository.FindAllWithDeleted(ctx)
+		a.Nil(err)
+		a.Empty(all)
+		clearDatabase()
+	})
+
+	t.Run("search employees by prefix and misspelled name", func(t *testing.T) {
+		ivan := fixture.MustEmployee("Ivan Petrov")
+		fixture.MustEmployee("Maria Sidorova")
+		ctx := context.Background()
+
+		got, err := employeeRepository.Search(ctx, "ivan:* & pet:*", "ivan pet", 10)
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(ivan, got[0].Id)
+		a.Contains(got[0].Highlight, "<mark>Ivan</mark>")
+
+		got, err = employeeRepository.Search(ctx, "ivna:*", "ivna", 10)
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(ivan, got[0].Id)
+		clearDatabase()
+	})
 }
@@ -33,14 +33,154 @@ func (f *Fixture) setupDatabase() error {
 			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
 			name TEXT NOT NULL,
 			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now(),
+			version BIGINT NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMPTZ,
+			description TEXT NOT NULL DEFAULT '',
+			owner_id BIGINT,
+			risk_level TEXT NOT NULL DEFAULT 'low',
+			requestable BOOLEAN NOT NULL DEFAULT false,
+			application TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE TABLE IF NOT EXISTS org_unit (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL,
+			parent_id BIGINT REFERENCES org_unit (id) ON DELETE RESTRICT,
+			created_at TIMESTAMPTZ DEFAULT now(),
 			updated_at TIMESTAMPTZ DEFAULT now()
 		)`,
 		`CREATE TABLE IF NOT EXISTS employee (
 			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
 			name TEXT NOT NULL,
 			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now(),
+			version BIGINT NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMPTZ,
+			email TEXT NOT NULL DEFAULT '',
+			login TEXT NOT NULL DEFAULT '',
+			employee_number TEXT NOT NULL DEFAULT '',
+			first_name TEXT NOT NULL DEFAULT '',
+			last_name TEXT NOT NULL DEFAULT '',
+			job_title TEXT NOT NULL DEFAULT '',
+			phone TEXT NOT NULL DEFAULT '',
+			hire_date DATE,
+			termination_date DATE,
+			status TEXT NOT NULL DEFAULT 'active',
+			org_unit_id BIGINT REFERENCES org_unit (id) ON DELETE RESTRICT,
+			manager_id BIGINT REFERENCES employee (id) ON DELETE SET NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS employee_role (
+			employee_id BIGINT NOT NULL REFERENCES employee (id) ON DELETE CASCADE,
+			role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			valid_from TIMESTAMPTZ,
+			valid_until TIMESTAMPTZ,
+			PRIMARY KEY (employee_id, role_id),
+			CHECK (valid_until > valid_from)
+		)`,
+		`CREATE TABLE IF NOT EXISTS api_key (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			roles TEXT[] NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			revoked_at TIMESTAMPTZ
+		)`,
+		`CREATE TABLE IF NOT EXISTS permission (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			description TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT now(),
 			updated_at TIMESTAMPTZ DEFAULT now()
 		)`,
+		`CREATE TABLE IF NOT EXISTS role_permission (
+			role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			permission_id BIGINT NOT NULL REFERENCES permission (id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			PRIMARY KEY (role_id, permission_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS role_parent (
+			role_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			parent_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ DEFAULT now(),
+			PRIMARY KEY (role_id, parent_id),
+			CHECK (role_id <> parent_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			actor TEXT NOT NULL,
+			actor_name TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			route TEXT NOT NULL,
+			target_type TEXT NOT NULL,
+			target_id BIGINT,
+			before_state JSONB,
+			after_state JSONB,
+			request_body JSONB,
+			request_id TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE TABLE IF NOT EXISTS sod_rule (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			role_a_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			role_b_id BIGINT NOT NULL REFERENCES role (id) ON DELETE CASCADE,
+			mode TEXT NOT NULL DEFAULT 'prevent' CHECK (mode IN ('prevent', 'detect')),
+			created_at TIMESTAMPTZ DEFAULT now(),
+			updated_at TIMESTAMPTZ DEFAULT now(),
+			CHECK (role_a_id < role_b_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS certification_campaign (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL,
+			scope_type TEXT NOT NULL CHECK (scope_type IN ('role', 'org_unit')),
+			scope_id BIGINT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+			launched_by TEXT NOT NULL DEFAULT '',
+			closed_by TEXT NOT NULL DEFAULT '',
+			summary JSONB,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			closed_at TIMESTAMPTZ
+		)`,
+		`CREATE TABLE IF NOT EXISTS certification_item (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			campaign_id BIGINT NOT NULL REFERENCES certification_campaign (id) ON DELETE CASCADE,
+			employee_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+			employee_name TEXT NOT NULL,
+			role_id BIGINT REFERENCES role (id) ON DELETE SET NULL,
+			role_name TEXT NOT NULL,
+			assigned_at TIMESTAMPTZ,
+			valid_until TIMESTAMPTZ,
+			decision TEXT CHECK (decision IN ('keep', 'revoke')),
+			comment TEXT NOT NULL DEFAULT '',
+			reviewed_by TEXT NOT NULL DEFAULT '',
+			reviewed_at TIMESTAMPTZ,
+			UNIQUE (campaign_id, employee_id, role_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS access_request (
+			id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			requester_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+			employee_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+			role_id BIGINT REFERENCES role (id) ON DELETE SET NULL,
+			justification TEXT NOT NULL DEFAULT '',
+			valid_until TIMESTAMPTZ,
+			approver_id BIGINT REFERENCES employee (id) ON DELETE SET NULL,
+			status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+			decided_by TEXT NOT NULL DEFAULT '',
+			decision_reason TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			decided_at TIMESTAMPTZ
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS employee_name_lower_key ON employee (lower(name))`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS role_name_lower_key ON role (lower(name))`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS employee_login_lower_key ON employee (lower(login)) WHERE login <> ''`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS sod_rule_name_lower_key ON sod_rule (lower(name))`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS sod_rule_roles_key ON sod_rule (role_a_id, role_b_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS access_request_pending_key ON access_request (employee_id, role_id) WHERE status = 'pending'`,
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`CREATE INDEX IF NOT EXISTS employee_name_trgm_idx ON employee USING gin (name gin_trgm_ops)`,
 	}
 	for _, q := range tables {
 		if _, err := f.db.Exec(q); err != nil {
@@ -86,7 +226,7 @@ func (f *Fixture) ClearTable(table string) error {
 // Employee — создаёт сотрудника, возвращает ID
 func (f *Fixture) Employee(name string) (int64, error) {
 	now := time.Now()
-	e := employee.Entity{Name: name, CreatedAt: now, UpdatedAt: now}
+	e := employee.Entity{Name: name, Status: employee.StatusActive, CreatedAt: now, UpdatedAt: now}
 	ctx := context.Background()
 	if err := f.employees.Add(ctx, &e); err != nil {
 		return 0, err
@@ -97,7 +237,7 @@ func (f *Fixture) Employee(name string) (int64, error) {
 // Role — создаёт роль, возвращает ID
 func (f *Fixture) Role(name string) (int64, error) {
 	now := time.Now()
-	r := role.Entity{Name: name, CreatedAt: now, UpdatedAt: now}
+	r := role.Entity{Name: name, CreatedAt: now, UpdatedAt: now, RiskLevel: role.RiskLow}
 	if err := f.roles.Add(context.Background(), &r); err != nil {
 		return 0, err
 	}
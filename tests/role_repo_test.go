@@ -87,11 +87,54 @@ func TestRoleRepository(t *testing.T) {
 	t.Run("delete roles by ids", func(t *testing.T) {
 		id1 := fixture.MustRole("ToDelete1")
 		id2 := fixture.MustRole("ToDelete2")
-		err := roleRepository.DeleteByIds(context.Background(), []int64{id1, id2})
+		deleted, err := roleRepository.DeleteByIds(context.Background(), []int64{id1, id2})
 		a.Nil(err)
+		a.ElementsMatch([]int64{id1, id2}, deleted)
 		got, err := roleRepository.FindAll(context.Background())
 		a.Nil(err)
 		a.Len(got, 0)
 		clearDatabase()
 	})
+	t.Run("find page of roles sorted by name", func(t *testing.T) {
+		fixture.MustRole("Payroll")
+		fixture.MustRole("accounting")
+		fixture.MustRole("Payments")
+		req := role.PageRequest{PageSize: 2, PageNumber: 0, TextFilter: "pay"}
+		got, err := roleRepository.FindPage(context.Background(), req)
+		a.Nil(err)
+		a.Len(got, 2)
+		a.Equal("Payments", got[0].Name)
+		a.Equal("Payroll", got[1].Name)
+		total, err := roleRepository.CountAll(context.Background(), req)
+		a.Nil(err)
+		a.Equal(int64(2), total)
+		clearDatabase()
+	})
+
+	t.Run("find page of roles sorted by created_at desc", func(t *testing.T) {
+		fixture.MustRole("First")
+		last := fixture.MustRole("Second")
+		req := role.PageRequest{PageSize: 1, SortBy: "created_at", SortOrder: "desc"}
+		got, err := roleRepository.FindPage(context.Background(), req)
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(last, got[0].Id)
+		clearDatabase()
+	})
+
+	t.Run("find roles after id", func(t *testing.T) {
+		first := fixture.MustRole("Payroll")
+		fixture.MustRole("Accounting")
+		second := fixture.MustRole("Payments")
+		req := role.PageRequest{TextFilter: "pay"}
+		got, err := roleRepository.FindAfter(context.Background(), req, 0, 1)
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(first, got[0].Id)
+		got, err = roleRepository.FindAfter(context.Background(), req, first, 10)
+		a.Nil(err)
+		a.Len(got, 1)
+		a.Equal(second, got[0].Id)
+		clearDatabase()
+	})
 }
@@ -26,7 +26,7 @@ func setupTestApp(db *sqlx.DB) *fiber.App {
 	employeeService := employee.NewService(employeeRepo, vld)
 
 	// Создаем сервер через тот же метод, что и в production
-	server := web.NewServer(logger)
+	server := web.NewServer(logger, web.NewAuthConfig(cfg))
 
 	employeeController := employee.NewController(server, employeeService, logger)
 	employeeController.RegisterRoutes()